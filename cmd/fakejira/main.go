@@ -0,0 +1,37 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+
+	log "github.com/tuannvm/jira-a2a/internal/logging"
+
+	"github.com/tuannvm/jira-a2a/internal/jira/jiratest"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8090", "Address to listen on")
+	fixturesPath := flag.String("fixtures", "", "Fixture JSON file (defaults to the bundled fixtures)")
+	flag.Parse()
+
+	fixtures := jiratest.DefaultFixtures()
+	if *fixturesPath != "" {
+		var err error
+		fixtures, err = jiratest.LoadFixtures(*fixturesPath)
+		if err != nil {
+			log.Fatalf("Failed to load fixtures: %v", err)
+		}
+	}
+
+	srv := jiratest.NewServer(fixtures)
+	srv.OnWrite = func(w jiratest.Write) {
+		log.Infof("Recorded write: %s %s %s", w.Method, w.Path, string(w.Body))
+	}
+
+	fmt.Printf("Fake Jira server listening on http://%s\n", *addr)
+	fmt.Printf("Point the agents at it with JIRA_BASE_URL=http://%s\n", *addr)
+	if err := http.ListenAndServe(*addr, srv); err != nil {
+		log.Fatalf("Fake Jira server error: %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	log "github.com/tuannvm/jira-a2a/internal/logging"
+
+	"github.com/tuannvm/jira-a2a/internal/agents"
+
+	"github.com/tuannvm/jira-a2a/internal/config"
+)
+
+// runBackfill analyzes existing tickets selected by a JQL query.
+// It expects InformationGatheringAgent to be running, except in export-only mode.
+func runBackfill(args []string) {
+	fs := flag.NewFlagSet("backfill", flag.ExitOnError)
+	jql := fs.String("jql", "", "JQL query selecting the tickets to analyze (required)")
+	mode := fs.String("mode", string(agents.BackfillModeComment), "Output mode: comment, no-comment or export-only")
+	concurrency := fs.Int("concurrency", agents.DefaultBackfillConcurrency, "Maximum number of tickets analyzed in parallel")
+	pageSize := fs.Int("page-size", agents.DefaultBackfillPageSize, "Number of tickets requested per search page")
+	limit := fs.Int("limit", 0, "Maximum number of tickets to analyze (0 = no limit)")
+	stateFile := fs.String("state", ".backfill-state", "File recording processed tickets, used to resume an interrupted run")
+	exportFile := fs.String("output", "", "JSON Lines file receiving the analysis results, or the ticket context in export-only mode")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s backfill -jql <query> [options]\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+
+	opts := agents.BackfillOptions{
+		JQL:         *jql,
+		Mode:        agents.BackfillMode(*mode),
+		Concurrency: *concurrency,
+		PageSize:    *pageSize,
+		Limit:       *limit,
+		StateFile:   *stateFile,
+		ExportFile:  *exportFile,
+	}
+	if err := opts.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid backfill options: %v\n", err)
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	// Set agent name for configuration
+	config.GetViper().Set("agent_name", config.JiraRetrievalAgentName)
+	cfg := config.NewConfig()
+	agent := agents.NewJiraRetrievalAgent(cfg)
+
+	// Stop queuing new tickets on SIGINT or SIGTERM; the state file allows resuming later
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log.Infof("Starting backfill (mode: %s, concurrency: %d)", opts.Mode, opts.Concurrency)
+	report, err := agent.Backfill(ctx, opts)
+	if report != nil {
+		fmt.Printf("Backfill finished: %d matched, %d skipped, %d processed, %d failed\n",
+			report.Matched, report.Skipped, report.Processed, report.Failed)
+	}
+	if err != nil {
+		log.Fatalf("Backfill stopped: %v", err)
+	}
+}
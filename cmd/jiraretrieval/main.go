@@ -25,6 +25,10 @@ func main() {
 		// Show the webhook simulation example
 		fmt.Println("For webhook testing, use: curl -X POST -H \"Content-Type: application/json\" -d '{\"ticketId\":\"PROJ-123\",\"event\":\"created\"}' http://localhost:8081/webhook")
 		return
+	} else if len(os.Args) > 1 && os.Args[1] == "backfill" {
+		// Analyze existing tickets selected by JQL
+		runBackfill(os.Args[2:])
+		return
 	}
 
 	// Set agent name for configuration
@@ -54,7 +58,21 @@ func main() {
 		log.Fatalf("Failed to setup A2A server: %v", err)
 	}
 	// Setup HTTP server for Jira webhooks
-	agent.SetupHTTPServer()
+	if err := agent.SetupHTTPServer(); err != nil {
+		log.Fatalf("Failed to setup HTTP server: %v", err)
+	}
+
+	// Register webhooks in Jira and keep them refreshed until shutdown
+	webhooksDone := make(chan struct{})
+	go func() {
+		defer close(webhooksDone)
+		if !cfg.WebhookAutoRegister {
+			return
+		}
+		if err := agent.ManageWebhooks(ctx); err != nil {
+			log.Errorf("Webhook auto-registration failed: %v", err)
+		}
+	}()
 
 	// Start both servers concurrently
 	go func() {
@@ -62,9 +80,10 @@ func main() {
 			log.Fatalf("A2A server error: %v", err)
 		}
 	}()
-	if err := agent.StartHTTPServer(); err != nil {
+	if err := agent.StartHTTPServer(ctx); err != nil {
 		log.Fatalf("HTTP server error: %v", err)
 	}
+	<-webhooksDone
 
 	log.Infof("Server shutdown complete")
 }
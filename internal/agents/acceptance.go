@@ -0,0 +1,245 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tuannvm/jira-a2a/internal/common"
+	"github.com/tuannvm/jira-a2a/internal/llm"
+	"github.com/tuannvm/jira-a2a/internal/models"
+	"github.com/tuannvm/jira-a2a/internal/prompts"
+	"github.com/tuannvm/jira-a2a/internal/quality"
+	"github.com/tuannvm/jira-a2a/internal/redaction"
+	"github.com/tuannvm/jira-a2a/internal/schema"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// acceptanceToolName is the tool the LLM calls to return drafted acceptance criteria.
+const acceptanceToolName = "record_acceptance_criteria"
+
+// Acceptance prompt tuning, as percentages of the prompt budget each section may use.
+const (
+	acceptanceDescriptionShare = 40
+	acceptanceCommentsShare    = 20
+	acceptanceHierarchyShare   = 10
+	acceptanceLinksShare       = 10
+)
+
+// acceptanceResponse is the LLM's answer to an acceptance prompt.
+type acceptanceResponse struct {
+	Criteria []struct {
+		Title string `json:"Title"`
+		Given string `json:"Given"`
+		When  string `json:"When"`
+		Then  string `json:"Then"`
+	} `json:"Criteria"`
+	TestCases []struct {
+		Title string `json:"Title"`
+		Kind  string `json:"Kind"`
+	} `json:"TestCases"`
+	Assumptions []string `json:"Assumptions"`
+}
+
+// processAcceptance runs the draft-acceptance-criteria skill, returning the drafted criteria
+// and test checklist as the task's artifact. The skill needs the LLM.
+func (a *InformationGatheringAgent) processAcceptance(ctx context.Context, taskID string, task *models.TicketAvailableTask, handle taskmanager.TaskHandle) error {
+	if a.llmClient == nil {
+		return fmt.Errorf("drafting acceptance criteria for task %s requires the LLM, which is disabled", taskID)
+	}
+	log.Infof("Drafting acceptance criteria for ticket %s (Task ID: %s)", task.TicketID, taskID)
+	ctx = llm.WithTicket(ctx, a.llmTicket(task))
+	project := strings.Split(task.TicketID, "-")[0]
+	tmpl := a.prompts.Select(prompts.KindAcceptance, project, task.IssueType)
+	reportProgress(handle, taskID, "Drafting acceptance criteria...")
+	criteria, err := a.draftAcceptance(ctx, task, tmpl, a.redactor.NewSession())
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to draft acceptance criteria for task %s: %v", taskID, err)
+		log.Error(errMsg)
+		return errors.New(errMsg)
+	}
+	log.Infof("Drafted %d acceptance criteria and %d test cases for ticket %s", len(criteria.Criteria), len(criteria.TestCases), task.TicketID)
+	return sendArtifact(taskID, handle, "AcceptanceCriteria", "Drafted Given/When/Then acceptance criteria and test checklist",
+		criteria, "Completed acceptance criteria draft.")
+}
+
+// draftAcceptance asks the LLM for acceptance criteria and test cases for the redacted
+// ticket and its linked context, and restores the redacted values in its answer.
+func (a *InformationGatheringAgent) draftAcceptance(ctx context.Context, task *models.TicketAvailableTask, tmpl *prompts.Template, session *redaction.Session) (*models.AcceptanceCriteria, error) {
+	ticket := promptTicket(task, session)
+	budget := a.promptBudget()
+	ticket.Description = a.tokens.Truncate(ticket.Description, budget*acceptanceDescriptionShare/100)
+	prompt, err := tmpl.Execute(prompts.AcceptanceData{
+		Ticket:      ticket,
+		Comments:    a.tokens.Truncate(formatComments(ticket.HumanComments()), budget*acceptanceCommentsShare/100),
+		Hierarchy:   a.tokens.Truncate(formatHierarchy(ticket.Hierarchy), budget*acceptanceHierarchyShare/100),
+		RemoteLinks: a.tokens.Truncate(formatRemoteLinks(ticket.RemoteLinks), budget*acceptanceLinksShare/100),
+		Schema:      string(schema.AcceptanceJSON()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	answer, err := a.completeAnalysis(ctx, prompt, acceptanceToolName, schema.AcceptanceJSON())
+	if err != nil {
+		return nil, fmt.Errorf("LLM acceptance criteria completion failed: %w", err)
+	}
+	var response acceptanceResponse
+	if err := decodeStructured(answer, schema.Acceptance(), &response); err != nil {
+		return nil, fmt.Errorf("LLM acceptance criteria: %w", err)
+	}
+
+	result := &models.AcceptanceCriteria{
+		TicketID:  task.TicketID,
+		Criteria:  []models.AcceptanceCriterion{},
+		TestCases: []models.TestCase{},
+		Model:     strings.Join(llm.ModelsUsed(ctx), ", "),
+		Prompts:   []models.PromptRef{tmpl.Ref()},
+		DraftedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	for _, c := range response.Criteria {
+		result.Criteria = append(result.Criteria, models.AcceptanceCriterion{
+			Title: session.Restore(c.Title),
+			Given: session.Restore(c.Given),
+			When:  session.Restore(c.When),
+			Then:  session.Restore(c.Then),
+		})
+	}
+	for _, t := range response.TestCases {
+		result.TestCases = append(result.TestCases, models.TestCase{Title: session.Restore(t.Title), Kind: t.Kind})
+	}
+	for _, assumption := range response.Assumptions {
+		result.Assumptions = append(result.Assumptions, session.Restore(assumption))
+	}
+	return result, nil
+}
+
+// hasAcceptanceCriteria reports whether a quality assessment found acceptance criteria.
+// The assessment sent with the analysis is only there when QUALITY_ENABLED is set, so the
+// checklist patterns are also evaluated locally.
+func hasAcceptanceCriteria(assessment *models.QualityAssessment) bool {
+	if assessment == nil {
+		return false
+	}
+	for _, check := range assessment.Checks {
+		if check.ID == "acceptance-criteria" && check.Present {
+			return true
+		}
+	}
+	return false
+}
+
+// applyAcceptanceCriteria drafts acceptance criteria for tickets of the configured issue types
+// and posts them as a comment or writes them into the configured field. Comments are posted for
+// new tickets only, and the field is written only while it is empty, so criteria the team wrote
+// are never replaced. Tickets whose description already has acceptance criteria are skipped.
+func (j *JiraRetrievalAgent) applyAcceptanceCriteria(ctx context.Context, task *models.TicketAvailableTask, jobID string, infoTask *models.InfoGatheredTask) {
+	mode := j.cfg.JiraAcceptanceCriteria
+	if mode == "off" {
+		return
+	}
+	issueTypes := splitList(j.cfg.JiraAcceptanceCriteriaIssueTypes)
+	if !containsFold(issueTypes, "*") && !containsFold(issueTypes, task.IssueType) {
+		return
+	}
+	if hasAcceptanceCriteria(infoTask.Quality) || hasAcceptanceCriteria(quality.Evaluate(task, quality.Checklist(j.qualityChecklists, task.IssueType))) {
+		log.Infof("Ticket %s already has acceptance criteria, not drafting any", task.TicketID)
+		return
+	}
+	switch mode {
+	case "comment":
+		if task.Event != "created" {
+			return
+		}
+	case "field":
+		value, err := j.jiraClient.GetFieldValue(task.TicketID, j.cfg.JiraAcceptanceCriteriaField)
+		if err != nil {
+			log.Errorf("Failed to read acceptance criteria field of ticket %s: %v", task.TicketID, err)
+			return
+		}
+		if text, ok := value.(string); value != nil && (!ok || strings.TrimSpace(text) != "") {
+			log.Debugf("Acceptance criteria field of ticket %s is already filled in", task.TicketID)
+			return
+		}
+	}
+
+	j.jobs.Progress(jobID, "Drafting acceptance criteria")
+	criteria, err := j.requestAcceptanceCriteria(ctx, task, jobID)
+	if err != nil {
+		log.Errorf("Failed to draft acceptance criteria for ticket %s: %v", task.TicketID, err)
+		return
+	}
+	if len(criteria.Criteria) == 0 {
+		log.Infof("No acceptance criteria drafted for ticket %s", task.TicketID)
+		return
+	}
+	text := formatAcceptanceCriteria(criteria)
+	if mode == "field" {
+		if err := j.jiraClient.SetFields(task.TicketID, map[string]interface{}{j.cfg.JiraAcceptanceCriteriaField: text}); err != nil {
+			log.Errorf("Failed to write acceptance criteria to ticket %s: %v", task.TicketID, err)
+		} else {
+			log.Infof("Wrote %d drafted acceptance criteria to field %s of ticket %s", len(criteria.Criteria), j.cfg.JiraAcceptanceCriteriaField, task.TicketID)
+		}
+		return
+	}
+	comment := "*Draft Acceptance Criteria*\n\n" + text + "\n\n_Drafted automatically from the ticket; please review before relying on them._"
+	visibility, err := j.commentVisibility(task.TicketID)
+	if err != nil {
+		log.Errorf("Not posting acceptance criteria for ticket %s: %v", task.TicketID, err)
+	} else if cmt, err := j.jiraClient.PostComment(task.TicketID, comment, visibility); err != nil {
+		log.Errorf("Failed to post acceptance criteria for ticket %s: %v", task.TicketID, err)
+	} else {
+		log.Infof("Posted drafted acceptance criteria for ticket %s (URL: %s)", task.TicketID, cmt.URL)
+	}
+}
+
+// requestAcceptanceCriteria runs the draft-acceptance-criteria skill of InformationGatheringAgent
+// for a ticket. Its progress is recorded on the job.
+func (j *JiraRetrievalAgent) requestAcceptanceCriteria(ctx context.Context, task *models.TicketAvailableTask, jobID string) (*models.AcceptanceCriteria, error) {
+	ticket := *task
+	ticket.Previous = nil
+	msg := newTicketTaskMessage(ticket)
+	msg.Metadata = map[string]interface{}{skillMetadataKey: skillDraftAcceptance}
+	params := protocol.SendTaskParams{ID: uuid.New().String(), Message: msg}
+	log.Infof("Requesting acceptance criteria for ticket %s (Task ID: %s)", task.TicketID, params.ID)
+	respMsg, err := common.StreamTask(ctx, j.infoAgentClient, params, j.streamTimeout(), func(u common.TaskUpdate) {
+		if u.Message != "" && u.State == protocol.TaskStateWorking {
+			j.jobs.Progress(jobID, u.Message)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("SendTaskSubscribe RPC failed: %w", err)
+	}
+	var criteria models.AcceptanceCriteria
+	if err := common.ExtractAcceptanceCriteria(&respMsg, &criteria); err != nil {
+		return nil, fmt.Errorf("failed to extract AcceptanceCriteria: %w", err)
+	}
+	return &criteria, nil
+}
+
+// formatAcceptanceCriteria formats drafted acceptance criteria and test cases as Jira wiki markup.
+func formatAcceptanceCriteria(criteria *models.AcceptanceCriteria) string {
+	var sb strings.Builder
+	for i, c := range criteria.Criteria {
+		sb.WriteString(fmt.Sprintf("*AC%d: %s*\n", i+1, c.Title))
+		sb.WriteString(fmt.Sprintf("*Given* %s\n*When* %s\n*Then* %s\n\n", c.Given, c.When, c.Then))
+	}
+	if len(criteria.TestCases) > 0 {
+		sb.WriteString("*Test Checklist:*\n")
+		for _, t := range criteria.TestCases {
+			sb.WriteString(fmt.Sprintf("- [%s] %s\n", t.Kind, t.Title))
+		}
+	}
+	if len(criteria.Assumptions) > 0 {
+		sb.WriteString("\n*Assumptions:*\n")
+		for _, a := range criteria.Assumptions {
+			sb.WriteString("- " + a + "\n")
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
@@ -0,0 +1,62 @@
+package agents
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tuannvm/jira-a2a/internal/common"
+	"github.com/tuannvm/jira-a2a/internal/config"
+	"github.com/tuannvm/jira-a2a/internal/models"
+	"github.com/tuannvm/jira-a2a/internal/quality"
+)
+
+func TestApplyAcceptanceCriteriaSkipsTicketsWithCriteria(t *testing.T) {
+	agent, server := newWebhookTestAgent(t)
+	agent.jobs = newJobTracker()
+	agent.qualityChecklists = quality.DefaultChecklists
+	agent.cfg.JiraAcceptanceCriteria = "comment"
+	agent.cfg.JiraAcceptanceCriteriaIssueTypes = "Story"
+
+	task := &models.TicketAvailableTask{
+		TicketID:    "PROJ-3",
+		IssueType:   "Story",
+		Event:       "created",
+		Description: "As a user I want to sign in with SSO so that I need one password.\n\nGiven an SSO account, when I sign in, then I see my dashboard.",
+	}
+	// No quality assessment was sent, and the agent has no A2A client to draft criteria with
+	agent.applyAcceptanceCriteria(context.Background(), task, "", &models.InfoGatheredTask{TicketID: "PROJ-3"})
+
+	if writes := server.Writes(); len(writes) != 0 {
+		t.Errorf("acceptance criteria written for a ticket that has them: %+v", writes)
+	}
+}
+
+func TestApplyAcceptanceCriteriaIgnoresAgentComments(t *testing.T) {
+	agent, _ := newWebhookTestAgent(t)
+	agent.jobs = newJobTracker()
+	agent.qualityChecklists = quality.DefaultChecklists
+	agent.cfg.JiraAcceptanceCriteria = "comment"
+	agent.cfg.JiraAcceptanceCriteriaIssueTypes = "Story"
+	// Nothing listens here, so the draft request fails once it is made
+	client, err := common.SetupA2AClient(&config.Config{}, "http://127.0.0.1:1")
+	if err != nil {
+		t.Fatalf("SetupA2AClient: %v", err)
+	}
+	agent.infoAgentClient = client
+
+	task := &models.TicketAvailableTask{
+		TicketID:    "PROJ-3",
+		IssueType:   "Story",
+		Event:       "created",
+		Description: "As a user I want to sign in with SSO so that I need one password.",
+		Comments: []models.JiraComment{
+			{Body: "*Draft Acceptance Criteria*\n1. Given an SSO account, when I sign in, then I see my dashboard", ByAgent: true},
+		},
+	}
+	agent.jobs.Start("job-1", task.TicketID, "Posting analysis to Jira")
+	agent.applyAcceptanceCriteria(context.Background(), task, "job-1", &models.InfoGatheredTask{TicketID: "PROJ-3"})
+
+	if job, _ := agent.jobs.Get("job-1"); job.Stage != "Drafting acceptance criteria" {
+		t.Errorf("job stage = %q, want acceptance criteria to be drafted", job.Stage)
+	}
+}
@@ -0,0 +1,278 @@
+package agents
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/tuannvm/jira-a2a/internal/jira"
+	"github.com/tuannvm/jira-a2a/internal/models"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// BackfillMode controls what a backfill run does with each ticket.
+type BackfillMode string
+
+// Supported backfill modes
+const (
+	// BackfillModeComment posts the analysis as a Jira comment and applies the configured
+	// issue property and transitions, like the webhook flow does.
+	BackfillModeComment BackfillMode = "comment"
+	// BackfillModeNoComment runs the analysis without writing anything back to Jira.
+	BackfillModeNoComment BackfillMode = "no-comment"
+	// BackfillModeExportOnly writes the ticket context collected from Jira to the export
+	// file without analyzing it, e.g. to build an evaluation data set.
+	BackfillModeExportOnly BackfillMode = "export-only"
+)
+
+// Default backfill settings
+const (
+	DefaultBackfillConcurrency = 4
+	DefaultBackfillPageSize    = 50
+)
+
+// BackfillOptions configures a backfill run.
+type BackfillOptions struct {
+	JQL         string       // Query selecting the tickets to analyze
+	Mode        BackfillMode // What to do with each result
+	Concurrency int          // Maximum number of tickets analyzed in parallel
+	PageSize    int          // Number of keys requested per search page
+	Limit       int          // Maximum number of tickets to analyze (0 = no limit)
+	StateFile   string       // Keys already processed, one per line; used to resume an interrupted run
+	ExportFile  string       // JSON Lines file receiving every result (required for export-only)
+}
+
+// BackfillReport summarizes a backfill run.
+type BackfillReport struct {
+	Matched   int `json:"matched"`   // Tickets returned by the search
+	Skipped   int `json:"skipped"`   // Tickets already processed in a previous run
+	Processed int `json:"processed"` // Tickets analyzed successfully
+	Failed    int `json:"failed"`    // Tickets whose analysis or output failed
+}
+
+// Validate checks the options and fills in defaults.
+func (o *BackfillOptions) Validate() error {
+	if strings.TrimSpace(o.JQL) == "" {
+		return errors.New("a JQL query is required")
+	}
+	switch o.Mode {
+	case "":
+		o.Mode = BackfillModeComment
+	case BackfillModeComment, BackfillModeNoComment:
+	case BackfillModeExportOnly:
+		if o.ExportFile == "" {
+			return errors.New("export-only mode requires an export file")
+		}
+	default:
+		return fmt.Errorf("unsupported backfill mode: %s", o.Mode)
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = DefaultBackfillConcurrency
+	}
+	if o.PageSize <= 0 {
+		o.PageSize = DefaultBackfillPageSize
+	}
+	return nil
+}
+
+// Backfill analyzes existing tickets matching a JQL query through InformationGatheringAgent,
+// or in export-only mode exports their context without analyzing them. Keys are paged from
+// the Jira search API and processed by a bounded pool of workers. Every completed key is
+// appended to the state file, so a rerun with the same state file skips tickets that were
+// already handled.
+func (j *JiraRetrievalAgent) Backfill(ctx context.Context, opts BackfillOptions) (*BackfillReport, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	done, err := loadBackfillState(opts.StateFile)
+	if err != nil {
+		return nil, err
+	}
+	if len(done) > 0 {
+		log.Infof("Resuming backfill, %d tickets already processed", len(done))
+	}
+
+	state, err := openAppend(opts.StateFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open backfill state file: %w", err)
+	}
+	defer closeQuietly(state)
+
+	export, err := openAppend(opts.ExportFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open backfill export file: %w", err)
+	}
+	defer closeQuietly(export)
+
+	report := &BackfillReport{}
+	var mu sync.Mutex // guards report, state and export
+
+	keys := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for key := range keys {
+				exportOnly := opts.Mode == BackfillModeExportOnly
+				task, infoTask, err := j.backfillTicket(ctx, key, !exportOnly)
+				if err == nil && export != nil {
+					var record interface{} = infoTask
+					if exportOnly {
+						record = task
+					}
+					mu.Lock()
+					err = writeJSONLine(export, record)
+					mu.Unlock()
+				}
+				if err == nil && opts.Mode == BackfillModeComment {
+					// Cached and unchanged analyses are skipped, so a rerun does not repeat comments
+					err = j.postAnalysis(ctx, task, "", infoTask)
+				}
+
+				mu.Lock()
+				if err != nil {
+					log.Errorf("Backfill failed for ticket %s: %v", key, err)
+					report.Failed++
+				} else {
+					log.Infof("Backfill processed ticket %s", key)
+					report.Processed++
+					if state != nil {
+						if _, werr := fmt.Fprintln(state, key); werr != nil {
+							log.Warnf("Failed to record backfill state for ticket %s: %v", key, werr)
+						}
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	searchErr := j.produceBackfillKeys(ctx, opts, done, keys, report, &mu)
+	close(keys)
+	wg.Wait()
+
+	if searchErr != nil {
+		return report, searchErr
+	}
+	return report, ctx.Err()
+}
+
+// produceBackfillKeys pages through the search results and feeds unprocessed keys to the workers.
+func (j *JiraRetrievalAgent) produceBackfillKeys(ctx context.Context, opts BackfillOptions, done map[string]bool,
+	keys chan<- string, report *BackfillReport, mu *sync.Mutex) error {
+	queued := 0
+	token := ""
+	for {
+		page, err := j.jiraClient.SearchTickets(opts.JQL, token, opts.PageSize)
+		if err != nil {
+			return fmt.Errorf("backfill search failed: %w", err)
+		}
+		for _, key := range page.Keys {
+			mu.Lock()
+			report.Matched++
+			if done[key] {
+				report.Skipped++
+				mu.Unlock()
+				continue
+			}
+			mu.Unlock()
+
+			if opts.Limit > 0 && queued >= opts.Limit {
+				return nil
+			}
+			select {
+			case keys <- key:
+				queued++
+			case <-ctx.Done():
+				return nil
+			}
+		}
+		if page.NextPageToken == "" || len(page.Keys) == 0 {
+			return nil
+		}
+		token = page.NextPageToken
+	}
+}
+
+// backfillTicket fetches a single ticket and, when analyze is set, runs it through
+// InformationGatheringAgent. It returns the ticket context along with the analysis, if any.
+func (j *JiraRetrievalAgent) backfillTicket(ctx context.Context, key string, analyze bool) (*models.TicketAvailableTask, *models.InfoGatheredTask, error) {
+	ticket, err := j.jiraClient.GetTicket(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("jira API fetch failed: %w", err)
+	}
+	webReq := &jira.WebhookRequest{
+		TicketID:   ticket.Key,
+		Event:      "backfill",
+		ProjectKey: strings.Split(ticket.Key, "-")[0],
+	}
+	task := j.collectTicketTask(ticket, webReq)
+	if !analyze {
+		return &task, nil, nil
+	}
+	params := protocol.SendTaskParams{ID: uuid.New().String(), Message: newTicketTaskMessage(task)}
+	infoTask, err := j.requestAnalysis(ctx, key, params)
+	return &task, infoTask, err
+}
+
+// loadBackfillState reads the keys recorded by a previous run.
+func loadBackfillState(path string) (map[string]bool, error) {
+	done := make(map[string]bool)
+	if path == "" {
+		return done, nil
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return done, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backfill state file: %w", err)
+	}
+	defer closeQuietly(f)
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if key := strings.TrimSpace(scanner.Text()); key != "" {
+			done[key] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read backfill state file: %w", err)
+	}
+	return done, nil
+}
+
+// openAppend opens a file for appending, returning nil when no path is configured.
+func openAppend(path string) (*os.File, error) {
+	if path == "" {
+		return nil, nil
+	}
+	return os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+}
+
+// writeJSONLine appends v to f as a single JSON line.
+func writeJSONLine(f *os.File, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode export record: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write export record: %w", err)
+	}
+	return nil
+}
+
+func closeQuietly(f *os.File) {
+	if f != nil {
+		_ = f.Close()
+	}
+}
@@ -0,0 +1,51 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tuannvm/jira-a2a/internal/models"
+)
+
+func TestBackfillExportOnly(t *testing.T) {
+	agent := newRetrievalTestAgent(t)
+	dir := t.TempDir()
+	opts := BackfillOptions{
+		JQL:        "key in (PROJ-2, PROJ-3)",
+		Mode:       BackfillModeExportOnly,
+		StateFile:  filepath.Join(dir, "state"),
+		ExportFile: filepath.Join(dir, "export.jsonl"),
+	}
+
+	// The agent has no A2A client, so an analysis request would fail the tickets
+	report, err := agent.Backfill(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("Backfill: %v", err)
+	}
+	if report.Matched != 2 || report.Processed != 2 || report.Failed != 0 {
+		t.Errorf("got report %+v", report)
+	}
+
+	data, err := os.ReadFile(opts.ExportFile)
+	if err != nil {
+		t.Fatalf("reading export: %v", err)
+	}
+	keys := map[string]bool{}
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var task models.TicketAvailableTask
+		if err := json.Unmarshal([]byte(line), &task); err != nil {
+			t.Fatalf("decoding export line %q: %v", line, err)
+		}
+		if task.Summary == "" {
+			t.Errorf("exported ticket %s has no context: %+v", task.TicketID, task)
+		}
+		keys[task.TicketID] = true
+	}
+	if !keys["PROJ-2"] || !keys["PROJ-3"] || len(keys) != 2 {
+		t.Errorf("exported tickets %v, want PROJ-2 and PROJ-3", keys)
+	}
+}
@@ -0,0 +1,152 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tuannvm/jira-a2a/internal/models"
+	"github.com/tuannvm/jira-a2a/internal/prompts"
+	"github.com/tuannvm/jira-a2a/internal/redaction"
+	"github.com/tuannvm/jira-a2a/internal/tokens"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+)
+
+// Prompt budget tuning.
+const (
+	// contextHeadroom is the percentage of the context window prompts may fill, leaving room
+	// for tokenizer differences between the counter and the provider
+	contextHeadroom = 95
+	// maxCondenseRounds bounds how often condensed chunks are condensed again before truncating
+	maxCondenseRounds = 3
+	// minCondensedWords keeps chunk summaries from being asked to fit into a handful of words
+	minCondensedWords = 50
+)
+
+// budgetSection is a part of the analysis prompt that can be shortened to fit the budget.
+type budgetSection struct {
+	name   string
+	text   *string
+	weight int
+}
+
+// promptTicket returns the ticket to render into a prompt, redacted when a session is given.
+// It is always a copy, so sections shortened to fit the prompt do not leak into the rest of the task.
+func promptTicket(task *models.TicketAvailableTask, session *redaction.Session) *models.TicketAvailableTask {
+	ticket := session.RedactTask(task)
+	if ticket == task {
+		copied := *task
+		ticket = &copied
+	}
+	return ticket
+}
+
+// fitAnalysisPrompt shortens the sections of the analysis prompt data so the rendered
+// prompt and the model's reply fit into the context window. Sections smaller than their
+// share are kept in full; oversized ones are condensed by the LLM chunk by chunk
+// (map-reduce), or truncated when that is disabled or fails.
+func (a *InformationGatheringAgent) fitAnalysisPrompt(ctx context.Context, data *prompts.AnalysisData, tmpl *prompts.Template) error {
+	ticket := promptTicket(data.Ticket, nil)
+	data.Ticket = ticket
+	sections := []budgetSection{
+		{name: "Description", text: &ticket.Description, weight: 4},
+		{name: "Comments", text: &data.Comments, weight: 3},
+		{name: "Recent Changes", text: &ticket.Changes, weight: 1},
+		{name: "Issue Hierarchy", text: &data.Hierarchy, weight: 1},
+		{name: "Remote Links", text: &data.RemoteLinks, weight: 1},
+		{name: "Development", text: &data.Development, weight: 1},
+		{name: "Stack Traces and Error Logs", text: &data.Diagnostics, weight: 2},
+		{name: "Attachments", text: &data.Attachments, weight: 2},
+	}
+
+	// Measure the prompt without the sections to find what is left for them
+	texts := make([]string, len(sections))
+	for i, s := range sections {
+		texts[i], *s.text = *s.text, ""
+	}
+	skeleton, err := tmpl.Execute(*data)
+	if err != nil {
+		return err
+	}
+	budget := a.promptBudget() - a.tokens.Count(skeleton)
+	if budget <= 0 {
+		return fmt.Errorf("prompt template %s leaves no room for the ticket in a %d token context window", tmpl.Name, a.contextWindow)
+	}
+
+	needs := make([]tokens.Section, len(sections))
+	total := 0
+	for i, s := range sections {
+		*s.text = texts[i]
+		needs[i] = tokens.Section{Name: s.name, Tokens: a.tokens.Count(texts[i]), Weight: s.weight}
+		total += needs[i].Tokens
+	}
+	if total <= budget {
+		return nil
+	}
+
+	log.Infof("Ticket %s needs %d tokens for a budget of %d, shortening oversized sections", ticket.TicketID, total, budget)
+	for i, limit := range tokens.Allocate(needs, budget) {
+		if needs[i].Tokens > limit {
+			*sections[i].text = a.condense(ctx, data, sections[i].name, texts[i], limit)
+		}
+	}
+	return nil
+}
+
+// promptBudget returns the tokens available for a prompt after reserving room for the reply.
+func (a *InformationGatheringAgent) promptBudget() int {
+	return a.contextWindow*contextHeadroom/100 - a.config.LLMMaxTokens
+}
+
+// condense shortens a prompt section to at most limit tokens. The text is split into chunks
+// that fit the context window, each chunk is condensed by the LLM (map), and the results
+// are joined (reduce); if they are still too long, they are condensed again.
+func (a *InformationGatheringAgent) condense(ctx context.Context, data *prompts.AnalysisData, section, text string, limit int) string {
+	if !a.config.LLMMapReduce {
+		return a.tokens.Truncate(text, limit)
+	}
+	project := strings.Split(data.Ticket.TicketID, "-")[0]
+	tmpl := a.prompts.Select(prompts.KindCondense, project, data.Ticket.IssueType)
+
+	skeleton, err := tmpl.Execute(prompts.CondenseData{Ticket: data.Ticket, Section: section, Part: 1, Parts: 1})
+	if err != nil {
+		log.Warnf("Failed to render condense prompt for ticket %s, truncating %s: %v", data.Ticket.TicketID, section, err)
+		return a.tokens.Truncate(text, limit)
+	}
+	chunkTokens := a.promptBudget() - a.tokens.Count(skeleton)
+	if chunkTokens <= 0 {
+		return a.tokens.Truncate(text, limit)
+	}
+
+	for round := 1; round <= maxCondenseRounds; round++ {
+		chunks := a.tokens.Split(text, chunkTokens)
+		// Words run at about three quarters of a token each
+		maxWords := max(limit/len(chunks)*3/4, minCondensedWords)
+		parts := make([]string, 0, len(chunks))
+		for i, chunk := range chunks {
+			prompt, err := tmpl.Execute(prompts.CondenseData{
+				Ticket:   data.Ticket,
+				Section:  section,
+				Part:     i + 1,
+				Parts:    len(chunks),
+				Text:     chunk,
+				MaxWords: maxWords,
+			})
+			if err == nil {
+				var condensed string
+				condensed, err = a.llmClient.Complete(ctx, prompt)
+				parts = append(parts, strings.TrimSpace(condensed))
+			}
+			if err != nil {
+				log.Warnf("Failed to condense %s of ticket %s, truncating it: %v", section, data.Ticket.TicketID, err)
+				return a.tokens.Truncate(text, limit)
+			}
+		}
+		text = strings.Join(parts, "\n")
+		log.Infof("Condensed %s of ticket %s from %d chunk(s) in round %d", section, data.Ticket.TicketID, len(chunks), round)
+		if a.tokens.Count(text) <= limit {
+			return text
+		}
+	}
+	return a.tokens.Truncate(text, limit)
+}
@@ -0,0 +1,50 @@
+package agents
+
+import (
+	"fmt"
+
+	"github.com/tuannvm/jira-a2a/internal/config"
+	"github.com/tuannvm/jira-a2a/internal/jira"
+)
+
+// serviceDeskProjectType is the Jira project type of Jira Service Management projects
+const serviceDeskProjectType = "service_desk"
+
+// commentVisibility decides who may see the analysis comment on a ticket.
+// The first configured rule matching the ticket's project and issue type wins;
+// without a match, comments on service desk tickets are internal notes so customers
+// do not see them, and comments elsewhere are unrestricted. When the ticket's project
+// cannot be fetched it returns an error, and callers do not post the comment, since
+// it may be a service desk ticket whose customers would see it.
+func (j *JiraRetrievalAgent) commentVisibility(ticketID string) (*jira.ClientJiraCommentVisibility, error) {
+	meta, err := j.jiraClient.GetIssueMeta(ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine comment visibility: %w", err)
+	}
+
+	if rule := matchVisibilityRule(j.visibilityRules, meta); rule != nil {
+		if rule.Public {
+			return nil, nil
+		}
+		return &jira.ClientJiraCommentVisibility{Type: rule.Type, Value: rule.Value, Internal: rule.Internal}, nil
+	}
+	if meta.ProjectType == serviceDeskProjectType {
+		return &jira.ClientJiraCommentVisibility{Internal: true}, nil
+	}
+	return nil, nil
+}
+
+// matchVisibilityRule returns the first rule matching the ticket's project and issue type.
+func matchVisibilityRule(rules []config.CommentVisibilityRule, meta *jira.ClientJiraIssueMeta) *config.CommentVisibilityRule {
+	for i := range rules {
+		rule := &rules[i]
+		if len(rule.Projects) > 0 && !containsFold(rule.Projects, meta.ProjectKey) {
+			continue
+		}
+		if len(rule.IssueTypes) > 0 && !containsFold(rule.IssueTypes, meta.IssueType) {
+			continue
+		}
+		return rule
+	}
+	return nil
+}
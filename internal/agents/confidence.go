@@ -0,0 +1,244 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/tuannvm/jira-a2a/internal/common"
+	"github.com/tuannvm/jira-a2a/internal/llm"
+	"github.com/tuannvm/jira-a2a/internal/models"
+	"github.com/tuannvm/jira-a2a/internal/schema"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+)
+
+// measureConfidence rates each field of an LLM analysis by the model's own assessment in its
+// response and, when samples are configured, by how similarly repeated samples of the same
+// prompt answer it and by the probability of the tokens that produced it.
+func (a *InformationGatheringAgent) measureConfidence(ctx context.Context, prompt, response string, result *models.Analysis) map[string]models.FieldConfidence {
+	var samples []*models.Analysis
+	var tokenProbs []map[string]float64
+	if n := a.config.LLMConfidenceSamples; n > 0 {
+		sampleCtx := llm.WithTemperature(ctx, a.config.LLMConfidenceTemperature)
+		for i := 1; i <= n; i++ {
+			sampleResponse, probs, err := a.sampleAnalysis(sampleCtx, prompt)
+			if err != nil {
+				log.Warnf("Failed to sample analysis %d/%d, measuring confidence without it: %v", i, n, err)
+				continue
+			}
+			sample, problems := a.parseLLMResponse(sampleResponse)
+			if len(problems) > 0 {
+				log.Debugf("Ignoring sampled analysis %d/%d failing validation: %s", i, n, formatProblems(problems, "; "))
+				continue
+			}
+			samples = append(samples, sample)
+			if probs != nil {
+				tokenProbs = append(tokenProbs, probs)
+			}
+		}
+	}
+	return combineConfidence(result, selfAssessment(response), averageScores(tokenProbs), agreement(result, samples))
+}
+
+// sampleAnalysis asks the LLM for another analysis, with token probabilities per field when
+// they are enabled and the provider returns them.
+func (a *InformationGatheringAgent) sampleAnalysis(ctx context.Context, prompt string) (string, map[string]float64, error) {
+	if completer, ok := a.llmClient.(llm.LogProbCompleter); ok && a.config.LLMConfidenceLogProbs {
+		response, tokens, err := completer.CompleteWithLogProbs(ctx, prompt)
+		if err == nil {
+			return response, tokenConfidence(response, tokens), nil
+		}
+		if ctx.Err() != nil {
+			return "", nil, err
+		}
+		if errors.Is(err, llm.ErrLogProbsUnsupported) {
+			log.Debugf("Token log probabilities unavailable (%v), sampling without them", err)
+		} else {
+			log.Warnf("Failed to get token log probabilities, sampling without them: %v", err)
+		}
+	}
+	response, err := a.completeAnalysis(ctx, prompt, analysisToolName, schema.AnalysisJSON())
+	return response, nil, err
+}
+
+// selfAssessment returns the confidence the model reported for each field of its analysis.
+// Scores given as percentages are scaled down.
+func selfAssessment(response string) map[string]float64 {
+	jsonStr, err := common.ExtractJSON(response)
+	if err != nil {
+		return nil
+	}
+	var raw interface{}
+	if err := json.Unmarshal([]byte(jsonStr), &raw); err != nil {
+		return nil
+	}
+	object, _ := schema.Analysis().Coerce(raw).(map[string]interface{})
+	reported, _ := object["Confidence"].(map[string]interface{})
+	scores := make(map[string]float64, len(reported))
+	for name, v := range reported {
+		score, ok := v.(float64)
+		if !ok || score < 0 {
+			continue
+		}
+		if score > 1 {
+			score /= 100
+		}
+		scores[name] = math.Min(score, 1)
+	}
+	return scores
+}
+
+// tokenConfidence returns, for each field of the JSON analysis in a completion, the geometric
+// mean probability of the tokens that produced its value.
+func tokenConfidence(completion string, tokens []llm.TokenLogProb) map[string]float64 {
+	jsonStr, err := common.ExtractJSON(completion)
+	if err != nil {
+		return nil
+	}
+	base := strings.Index(completion, jsonStr)
+	if base < 0 {
+		return nil
+	}
+
+	// Locate each top-level value in the completion
+	type span struct{ start, end int }
+	spans := make(map[string]span)
+	dec := json.NewDecoder(strings.NewReader(jsonStr))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return nil
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil
+		}
+		start := int(dec.InputOffset())
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil && !errors.Is(err, io.EOF) {
+			return nil
+		}
+		if name, ok := key.(string); ok && name != "Confidence" {
+			spans[name] = span{base + start, base + int(dec.InputOffset())}
+		}
+	}
+
+	scores := make(map[string]float64, len(spans))
+	for name, s := range spans {
+		sum, n, offset := 0.0, 0, 0
+		for _, t := range tokens {
+			if offset < s.end && offset+len(t.Token) > s.start {
+				sum += t.LogProb
+				n++
+			}
+			offset += len(t.Token)
+		}
+		if n > 0 {
+			scores[name] = math.Exp(sum / float64(n))
+		}
+	}
+	return scores
+}
+
+// agreement returns, for each field of an analysis, the average similarity of its value
+// to the value in each sample.
+func agreement(result *models.Analysis, samples []*models.Analysis) map[string]float64 {
+	if len(samples) == 0 {
+		return nil
+	}
+	scores := make(map[string]float64)
+	for _, f := range result.Fields() {
+		total := 0.0
+		for _, sample := range samples {
+			value, _ := sample.Field(f.Name)
+			total += wordSimilarity(f.Value, value)
+		}
+		scores[f.Name] = total / float64(len(samples))
+	}
+	return scores
+}
+
+// wordSimilarity is the Jaccard similarity of the words of two values, so that enum values
+// must match exactly while lists and free text may differ in order and wording.
+func wordSimilarity(a, b string) float64 {
+	words := func(s string) map[string]bool {
+		set := make(map[string]bool)
+		for _, w := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			set[w] = true
+		}
+		return set
+	}
+	wa, wb := words(a), words(b)
+	if len(wa) == 0 && len(wb) == 0 {
+		return 1
+	}
+	shared := 0
+	for w := range wa {
+		if wb[w] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(wa)+len(wb)-shared)
+}
+
+// averageScores averages per-field scores over several samples.
+func averageScores(samples []map[string]float64) map[string]float64 {
+	if len(samples) == 0 {
+		return nil
+	}
+	sums := make(map[string]float64)
+	counts := make(map[string]int)
+	for _, scores := range samples {
+		for name, score := range scores {
+			sums[name] += score
+			counts[name]++
+		}
+	}
+	for name := range sums {
+		sums[name] /= float64(counts[name])
+	}
+	return sums
+}
+
+// combineConfidence returns the confidence of each analysis field with at least one signal,
+// scored as the average of its signals.
+func combineConfidence(result *models.Analysis, self, tokenProbs, agreements map[string]float64) map[string]models.FieldConfidence {
+	confidence := make(map[string]models.FieldConfidence)
+	for _, f := range result.Fields() {
+		var c models.FieldConfidence
+		var signals []float64
+		add := func(scores map[string]float64, dst **float64) {
+			if score, ok := scores[f.Name]; ok {
+				score = roundScore(score)
+				*dst = &score
+				signals = append(signals, score)
+			}
+		}
+		add(self, &c.SelfAssessed)
+		add(tokenProbs, &c.TokenProb)
+		add(agreements, &c.Agreement)
+		if len(signals) == 0 {
+			continue
+		}
+		sum := 0.0
+		for _, s := range signals {
+			sum += s
+		}
+		c.Score = roundScore(sum / float64(len(signals)))
+		confidence[f.Name] = c
+	}
+	if len(confidence) == 0 {
+		return nil
+	}
+	return confidence
+}
+
+// roundScore rounds a score to two decimals.
+func roundScore(score float64) float64 {
+	return math.Round(score*100) / 100
+}
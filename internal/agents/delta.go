@@ -0,0 +1,217 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tuannvm/jira-a2a/internal/heuristics"
+	"github.com/tuannvm/jira-a2a/internal/llm"
+	"github.com/tuannvm/jira-a2a/internal/models"
+	"github.com/tuannvm/jira-a2a/internal/prompts"
+	"github.com/tuannvm/jira-a2a/internal/redaction"
+	"github.com/tuannvm/jira-a2a/internal/schema"
+	"github.com/tuannvm/jira-a2a/internal/stacktrace"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+)
+
+// deltaToolName is the tool the LLM calls to return the changes to a previous analysis.
+const deltaToolName = "record_analysis_changes"
+
+// heuristicChangeReason is the reason given for delta changes not proposed by the LLM.
+const heuristicChangeReason = "detected by local heuristics"
+
+// Delta prompt tuning.
+const (
+	// deltaComments is the number of latest comments shown in the delta prompt
+	deltaComments = 5
+	// deltaDescriptionShare is the percentage of the prompt budget the description may use
+	deltaDescriptionShare = 50
+	// deltaCommentsShare is the percentage of the prompt budget the comments may use
+	deltaCommentsShare = 25
+)
+
+// deltaResponse is the LLM's answer to a delta prompt.
+type deltaResponse struct {
+	Changes []deltaChange `json:"Changes"`
+	Summary string        `json:"Summary"`
+}
+
+// deltaChange is a change the LLM proposes to one analysis field.
+type deltaChange struct {
+	Field      string      `json:"Field"`
+	To         interface{} `json:"To"`
+	Reason     string      `json:"Reason"`
+	Confidence *float64    `json:"Confidence"`
+}
+
+// previousAnalysis returns the analysis to revise for an update event: the one sent by
+// JiraRetrievalAgent, or else the latest cached one. It returns nil for other events, when
+// the LLM is disabled or when no earlier LLM analysis of the ticket is known.
+func (a *InformationGatheringAgent) previousAnalysis(task *models.TicketAvailableTask) *models.InfoGatheredTask {
+	if task.Event != "updated" || a.llmClient == nil {
+		return nil
+	}
+	previous := task.Previous
+	if previous == nil && a.analysisCache != nil {
+		previous, _ = a.analysisCache.Latest(task.TicketID)
+	}
+	if previous == nil || previous.AnalysisResult == nil || previous.AnalysisResult.Source == models.AnalysisSourceHeuristic {
+		return nil
+	}
+	return previous
+}
+
+// reviseAnalysis asks the LLM how the update changes the previous analysis instead of
+// analyzing the ticket from scratch, and returns the revised analysis with its delta.
+// The previous summary is kept; the delta summary explains what changed.
+func (a *InformationGatheringAgent) reviseAnalysis(ctx context.Context, task *models.TicketAvailableTask, previous *models.InfoGatheredTask, tmpl *prompts.Template, session *redaction.Session) (*models.InfoGatheredTask, error) {
+	log.Infof("Revising analysis of ticket %s from %s", task.TicketID, previous.AnalyzedAt)
+	prompt, err := a.createDeltaPrompt(task, previous, tmpl, session)
+	if err != nil {
+		return nil, err
+	}
+	answer, err := a.completeAnalysis(ctx, prompt, deltaToolName, schema.DeltaJSON())
+	if err != nil {
+		return nil, fmt.Errorf("LLM delta completion failed: %w", err)
+	}
+	var response deltaResponse
+	if err := decodeStructured(answer, schema.Delta(), &response); err != nil {
+		return nil, fmt.Errorf("LLM delta: %w", err)
+	}
+
+	revised, applied, err := applyChanges(previous.AnalysisResult, &response)
+	if err != nil {
+		return nil, err
+	}
+	session.RestoreAnalysis(revised)
+	addSprintFlags(task, revised)
+	merged := heuristics.Merge(revised, heuristics.Analyze(task, time.Now()))
+	byHeuristics := make(map[string]bool)
+	for _, change := range diffAnalyses(revised, merged) {
+		byHeuristics[change.Field] = true
+	}
+
+	// Unchanged fields keep their confidence; changed ones take the one given with the change
+	confidence := make(map[string]models.FieldConfidence, len(previous.Confidence))
+	for name, c := range previous.Confidence {
+		confidence[name] = c
+	}
+	delta := &models.AnalysisDelta{Since: previous.AnalyzedAt, Summary: session.Restore(response.Summary)}
+	for _, change := range diffAnalyses(previous.AnalysisResult, merged) {
+		delete(confidence, change.Field)
+		proposed, byLLM := applied[change.Field]
+		if !byLLM || byHeuristics[change.Field] {
+			// Set by the heuristics or the sprint flags rather than proposed by the LLM
+			change.Reason = heuristicChangeReason
+			delta.Changes = append(delta.Changes, change)
+			continue
+		}
+		change.Reason = session.Restore(proposed.Reason)
+		delta.Changes = append(delta.Changes, change)
+		if proposed.Confidence != nil {
+			score := roundScore(min(max(*proposed.Confidence, 0), 1))
+			confidence[change.Field] = models.FieldConfidence{Score: score, SelfAssessed: &score}
+		}
+	}
+	if len(confidence) == 0 {
+		confidence = nil
+	}
+	log.Infof("Revised analysis of ticket %s: %d field(s) changed", task.TicketID, len(delta.Changes))
+
+	return &models.InfoGatheredTask{
+		TicketID:       task.TicketID,
+		AnalysisResult: merged,
+		Summary:        previous.Summary,
+		Model:          strings.Join(llm.ModelsUsed(ctx), ", "),
+		Prompts:        []models.PromptRef{tmpl.Ref()},
+		Diagnostics:    stacktrace.Extract(task),
+		Delta:          delta,
+		Confidence:     confidence,
+		AnalyzedAt:     time.Now().UTC().Format(time.RFC3339),
+	}, nil
+}
+
+// createDeltaPrompt renders the delta prompt with the redacted ticket, changes and previous
+// analysis. The description and comments are truncated to their share of the prompt budget.
+func (a *InformationGatheringAgent) createDeltaPrompt(task *models.TicketAvailableTask, previous *models.InfoGatheredTask, tmpl *prompts.Template, session *redaction.Session) (string, error) {
+	ticket := promptTicket(task, session)
+	comments := ticket.Comments
+	if len(comments) > deltaComments {
+		comments = comments[len(comments)-deltaComments:]
+	}
+	budget := a.promptBudget()
+	ticket.Description = a.tokens.Truncate(ticket.Description, budget*deltaDescriptionShare/100)
+
+	return tmpl.Execute(prompts.DeltaData{
+		Ticket:     ticket,
+		Comments:   a.tokens.Truncate(formatComments(comments), budget*deltaCommentsShare/100),
+		Previous:   session.RedactFields(previous.AnalysisResult.Fields()),
+		PreviousAt: previous.AnalyzedAt,
+		Changes:    ticket.FieldChanges,
+		Schema:     string(schema.DeltaJSON()),
+	})
+}
+
+// applyChanges returns a copy of the previous analysis with the changed fields set, and
+// the changes applied by field. Each new value is checked against the analysis schema;
+// values that do not match are ignored.
+func applyChanges(previous *models.Analysis, response *deltaResponse) (*models.Analysis, map[string]deltaChange, error) {
+	data, err := json.Marshal(previous)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode previous analysis: %w", err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode previous analysis: %w", err)
+	}
+
+	applied := make(map[string]deltaChange)
+	properties := schema.Analysis().Properties
+	for _, change := range response.Changes {
+		property, ok := properties[change.Field]
+		if !ok {
+			continue
+		}
+		value := property.Coerce(change.To)
+		if problems := property.Validate(value); len(problems) > 0 {
+			log.Warnf("Ignoring LLM change to %s: %s", change.Field, formatProblems(problems, "; "))
+			continue
+		}
+		fields[change.Field] = value
+		applied[change.Field] = change
+	}
+
+	data, _ = json.Marshal(fields)
+	revised := &models.Analysis{}
+	if err := json.Unmarshal(data, revised); err != nil {
+		return nil, nil, fmt.Errorf("failed to apply analysis changes: %w", err)
+	}
+	revised.SchemaVersion = models.AnalysisSchemaVersion
+	return revised, applied, nil
+}
+
+// diffAnalyses lists the fields whose displayed value differs between two analyses,
+// in display order. The status note is left out.
+func diffAnalyses(before, after *models.Analysis) []models.AnalysisChange {
+	old := make(map[string]string)
+	for _, f := range before.Fields() {
+		old[f.Name] = f.Value
+	}
+	var changes []models.AnalysisChange
+	seen := make(map[string]bool)
+	for _, f := range after.Fields() {
+		seen[f.Name] = true
+		if f.Name != "Status" && old[f.Name] != f.Value {
+			changes = append(changes, models.AnalysisChange{Field: f.Name, From: old[f.Name], To: f.Value})
+		}
+	}
+	for _, f := range before.Fields() {
+		if !seen[f.Name] && f.Name != "Status" {
+			changes = append(changes, models.AnalysisChange{Field: f.Name, From: f.Value})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,189 @@
+package agents
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/tuannvm/jira-a2a/internal/config"
+	"github.com/tuannvm/jira-a2a/internal/models"
+	"github.com/tuannvm/jira-a2a/internal/prompts"
+)
+
+func newPreviousAnalysis() *models.Analysis {
+	return &models.Analysis{
+		Sentiment:       models.SentimentNeutral,
+		Urgency:         models.UrgencyMedium,
+		KeyInformation:  []string{"SSO fails"},
+		SuggestedAction: "Check the IdP",
+		EstimatedEffort: models.EffortSmall,
+		Source:          models.AnalysisSourceLLM,
+	}
+}
+
+func TestApplyChanges(t *testing.T) {
+	tests := []struct {
+		name    string
+		changes []deltaChange
+		want    map[string]string
+		applied []string
+	}{
+		{name: "no changes", want: map[string]string{"Urgency": "Medium"}},
+		{
+			name:    "valid change",
+			changes: []deltaChange{{Field: "Urgency", To: "High"}},
+			want:    map[string]string{"Urgency": "High", "Sentiment": "Neutral"},
+			applied: []string{"Urgency"},
+		},
+		{
+			name: "near misses coerced",
+			changes: []deltaChange{
+				{Field: "Urgency", To: "critical"},
+				{Field: "KeyInformation", To: "SSO fails, since the deploy"},
+				{Field: "RequiresClarification", To: "yes"},
+			},
+			want:    map[string]string{"Urgency": "Critical", "KeyInformation": "SSO fails; since the deploy", "RequiresClarification": "true"},
+			applied: []string{"KeyInformation", "RequiresClarification", "Urgency"},
+		},
+		{
+			name:    "invalid value ignored",
+			changes: []deltaChange{{Field: "Urgency", To: "Soon"}, {Field: "EstimatedEffort", To: "Large"}},
+			want:    map[string]string{"Urgency": "Medium", "EstimatedEffort": "Large"},
+			applied: []string{"EstimatedEffort"},
+		},
+		{
+			name:    "unknown field ignored",
+			changes: []deltaChange{{Field: "Color", To: "red"}},
+			want:    map[string]string{"Color": ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous := newPreviousAnalysis()
+			revised, applied, err := applyChanges(previous, &deltaResponse{Changes: tt.changes})
+			if err != nil {
+				t.Fatalf("applyChanges: %v", err)
+			}
+			for name, want := range tt.want {
+				if got, _ := revised.Field(name); got != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+			var names []string
+			for name := range applied {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			if !reflect.DeepEqual(names, tt.applied) {
+				t.Errorf("applied = %v, want %v", names, tt.applied)
+			}
+			if revised.SchemaVersion != models.AnalysisSchemaVersion {
+				t.Errorf("schema version = %d", revised.SchemaVersion)
+			}
+			if !reflect.DeepEqual(previous, newPreviousAnalysis()) {
+				t.Errorf("applyChanges modified the previous analysis: %+v", previous)
+			}
+		})
+	}
+}
+
+func TestDiffAnalyses(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(a *models.Analysis)
+		want   []models.AnalysisChange
+	}{
+		{name: "unchanged", change: func(a *models.Analysis) {}},
+		{name: "status note ignored", change: func(a *models.Analysis) { a.Status = "Revised" }},
+		{
+			name:   "changed",
+			change: func(a *models.Analysis) { a.Urgency = models.UrgencyHigh },
+			want:   []models.AnalysisChange{{Field: "Urgency", From: "Medium", To: "High"}},
+		},
+		{
+			name: "added and removed in display order",
+			change: func(a *models.Analysis) {
+				a.SuggestedAction = ""
+				a.RelatedTickets = []string{"PROJ-7", "PROJ-9"}
+				a.KeyInformation = append(a.KeyInformation, "Since the deploy")
+			},
+			want: []models.AnalysisChange{
+				{Field: "KeyInformation", From: "SSO fails", To: "SSO fails; Since the deploy"},
+				{Field: "RelatedTickets", To: "PROJ-7, PROJ-9"},
+				{Field: "SuggestedAction", From: "Check the IdP"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			after := newPreviousAnalysis()
+			tt.change(after)
+			if got := diffAnalyses(newPreviousAnalysis(), after); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffAnalyses = %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReviseAnalysis(t *testing.T) {
+	client := &scriptedLLM{responses: []string{`{"Changes": [
+		{"Field": "Urgency", "To": "High", "Reason": "Customer escalation", "Confidence": 0.9},
+		{"Field": "RelatedTickets", "To": ["PROJ-9"], "Reason": "Linked ticket"}],
+		"Summary": "Urgency raised after a customer escalation"}`}}
+	agent := newAnalysisTestAgent(t, client, &config.Config{})
+	task := &models.TicketAvailableTask{
+		TicketID:    "PROJ-2",
+		Summary:     "Login fails with SSO",
+		Description: "See PROJ-7.\n1. Open the portal\n2. Click sign in",
+		IssueType:   "Bug",
+		Event:       "updated",
+	}
+	previous := &models.InfoGatheredTask{
+		TicketID:       "PROJ-2",
+		AnalysisResult: newPreviousAnalysis(),
+		Summary:        "SSO login fails",
+		AnalyzedAt:     "2025-01-12T08:31:02Z",
+		Confidence: map[string]models.FieldConfidence{
+			"Urgency":   {Score: 0.5},
+			"Sentiment": {Score: 0.8},
+		},
+	}
+	tmpl := agent.prompts.Select(prompts.KindDelta, "PROJ", "Bug")
+
+	result, err := agent.reviseAnalysis(context.Background(), task, previous, tmpl, nil)
+	if err != nil {
+		t.Fatalf("reviseAnalysis: %v", err)
+	}
+	if result.Summary != previous.Summary || result.Delta.Since != previous.AnalyzedAt ||
+		result.Delta.Summary != "Urgency raised after a customer escalation" {
+		t.Errorf("summary = %q, delta = %+v", result.Summary, result.Delta)
+	}
+
+	changes := make(map[string]models.AnalysisChange)
+	for _, change := range result.Delta.Changes {
+		changes[change.Field] = change
+	}
+	if got, want := changes["Urgency"], (models.AnalysisChange{Field: "Urgency", From: "Medium", To: "High", Reason: "Customer escalation"}); got != want {
+		t.Errorf("urgency change = %+v, want %+v", got, want)
+	}
+	// The heuristics added PROJ-7 to the LLM's change, so the LLM's reason no longer explains it
+	if got, want := changes["RelatedTickets"], (models.AnalysisChange{Field: "RelatedTickets", To: "PROJ-9, PROJ-7", Reason: heuristicChangeReason}); got != want {
+		t.Errorf("related tickets change = %+v, want %+v", got, want)
+	}
+	for field, change := range changes {
+		if field != "Urgency" && change.Reason != heuristicChangeReason {
+			t.Errorf("%s change not proposed by the LLM has reason %q", field, change.Reason)
+		}
+	}
+
+	if c := result.Confidence["Urgency"]; c.Score != 0.9 || c.SelfAssessed == nil {
+		t.Errorf("urgency confidence = %+v, want the one given with the change", c)
+	}
+	if c := result.Confidence["Sentiment"]; c.Score != 0.8 {
+		t.Errorf("unchanged sentiment confidence = %+v, want it kept", c)
+	}
+	if _, ok := result.Confidence["RelatedTickets"]; ok {
+		t.Error("related tickets changed without a confidence kept one")
+	}
+}
@@ -8,23 +8,60 @@ import (
 	"strings"
 	"time"
 
+	"github.com/tuannvm/jira-a2a/internal/cache"
 	"github.com/tuannvm/jira-a2a/internal/common"
 	"github.com/tuannvm/jira-a2a/internal/config"
+	"github.com/tuannvm/jira-a2a/internal/heuristics"
 	"github.com/tuannvm/jira-a2a/internal/llm"
 	"github.com/tuannvm/jira-a2a/internal/models"
+	"github.com/tuannvm/jira-a2a/internal/prompts"
+	"github.com/tuannvm/jira-a2a/internal/quality"
+	"github.com/tuannvm/jira-a2a/internal/redaction"
+	"github.com/tuannvm/jira-a2a/internal/schema"
+	"github.com/tuannvm/jira-a2a/internal/similarity"
+	"github.com/tuannvm/jira-a2a/internal/stacktrace"
+	"github.com/tuannvm/jira-a2a/internal/tokens"
 	"trpc.group/trpc-go/trpc-a2a-go/log"
 	"trpc.group/trpc-go/trpc-a2a-go/protocol"
 	"trpc.group/trpc-go/trpc-a2a-go/server"
 	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
 )
 
+// analysisToolName is the tool the LLM calls to return a structured analysis.
+const analysisToolName = "record_ticket_analysis"
+
+// Skills of InformationGatheringAgent. A task runs the skill named by the "skill" metadata
+// of its message, or process-ticket-info when there is none.
+const (
+	skillMetadataKey     = "skill"
+	skillProcessTicket   = "process-ticket-info"
+	skillAssessQuality   = "assess-ticket-quality"
+	skillDraftAcceptance = "draft-acceptance-criteria"
+)
+
 // InformationGatheringAgent analyzes Jira ticket information received from JiraRetrievalAgent.
 // It uses LLM (if configured) and returns structured insights.
 // It does not interact directly with the Jira API.
 type InformationGatheringAgent struct {
 	config    *config.Config
 	llmClient llm.LLMClient
+	prompts   *prompts.Store
+	redactor  *redaction.Redactor // nil when redaction is disabled
 	server    *server.A2AServer
+
+	// Token budgeting of LLM prompts, set when the LLM is enabled
+	tokens        *tokens.Counter
+	contextWindow int
+
+	// Similar ticket detection, nil when disabled
+	embedder        similarity.Embedder
+	similarityIndex *similarity.Index
+
+	// Analyses of unchanged tickets, nil when disabled
+	analysisCache *cache.Store
+
+	// Definition-of-Ready checklists by issue type
+	qualityChecklists config.QualityChecklists
 }
 
 // NewInformationGatheringAgent creates a new InformationGatheringAgent.
@@ -40,23 +77,97 @@ func NewInformationGatheringAgent(cfg *config.Config) *InformationGatheringAgent
 		}
 	}
 
-	return &InformationGatheringAgent{
+	promptStore, err := prompts.NewStore(cfg.LLMPromptDir)
+	if err != nil {
+		log.Fatalf("Failed to load prompt templates: %v", err)
+	}
+
+	agent := &InformationGatheringAgent{
 		config:    cfg,
 		llmClient: llmClient,
+		prompts:   promptStore,
+	}
+	if llmClient != nil {
+		agent.tokens = tokens.NewCounter(cfg.LLMModel)
+		agent.contextWindow = cfg.LLMContextWindow
+		if agent.contextWindow <= 0 {
+			// Prompts must fit the smallest model any of them may be routed to
+			models := []string{cfg.LLMModel}
+			if router, ok := llmClient.(*llm.Router); ok {
+				models = router.Models()
+			}
+			for _, model := range models {
+				if window := tokens.ContextWindow(model); agent.contextWindow <= 0 || window < agent.contextWindow {
+					agent.contextWindow = window
+				}
+			}
+		}
+	}
+	if cfg.RedactionEnabled {
+		rules, err := config.LoadRedactionRules(cfg.RedactionRulesFile)
+		if err != nil {
+			log.Fatalf("Failed to load redaction rules: %v", err)
+		}
+		agent.redactor, err = redaction.New(rules)
+		if err != nil {
+			log.Fatalf("Failed to initialize redaction: %v", err)
+		}
+	}
+	if cfg.SimilarityIndexFile != "" {
+		agent.embedder, err = similarity.NewEmbedder(cfg)
+		if err != nil {
+			log.Fatalf("Failed to initialize embedder: %v", err)
+		}
+		agent.similarityIndex, err = similarity.OpenIndex(cfg.SimilarityIndexFile, agent.embedder.Name())
+		if err != nil {
+			log.Fatalf("Failed to open similarity index: %v", err)
+		}
+		log.Infof("Loaded similarity index %s with %d tickets", cfg.SimilarityIndexFile, agent.similarityIndex.Len())
 	}
+	if cfg.AnalysisCacheFile != "" {
+		agent.analysisCache, err = cache.Open(cfg.AnalysisCacheFile, time.Duration(cfg.AnalysisCacheTTL)*time.Second)
+		if err != nil {
+			log.Fatalf("Failed to open analysis cache: %v", err)
+		}
+		log.Infof("Loaded analysis cache %s with %d tickets", cfg.AnalysisCacheFile, agent.analysisCache.Len())
+	}
+	agent.qualityChecklists = quality.DefaultChecklists
+	if cfg.QualityChecklistFile != "" {
+		agent.qualityChecklists, err = config.LoadQualityChecklists(cfg.QualityChecklistFile)
+		if err != nil {
+			log.Fatalf("Failed to load quality checklists: %v", err)
+		}
+	}
+	return agent
 }
 
 // SetupAgentServer configures the A2A server for the agent.
 func (a *InformationGatheringAgent) SetupAgentServer() error {
 	skills := []server.AgentSkill{
 		{
-			ID:          "process-ticket-info",
+			ID:          skillProcessTicket,
 			Name:        "Process Ticket Information",
 			Description: common.StringPtr("Analyzes ticket information and provides insights"),
 			Tags:        []string{"analysis", "ticket"},
 			InputModes:  []string{"text", "data"},
 			OutputModes: []string{"text", "data"},
 		},
+		{
+			ID:          skillAssessQuality,
+			Name:        "Assess Ticket Quality",
+			Description: common.StringPtr("Scores a ticket against the Definition-of-Ready checklist of its issue type and asks for the missing items"),
+			Tags:        []string{"quality", "ticket"},
+			InputModes:  []string{"data"},
+			OutputModes: []string{"data"},
+		},
+		{
+			ID:          skillDraftAcceptance,
+			Name:        "Draft Acceptance Criteria",
+			Description: common.StringPtr("Drafts Given/When/Then acceptance criteria and a test checklist from a ticket and its linked context"),
+			Tags:        []string{"acceptance-criteria", "testing", "ticket"},
+			InputModes:  []string{"data"},
+			OutputModes: []string{"data"},
+		},
 	}
 
 	opts := common.SetupServerOptions{
@@ -83,6 +194,12 @@ func (a *InformationGatheringAgent) StartAgentServer(ctx context.Context) error
 	if a.server == nil {
 		return fmt.Errorf("server not setup for InformationGatheringAgent")
 	}
+	// Pick up prompt template edits without a restart
+	go func() {
+		if err := a.prompts.Watch(ctx); err != nil {
+			log.Errorf("Prompt template hot reload disabled: %v", err)
+		}
+	}()
 	return common.StartServer(ctx, a.server, a.config.ServerHost, a.config.ServerPort)
 }
 
@@ -106,23 +223,112 @@ func (a *InformationGatheringAgent) Process(ctx context.Context, taskID string,
 		return errors.New(errMsg)
 	}
 
+	switch skill := requestedSkill(message); skill {
+	case skillProcessTicket:
+	case skillAssessQuality:
+		return a.processQuality(ctx, taskID, &ticketTask, handle)
+	case skillDraftAcceptance:
+		return a.processAcceptance(ctx, taskID, &ticketTask, handle)
+	default:
+		return fmt.Errorf("unknown skill %q for task %s", skill, taskID)
+	}
+
 	log.Infof("Processing TicketAvailableTask for ticket %s (Task ID: %s)", ticketTask.TicketID, taskID)
 
+	// Select the prompt templates for the ticket's project and issue type
+	project := strings.Split(ticketTask.TicketID, "-")[0]
+	analysisPrompt := a.prompts.Select(prompts.KindAnalysis, project, ticketTask.IssueType)
+	summaryPrompt := a.prompts.Select(prompts.KindSummary, project, ticketTask.IssueType)
+	qualityPrompt := a.prompts.Select(prompts.KindQuality, project, ticketTask.IssueType)
+
+	// Route the ticket's completions and record the models that answer them
+	var llmTicket llm.Ticket
+	if a.llmClient != nil {
+		llmTicket = a.llmTicket(&ticketTask)
+		ctx = llm.WithTicket(ctx, llmTicket)
+	}
+
+	// Reuse the previous analysis when nothing it depends on has changed
+	var contentHash string
+	if a.analysisCache != nil && a.llmClient != nil {
+		versions := []string{a.routedModel(llmTicket), analysisPrompt.Version, summaryPrompt.Version,
+			fmt.Sprintf("schema-%d", models.AnalysisSchemaVersion)}
+		if a.config.QualityEnabled {
+			versions = append(versions, qualityPrompt.Version, quality.Version(quality.Checklist(a.qualityChecklists, ticketTask.IssueType)))
+		}
+		contentHash = cache.Hash(&ticketTask, versions...)
+		if cached, ok := a.analysisCache.Get(ticketTask.TicketID, contentHash); ok {
+			log.Infof("Ticket %s unchanged since its analysis at %s, returning cached analysis", ticketTask.TicketID, cached.AnalyzedAt)
+			cached.TaskID = taskID
+			cached.Cached = true
+			return a.sendResult(taskID, cached, handle)
+		}
+	}
+
+	// Personal data and secrets are replaced with placeholders in everything sent to the LLM
+	session := a.redactor.NewSession()
+
+	// Check the ticket against its Definition of Ready, whether it is revised or analyzed anew
+	var assessment *models.QualityAssessment
+	if a.config.QualityEnabled {
+		reportProgress(handle, taskID, "Checking Definition of Ready...")
+		assessment = a.assessQuality(ctx, &ticketTask, qualityPrompt, session)
+		log.Infof("Ticket %s scored %d/100 against its Definition of Ready", ticketTask.TicketID, assessment.Score)
+	}
+
+	// On updates, revise the previous analysis in light of the changes rather than starting over
+	if previous := a.previousAnalysis(&ticketTask); previous != nil {
+		reportProgress(handle, taskID, "Revising previous analysis...")
+		deltaPrompt := a.prompts.Select(prompts.KindDelta, project, ticketTask.IssueType)
+		revised, err := a.reviseAnalysis(ctx, &ticketTask, previous, deltaPrompt, session)
+		if err == nil {
+			revised.TaskID = taskID
+			revised.SimilarTickets = a.findSimilarTickets(&ticketTask, session)
+			revised.Redactions = session.Counts()
+			revised.Quality = assessment
+			if assessment != nil && assessment.Source != models.AnalysisSourceHeuristic {
+				revised.Prompts = append(revised.Prompts, qualityPrompt.Ref())
+			}
+			a.cacheResult(contentHash, revised)
+			return a.sendResult(taskID, revised, handle)
+		}
+		log.Warnf("Failed to revise analysis of ticket %s, analyzing it again: %v", ticketTask.TicketID, err)
+	}
+
 	// 2. Analyze the ticket information (using LLM if available)
-	analysisResult, err := a.analyzeTicketInfo(&ticketTask)
+	if a.llmClient != nil {
+		reportProgress(handle, taskID, "Analyzing ticket with LLM...")
+	} else {
+		reportProgress(handle, taskID, "Analyzing ticket...")
+	}
+	analysisResult, confidence, err := a.analyzeTicketInfo(ctx, &ticketTask, analysisPrompt, session)
 	if err != nil {
 		errMsg := fmt.Sprintf("failed to analyze ticket info for task %s: %v", taskID, err)
 		log.Error(errMsg)
 		return errors.New(errMsg)
 	}
 
-	// 3. Generate a summary (using LLM if available)
+	// Look for earlier tickets describing the same problem
+	if a.similarityIndex != nil {
+		reportProgress(handle, taskID, "Searching for similar tickets...")
+	}
+	similarTickets := a.findSimilarTickets(&ticketTask, session)
+
+	// 3. Generate a summary (using LLM if available and the LLM analysis succeeded)
+	usedLLM := analysisResult.Source != models.AnalysisSourceHeuristic
 	var summary string
-	if a.llmClient != nil {
-		summary, err = a.generateSummary(&ticketTask, analysisResult)
+	summaryFailed := false
+	if a.llmClient != nil && !usedLLM {
+		summary = "LLM analysis failed. No summary generated."
+	} else if a.llmClient != nil {
+		reportProgress(handle, taskID, "Summarizing analysis...")
+		stream := newSummaryStream(handle, taskID, session)
+		summary, err = a.generateSummary(ctx, &ticketTask, analysisResult, summaryPrompt, session, stream.Write)
+		stream.Flush()
 		if err != nil {
 			log.Warnf("Failed to generate LLM summary for task %s: %v", taskID, err)
 			summary = "Summary generation failed: " + err.Error()
+			summaryFailed = true
 		} else {
 			log.Infof("Generated LLM summary for task %s", taskID)
 		}
@@ -137,24 +343,94 @@ func (a *InformationGatheringAgent) Process(ctx context.Context, taskID string,
 		TicketID:       ticketTask.TicketID,
 		AnalysisResult: analysisResult,
 		Summary:        summary,
+		SimilarTickets: similarTickets,
+		Redactions:     session.Counts(),
+		Diagnostics:    stacktrace.Extract(&ticketTask),
+		Confidence:     confidence,
+		Quality:        assessment,
+		AnalyzedAt:     time.Now().UTC().Format(time.RFC3339),
+	}
+	if len(infoGatheredTask.Redactions) > 0 {
+		log.Infof("Redacted values from LLM content for ticket %s: %v", ticketTask.TicketID, infoGatheredTask.Redactions)
+	}
+	if a.llmClient != nil && usedLLM {
+		infoGatheredTask.Model = strings.Join(llm.ModelsUsed(ctx), ", ")
+		infoGatheredTask.Prompts = []models.PromptRef{analysisPrompt.Ref(), summaryPrompt.Ref()}
+	}
+	if assessment != nil && assessment.Source != models.AnalysisSourceHeuristic {
+		infoGatheredTask.Prompts = append(infoGatheredTask.Prompts, qualityPrompt.Ref())
 	}
 
-	// 5. Create the result message with InfoGatheredTask payload
+	// Only complete LLM analyses are worth caching; heuristic ones are cheap to redo
+	if usedLLM && !summaryFailed {
+		a.cacheResult(contentHash, &infoGatheredTask)
+	}
+
+	return a.sendResult(taskID, &infoGatheredTask, handle)
+}
+
+// requestedSkill returns the skill a task message asks for.
+func requestedSkill(message protocol.Message) string {
+	if skill, ok := message.Metadata[skillMetadataKey].(string); ok && skill != "" {
+		return skill
+	}
+	return skillProcessTicket
+}
+
+// cacheResult stores an analysis in the analysis cache under the ticket's content hash.
+// Nothing is stored when the cache is disabled.
+func (a *InformationGatheringAgent) cacheResult(contentHash string, result *models.InfoGatheredTask) {
+	if contentHash == "" {
+		return
+	}
+	a.analysisCache.Put(result.TicketID, contentHash, result)
+	if err := a.analysisCache.Save(); err != nil {
+		log.Errorf("Failed to save analysis cache: %v", err)
+	}
+}
+
+// llmTicket describes a ticket for LLM routing.
+func (a *InformationGatheringAgent) llmTicket(task *models.TicketAvailableTask) llm.Ticket {
+	return llm.Ticket{
+		Project:   strings.Split(task.TicketID, "-")[0],
+		IssueType: task.IssueType,
+		Priority:  task.Priority,
+		Comments:  len(task.Comments),
+		Tokens:    a.tokens.Count(task.Description) + a.tokens.Count(formatComments(task.Comments)),
+	}
+}
+
+// routedModel returns the model a ticket's completions are sent to first.
+func (a *InformationGatheringAgent) routedModel(ticket llm.Ticket) string {
+	if router, ok := a.llmClient.(*llm.Router); ok {
+		return router.ModelFor(ticket)
+	}
+	return a.config.LLMModel
+}
+
+// sendResult returns an InfoGatheredTask to the caller as the task's artifact and completes the task.
+func (a *InformationGatheringAgent) sendResult(taskID string, infoGatheredTask *models.InfoGatheredTask, handle taskmanager.TaskHandle) error {
+	return sendArtifact(taskID, handle, "InfoGatheredTask", "Generated summary and analysis", infoGatheredTask, "Completed ticket analysis.")
+}
+
+// sendArtifact returns a skill's result to the caller as the task's artifact and completes the task.
+func sendArtifact(taskID string, handle taskmanager.TaskHandle, name, description string, data interface{}, done string) error {
+	// 5. Create the result message with the payload
 	resultMessage := protocol.Message{
 		Parts: []protocol.Part{
 			&protocol.DataPart{
 				Type: protocol.PartTypeData,
-				Data: infoGatheredTask,
+				Data: data,
 			},
 		},
 	}
 
-	// 6. Send InfoGatheredTask as artifact with metadata
-	log.Infof("Adding artifact for task %s", taskID)
+	// 6. Send the payload as artifact with metadata
+	log.Infof("Adding artifact %s for task %s", name, taskID)
 	last := true
 	artifact := protocol.Artifact{
-		Name:        common.StringPtr("InfoGatheredTask"),
-		Description: common.StringPtr("Generated summary and analysis"),
+		Name:        common.StringPtr(name),
+		Description: common.StringPtr(description),
 		Index:       0,
 		Parts:       resultMessage.Parts,
 		Append:      common.BoolPtr(false),
@@ -164,11 +440,8 @@ func (a *InformationGatheringAgent) Process(ctx context.Context, taskID string,
 		log.Errorf("AddArtifact failed for task %s: %v", taskID, err)
 		return fmt.Errorf("failed to add artifact for task %s: %w", taskID, err)
 	}
-	// Allow time for artifact to be delivered before closing subscription
-	time.Sleep(100 * time.Millisecond)
-
-	// Final status completion
-	completeMsg := protocol.NewMessage(protocol.MessageRoleAgent, []protocol.Part{protocol.NewTextPart("Completed ticket analysis.")})
+	// Final status completion; events reach subscribers in order, after the artifact
+	completeMsg := protocol.NewMessage(protocol.MessageRoleAgent, []protocol.Part{protocol.NewTextPart(done)})
 	if err := handle.UpdateStatus(protocol.TaskStateCompleted, &completeMsg); err != nil {
 		log.Warnf("Failed to send completion status for task %s: %v", taskID, err)
 	}
@@ -176,146 +449,428 @@ func (a *InformationGatheringAgent) Process(ctx context.Context, taskID string,
 	return nil
 }
 
-// analyzeTicketInfo analyzes the ticket information using LLM (if available).
-func (a *InformationGatheringAgent) analyzeTicketInfo(task *models.TicketAvailableTask) (map[string]string, error) {
+// analyzeTicketInfo analyzes the ticket information using LLM (if available), merged with the
+// heuristic analysis. When the LLM is disabled or fails, the heuristic analysis is used alone.
+// The LLM sees the ticket redacted by session; the heuristics run locally on the original.
+// The confidence of the LLM's fields is returned with the analysis; fields the heuristics
+// changed have none.
+func (a *InformationGatheringAgent) analyzeTicketInfo(ctx context.Context, task *models.TicketAvailableTask, tmpl *prompts.Template, session *redaction.Session) (*models.Analysis, map[string]models.FieldConfidence, error) {
+	heuristic := heuristics.Analyze(task, time.Now())
+	addSprintFlags(task, heuristic)
 	if a.llmClient == nil {
-		log.Infof("LLM client not available, skipping analysis for ticket %s", task.TicketID)
-		return map[string]string{"status": "LLM analysis skipped (client unavailable)"}, nil
+		log.Infof("LLM client not available, using heuristic analysis for ticket %s", task.TicketID)
+		heuristic.Status = "Heuristic analysis only (LLM disabled)"
+		return heuristic, nil, nil
+	}
+
+	result, confidence, err := a.analyzeWithLLM(ctx, session.RedactTask(task), tmpl)
+	if err != nil {
+		log.Warnf("LLM analysis failed for ticket %s, using heuristic analysis: %v", task.TicketID, err)
+		heuristic.Status = "Heuristic analysis only (LLM analysis failed)"
+		return heuristic, nil, nil
+	}
+	session.RestoreAnalysis(result)
+	addSprintFlags(task, result)
+	merged := heuristics.Merge(result, heuristic)
+	for _, change := range diffAnalyses(result, merged) {
+		delete(confidence, change.Field)
 	}
+	return merged, confidence, nil
+}
 
+// analyzeWithLLM asks the LLM for an analysis and measures its confidence in each field.
+// Output that does not match the analysis schema is sent back to the model with the
+// validation errors, up to LLMRepairAttempts times.
+func (a *InformationGatheringAgent) analyzeWithLLM(ctx context.Context, task *models.TicketAvailableTask, tmpl *prompts.Template) (*models.Analysis, map[string]models.FieldConfidence, error) {
 	log.Infof("Performing LLM analysis for ticket %s", task.TicketID)
-	prompt := a.createLLMPrompt(task)
-	response, err := a.llmClient.Complete(context.Background(), prompt)
+	prompt, err := a.createLLMPrompt(ctx, task, tmpl)
+	if err != nil {
+		return nil, nil, err
+	}
+	response, err := a.completeAnalysis(ctx, prompt, analysisToolName, schema.AnalysisJSON())
+	if err != nil {
+		return nil, nil, fmt.Errorf("LLM completion failed: %w", err)
+	}
+
+	result, problems := a.parseLLMResponse(response)
+	for attempt := 1; len(problems) > 0 && attempt <= a.config.LLMRepairAttempts; attempt++ {
+		log.Warnf("LLM analysis for ticket %s failed validation (%s), repair attempt %d/%d",
+			task.TicketID, formatProblems(problems, "; "), attempt, a.config.LLMRepairAttempts)
+		response, err = a.completeAnalysis(ctx, createRepairPrompt(prompt, response, problems), analysisToolName, schema.AnalysisJSON())
+		if err != nil {
+			return nil, nil, fmt.Errorf("LLM repair completion failed: %w", err)
+		}
+		result, problems = a.parseLLMResponse(response)
+	}
+	if len(problems) > 0 {
+		return nil, nil, fmt.Errorf("LLM analysis failed schema validation: %s", formatProblems(problems, "; "))
+	}
+	result.Source = models.AnalysisSourceLLM
+	return result, a.measureConfidence(ctx, prompt, response, result), nil
+}
+
+// completeAnalysis asks the LLM for a JSON answer matching a schema, preferring structured
+// output through the named tool and falling back to a text completion when the provider
+// does not support it. The text may surround the JSON with prose; see decodeStructured.
+func (a *InformationGatheringAgent) completeAnalysis(ctx context.Context, prompt, tool string, schemaJSON []byte) (string, error) {
+	data, err := a.llmClient.CompleteStructured(ctx, prompt, tool, schemaJSON)
+	if err == nil {
+		return string(data), nil
+	}
+	if !errors.Is(err, llm.ErrStructuredOutputUnsupported) {
+		return "", err
+	}
+	log.Debugf("Structured output unavailable (%v), falling back to text completion", err)
+	return a.llmClient.Complete(ctx, prompt)
+}
+
+// decodeStructured extracts the JSON answer from an LLM response, coerces and validates it
+// against the schema and decodes it into v.
+func decodeStructured(response string, s *schema.Schema, v interface{}) error {
+	jsonStr, err := common.ExtractJSON(response)
 	if err != nil {
-		return nil, fmt.Errorf("LLM completion failed: %w", err)
+		return err
+	}
+	var raw interface{}
+	if err := json.Unmarshal([]byte(jsonStr), &raw); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
 	}
+	raw = s.Coerce(raw)
+	if problems := s.Validate(raw); len(problems) > 0 {
+		return &schemaError{problems: problems}
+	}
+	normalized, _ := json.Marshal(raw)
+	if err := json.Unmarshal(normalized, v); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	return nil
+}
 
-	return a.parseLLMResponse(response)
+// schemaError reports an LLM answer that does not match its schema.
+type schemaError struct {
+	problems []schema.ValidationError
 }
 
-// createLLMPrompt creates a prompt for the LLM based on the ticket information.
-func (a *InformationGatheringAgent) createLLMPrompt(task *models.TicketAvailableTask) string {
-	// Using a raw string literal to avoid issues with special characters
-	promptTemplate := `Analyze the following Jira ticket information and provide a structured analysis in JSON format.
+func (e *schemaError) Error() string {
+	return "failed schema validation: " + formatProblems(e.problems, "; ")
+}
 
-Ticket ID: %s
-Summary: %s
-Status: %s
-Reporter: %s
-Assignee: %s
-Priority: %s
-Labels: %s
-Created: %s
-Updated: %s
+// addSprintFlags records sprint facts that do not need the LLM to be judged,
+// such as a ticket being pulled into a sprint that has already started.
+func addSprintFlags(task *models.TicketAvailableTask, result *models.Analysis) {
+	if task.Agile == nil || task.Agile.Sprint == nil || task.Agile.Sprint.State != "active" {
+		return
+	}
+	addedMidSprint := task.Agile.AddedMidSprint
+	result.AddedMidSprint = &addedMidSprint
+	if addedMidSprint {
+		result.SprintWarning = fmt.Sprintf("Added to active sprint %q after it started (%s)", task.Agile.Sprint.Name, task.Agile.AddedToSprintAt)
+	}
+}
 
-Description:
-%s
+// createLLMPrompt renders the analysis prompt template for the ticket, shortening
+// sections that do not fit into the model's context window.
+func (a *InformationGatheringAgent) createLLMPrompt(ctx context.Context, task *models.TicketAvailableTask, tmpl *prompts.Template) (string, error) {
+	data := prompts.AnalysisData{
+		Ticket:      task,
+		Comments:    formatComments(task.Comments),
+		Hierarchy:   formatHierarchy(task.Hierarchy),
+		Sprint:      formatAgileContext(task.Agile),
+		RemoteLinks: formatRemoteLinks(task.RemoteLinks),
+		Development: formatDevelopment(task.Development),
+		Diagnostics: formatDiagnostics(stacktrace.Extract(task)),
+		Attachments: formatAttachments(task.Attachments),
+		Schema:      string(schema.AnalysisJSON()),
+	}
+	if err := a.fitAnalysisPrompt(ctx, &data, tmpl); err != nil {
+		return "", err
+	}
+	return tmpl.Execute(data)
+}
 
-Recent Changes:
-%s
+// formatRemoteLinks renders remote links for inclusion in a prompt.
+func formatRemoteLinks(links []models.RemoteLink) string {
+	if len(links) == 0 {
+		return "None"
+	}
+	var sb strings.Builder
+	for _, l := range links {
+		sb.WriteString("- ")
+		if l.Relationship != "" {
+			sb.WriteString(fmt.Sprintf("[%s] ", l.Relationship))
+		}
+		sb.WriteString(fmt.Sprintf("%s <%s>", l.Title, l.URL))
+		if l.Application != "" {
+			sb.WriteString(fmt.Sprintf(" (%s)", l.Application))
+		}
+		if l.Resolved {
+			sb.WriteString(" - resolved")
+		}
+		if l.Summary != "" {
+			sb.WriteString(fmt.Sprintf(": %s", l.Summary))
+		}
+		sb.WriteString("\n")
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// formatDevelopment renders the development panel summary for inclusion in a prompt.
+func formatDevelopment(dev *models.DevelopmentInfo) string {
+	if dev == nil {
+		return "None"
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("- Branches: %d, Commits: %d\n", dev.Branches, dev.Commits))
+	for _, pr := range dev.PullRequests {
+		sb.WriteString(fmt.Sprintf("- Pull Request (%s): %s <%s>", pr.Status, pr.Title, pr.URL))
+		if pr.Repository != "" {
+			sb.WriteString(fmt.Sprintf(" in %s", pr.Repository))
+		}
+		sb.WriteString("\n")
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
 
-Please provide a JSON object containing the following fields:
-- Sentiment: (Positive/Negative/Neutral)
-- Urgency: (Low/Medium/High/Critical)
-- KeyInformation: Bullet points summarizing the core issue or request.
-- DetectedEntities: Any important entities like product names, user IDs, error codes, etc.
-- SuggestedAction: What should be the immediate next step?
-- EstimatedEffort: (e.g., Small, Medium, Large, X-Large)
-- RelatedTickets: Any mentioned related ticket IDs.
-- RequiresClarification: (true/false) Does the description lack necessary information?
-- RecommendedLabels: Suggested labels that should be added (as a JSON array of strings).
-
-You may include additional fields that you think are relevant.
-Ensure your analysis is concise but comprehensive.
-Focus on extracting actionable insights.
+// formatDiagnostics renders parsed stack traces and error log lines for inclusion in a prompt.
+func formatDiagnostics(d *models.Diagnostics) string {
+	if d == nil {
+		return "None"
+	}
+	var sb strings.Builder
+	for _, t := range d.StackTraces {
+		sb.WriteString(fmt.Sprintf("- [%s] %s (%s", t.Language, t.Headline(), t.Source))
+		if t.Timestamp != "" {
+			sb.WriteString(", at " + t.Timestamp)
+		}
+		if t.Occurrences > 1 {
+			sb.WriteString(fmt.Sprintf(", seen %d times", t.Occurrences))
+		}
+		sb.WriteString(")\n")
+		if t.RootCause != "" {
+			sb.WriteString(fmt.Sprintf("  Root cause: %s\n", t.RootCause))
+		}
+		for _, f := range t.Frames {
+			sb.WriteString("  at " + formatFrame(f) + "\n")
+		}
+	}
+	for _, l := range d.LogLines {
+		sb.WriteString(fmt.Sprintf("- %s %s (%s", l.Level, l.Message, l.Source))
+		if l.Timestamp != "" {
+			sb.WriteString(", at " + l.Timestamp)
+		}
+		if l.Occurrences > 1 {
+			sb.WriteString(fmt.Sprintf(", seen %d times", l.Occurrences))
+		}
+		sb.WriteString(")\n")
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
 
-JSON Analysis:
-`
+// formatAttachments renders the text attachments for inclusion in a prompt.
+func formatAttachments(attachments []models.Attachment) string {
+	if len(attachments) == 0 {
+		return "None"
+	}
+	var sb strings.Builder
+	for _, a := range attachments {
+		sb.WriteString(fmt.Sprintf("--- %s ---\n%s\n", a.Filename, strings.TrimSpace(a.Content)))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
 
-	return fmt.Sprintf(promptTemplate,
-		task.TicketID,
-		task.Summary,
-		task.Status,
-		task.Reporter,
-		task.Assignee,
-		task.Priority,
-		strings.Join(task.Labels, ", "),
-		task.Created,
-		task.Updated,
-		task.Description,
-		task.Changes,
-	)
-}
-
-// parseLLMResponse parses the LLM response (expected to contain JSON) into a map.
-func (a *InformationGatheringAgent) parseLLMResponse(response string) (map[string]string, error) {
-	jsonStr, err := common.ExtractJSON(response) // Use common utility
-	if err != nil {
-		log.Warnf("Failed to extract JSON from LLM response. Response was: %s", response)
-		return nil, fmt.Errorf("failed to extract JSON from LLM response: %w", err)
-	}
-
-	var resultMap map[string]interface{}
-	if err := json.Unmarshal([]byte(jsonStr), &resultMap); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON from LLM response: %w", err)
-	}
-
-	// Convert map[string]interface{} to map[string]string for simplicity
-	stringMap := make(map[string]string)
-	for k, v := range resultMap {
-		switch value := v.(type) {
-		case string:
-			stringMap[k] = value
-		case []interface{}: // Handle array (e.g., RecommendedLabels)
-			strArr := make([]string, 0, len(value))
-			for _, item := range value {
-				if str, ok := item.(string); ok {
-					strArr = append(strArr, str)
-				}
-			}
-			stringMap[k] = strings.Join(strArr, ", ")
-		default:
-			stringMap[k] = fmt.Sprintf("%v", value)
+// formatFrame renders a stack frame as "function (file:line)".
+func formatFrame(f models.StackFrame) string {
+	location := f.File
+	if f.Line > 0 {
+		location = fmt.Sprintf("%s:%d", f.File, f.Line)
+	}
+	switch {
+	case f.Function == "":
+		return location
+	case location == "":
+		return f.Function
+	}
+	return fmt.Sprintf("%s (%s)", f.Function, location)
+}
+
+// formatAgileContext renders the sprint, board and estimate for inclusion in a prompt.
+func formatAgileContext(agile *models.AgileContext) string {
+	if agile == nil {
+		return "None"
+	}
+	var sb strings.Builder
+	if agile.Sprint == nil {
+		sb.WriteString("- Sprint: none (backlog)\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("- Sprint: %s (%s, %s to %s)\n", agile.Sprint.Name, agile.Sprint.State, agile.Sprint.StartDate, agile.Sprint.EndDate))
+		if agile.Sprint.Goal != "" {
+			sb.WriteString(fmt.Sprintf("- Sprint Goal: %s\n", agile.Sprint.Goal))
+		}
+		if agile.AddedToSprintAt != "" {
+			sb.WriteString(fmt.Sprintf("- Added To Sprint: %s (mid-sprint: %v)\n", agile.AddedToSprintAt, agile.AddedMidSprint))
 		}
 	}
+	if agile.Board != "" {
+		sb.WriteString(fmt.Sprintf("- Board: %s\n", agile.Board))
+	}
+	if agile.StoryPoints != nil {
+		sb.WriteString(fmt.Sprintf("- Story Points: %v\n", *agile.StoryPoints))
+	}
+	if agile.ClosedSprints > 0 {
+		sb.WriteString(fmt.Sprintf("- Carried Over From: %d closed sprint(s)\n", agile.ClosedSprints))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
 
-	return stringMap, nil
+// formatComments renders the ticket comments for inclusion in a prompt.
+func formatComments(comments []models.JiraComment) string {
+	if len(comments) == 0 {
+		return "None"
+	}
+	var sb strings.Builder
+	for _, c := range comments {
+		sb.WriteString(fmt.Sprintf("- %s (%s): %s\n", c.Author, c.Created, c.Body))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
 }
 
-// generateSummary generates a human-readable summary using the LLM.
-func (a *InformationGatheringAgent) generateSummary(task *models.TicketAvailableTask, analysis map[string]string) (string, error) {
-	if a.llmClient == nil {
-		return "LLM client not available for summary generation.", nil
+// formatHierarchy renders the ticket hierarchy for inclusion in a prompt.
+func formatHierarchy(h *models.TicketHierarchy) string {
+	if h == nil {
+		return "None"
 	}
+	var sb strings.Builder
+	for i, parent := range h.Parents {
+		label := "Parent"
+		if i > 0 {
+			label = "Ancestor"
+		}
+		sb.WriteString(fmt.Sprintf("- %s: %s\n", label, formatIssueRef(parent)))
+	}
+	for _, sibling := range h.Siblings {
+		sb.WriteString(fmt.Sprintf("- Sibling: %s\n", formatIssueRef(sibling)))
+	}
+	for _, child := range h.Children {
+		sb.WriteString(fmt.Sprintf("- Child: %s\n", formatIssueRef(child)))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
 
-	log.Infof("Generating LLM summary for ticket %s", task.TicketID)
-	// Convert analysis map back to a string format for the prompt
-	analysisStr := ""
-	for k, v := range analysis {
-		analysisStr += fmt.Sprintf("- %s: %s\n", common.Capitalize(k), v)
+// formatIssueRef renders a related issue as "KEY [Type] Summary (Status)".
+func formatIssueRef(ref models.IssueRef) string {
+	s := ref.Key
+	if ref.IssueType != "" {
+		s += " [" + ref.IssueType + "]"
+	}
+	if ref.Summary != "" {
+		s += " " + ref.Summary
 	}
+	if ref.Status != "" {
+		s += " (" + ref.Status + ")"
+	}
+	return s
+}
 
-	promptTemplate := `Based on the following Jira ticket and its analysis, create a concise, human-readable summary suitable for a quick overview.
+// parseLLMResponse decodes and validates the analysis in an LLM response. Problems are
+// returned instead of an error so they can be fed back to the LLM.
+func (a *InformationGatheringAgent) parseLLMResponse(response string) (*models.Analysis, []schema.ValidationError) {
+	s := schema.Analysis()
+	var raw map[string]interface{}
+	if err := decodeStructured(response, s, &raw); err != nil {
+		var invalid *schemaError
+		if errors.As(err, &invalid) {
+			return nil, invalid.problems
+		}
+		log.Warnf("Failed to decode LLM response (%v). Response was: %s", err, response)
+		return nil, []schema.ValidationError{{Path: "$", Message: err.Error()}}
+	}
 
-Ticket ID: %s
-Summary: %s
-Status: %s
+	// The schema guarantees the types line up with models.Analysis
+	data, _ := json.Marshal(raw)
+	result := &models.Analysis{}
+	if err := json.Unmarshal(data, result); err != nil {
+		return nil, []schema.ValidationError{{Path: "$", Message: err.Error()}}
+	}
+	result.SchemaVersion = models.AnalysisSchemaVersion
 
-Analysis Results:
+	// Keep any extra fields the LLM chose to include as text
+	for k, v := range raw {
+		if _, known := s.Properties[k]; known {
+			continue
+		}
+		if result.Additional == nil {
+			result.Additional = make(map[string]string)
+		}
+		result.Additional[k] = stringifyValue(v)
+	}
+	return result, nil
+}
+
+// stringifyValue renders a decoded JSON value as text, joining arrays with commas.
+func stringifyValue(v interface{}) string {
+	switch value := v.(type) {
+	case string:
+		return value
+	case []interface{}:
+		strArr := make([]string, 0, len(value))
+		for _, item := range value {
+			strArr = append(strArr, stringifyValue(item))
+		}
+		return strings.Join(strArr, ", ")
+	case map[string]interface{}:
+		data, _ := json.Marshal(value)
+		return string(data)
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+}
+
+// createRepairPrompt asks the LLM to correct a response that failed schema validation.
+func createRepairPrompt(prompt, response string, problems []schema.ValidationError) string {
+	promptTemplate := `%s%s
+
+The JSON above does not match the required schema:
 %s
-Please provide a brief summary (2-4 sentences) highlighting the main point and any critical findings or suggested actions.
 
-Summary:
+Reply with the corrected JSON object only.
+
+JSON Analysis:
 `
+	return fmt.Sprintf(promptTemplate, prompt, strings.TrimSpace(response), "- "+formatProblems(problems, "\n- "))
+}
 
-	prompt := fmt.Sprintf(promptTemplate,
-		task.TicketID,
-		task.Summary,
-		task.Status,
-		analysisStr,
-	)
+// formatProblems joins validation errors with sep.
+func formatProblems(problems []schema.ValidationError, sep string) string {
+	lines := make([]string, 0, len(problems))
+	for _, p := range problems {
+		lines = append(lines, p.Error())
+	}
+	return strings.Join(lines, sep)
+}
+
+// generateSummary generates a human-readable summary using the LLM. The prompt is redacted
+// by session and the original values are restored in the summary. When onChunk is set,
+// the summary is streamed to it as it is generated, still redacted.
+func (a *InformationGatheringAgent) generateSummary(ctx context.Context, task *models.TicketAvailableTask, analysis *models.Analysis, tmpl *prompts.Template, session *redaction.Session, onChunk func(string)) (string, error) {
+	if a.llmClient == nil {
+		return "LLM client not available for summary generation.", nil
+	}
 
-	response, err := a.llmClient.Complete(context.Background(), prompt)
+	log.Infof("Generating LLM summary for ticket %s", task.TicketID)
+	prompt, err := tmpl.Execute(prompts.SummaryData{
+		Ticket:   session.RedactTask(task),
+		Analysis: session.RedactFields(analysis.Fields()),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var response string
+	if onChunk != nil {
+		response, err = a.llmClient.CompleteStream(ctx, prompt, onChunk)
+	} else {
+		response, err = a.llmClient.Complete(ctx, prompt)
+	}
 	if err != nil {
 		return "", fmt.Errorf("LLM summary completion failed: %w", err)
 	}
@@ -326,5 +881,5 @@ Summary:
 		summary = strings.TrimSpace(strings.TrimPrefix(summary, "Summary:"))
 	}
 
-	return summary, nil
+	return session.Restore(summary), nil
 }
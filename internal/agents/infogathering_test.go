@@ -0,0 +1,104 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/tuannvm/jira-a2a/internal/config"
+	"github.com/tuannvm/jira-a2a/internal/llm"
+	"github.com/tuannvm/jira-a2a/internal/models"
+	"github.com/tuannvm/jira-a2a/internal/prompts"
+	"github.com/tuannvm/jira-a2a/internal/tokens"
+)
+
+// scriptedLLM is an LLM client answering text completions with the given responses in order.
+// Structured output is unsupported, so callers fall back to text completions.
+type scriptedLLM struct {
+	responses []string
+	prompts   []string
+}
+
+func (c *scriptedLLM) Complete(ctx context.Context, prompt string) (string, error) {
+	c.prompts = append(c.prompts, prompt)
+	if len(c.responses) == 0 {
+		return "", context.DeadlineExceeded
+	}
+	response := c.responses[0]
+	c.responses = c.responses[1:]
+	return response, nil
+}
+
+func (c *scriptedLLM) CompleteStream(ctx context.Context, prompt string, onChunk func(chunk string)) (string, error) {
+	return c.Complete(ctx, prompt)
+}
+
+func (c *scriptedLLM) CompleteStructured(ctx context.Context, prompt, name string, schema json.RawMessage) (json.RawMessage, error) {
+	return nil, llm.ErrStructuredOutputUnsupported
+}
+
+// newAnalysisTestAgent returns an agent analyzing tickets with the built-in prompts and the given LLM
+func newAnalysisTestAgent(t *testing.T, client llm.LLMClient, cfg *config.Config) *InformationGatheringAgent {
+	t.Helper()
+	store, err := prompts.NewStore("")
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	// The zero counter approximates token counts, so no tokenizer is downloaded
+	return &InformationGatheringAgent{config: cfg, llmClient: client, prompts: store, tokens: &tokens.Counter{}, contextWindow: 8192}
+}
+
+func TestAnalyzeWithLLMRepair(t *testing.T) {
+	const (
+		invalid = `Here is the analysis: {"Sentiment": "Negative", "Urgency": "Urgent", "KeyInformation": ["SSO fails"],
+			"SuggestedAction": "Check the IdP", "EstimatedEffort": "Small"}`
+		valid = `{"Sentiment": "negative", "Urgency": "High", "KeyInformation": "SSO fails, since the deploy",
+			"SuggestedAction": "Check the IdP", "EstimatedEffort": "Small", "RequiresClarification": "no", "Browser": "Firefox"}`
+	)
+	tests := []struct {
+		name      string
+		responses []string
+		attempts  int
+		wantErr   bool
+		wantCalls int
+	}{
+		{name: "valid first time", responses: []string{valid}, attempts: 1, wantCalls: 1},
+		{name: "repaired", responses: []string{invalid, valid}, attempts: 1, wantCalls: 2},
+		{name: "repair disabled", responses: []string{invalid, valid}, attempts: 0, wantErr: true, wantCalls: 1},
+		{name: "repair fails", responses: []string{invalid, invalid, invalid}, attempts: 2, wantErr: true, wantCalls: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &scriptedLLM{responses: tt.responses}
+			agent := newAnalysisTestAgent(t, client, &config.Config{LLMRepairAttempts: tt.attempts})
+			task := &models.TicketAvailableTask{TicketID: "PROJ-2", Summary: "Login fails with SSO", IssueType: "Bug"}
+			tmpl := agent.prompts.Select(prompts.KindAnalysis, "PROJ", "Bug")
+
+			result, _, err := agent.analyzeWithLLM(context.Background(), task, tmpl)
+			if len(client.prompts) != tt.wantCalls {
+				t.Errorf("LLM called %d times, want %d", len(client.prompts), tt.wantCalls)
+			}
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), `$.Urgency: must be one of`) {
+					t.Errorf("analyzeWithLLM error = %v, want the validation problem", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("analyzeWithLLM: %v", err)
+			}
+			if tt.wantCalls > 1 && !strings.Contains(client.prompts[1], "$.Urgency") {
+				t.Errorf("repair prompt does not name the problem:\n%s", client.prompts[1])
+			}
+			// Near misses are coerced and unknown fields kept as text
+			if result.Urgency != "High" || result.Sentiment != "Negative" || len(result.KeyInformation) != 2 ||
+				result.RequiresClarification || result.Additional["Browser"] != "Firefox" {
+				t.Errorf("got analysis %+v", result)
+			}
+			if result.Source != models.AnalysisSourceLLM || result.SchemaVersion != models.AnalysisSchemaVersion {
+				t.Errorf("source = %q, schema version = %d", result.Source, result.SchemaVersion)
+			}
+		})
+	}
+}
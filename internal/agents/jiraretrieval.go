@@ -3,16 +3,21 @@ package agents
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/tuannvm/jira-a2a/internal/common"
 	"github.com/tuannvm/jira-a2a/internal/config"
 	"github.com/tuannvm/jira-a2a/internal/jira"
 	"github.com/tuannvm/jira-a2a/internal/models"
+	"github.com/tuannvm/jira-a2a/internal/quality"
+	"trpc.group/trpc-go/trpc-a2a-go/auth"
 	a2aclient "trpc.group/trpc-go/trpc-a2a-go/client"
 	"trpc.group/trpc-go/trpc-a2a-go/log"
 	"trpc.group/trpc-go/trpc-a2a-go/protocol"
@@ -23,9 +28,18 @@ import (
 type JiraRetrievalAgent struct {
 	cfg             *config.Config
 	jiraClient      *jira.Client
-	infoAgentClient *a2aclient.A2AClient
-	a2aServer       *server.A2AServer
-	httpMux         *http.ServeMux
+	users           *jira.UserResolver
+	transitionRules []config.TransitionRule
+	visibilityRules []config.CommentVisibilityRule
+	// Definition-of-Ready checklists, used to tell whether a ticket has acceptance criteria
+	qualityChecklists config.QualityChecklists
+	infoAgentClient   *a2aclient.A2AClient
+	jobs              *jobTracker
+	a2aServer         *server.A2AServer
+	httpMux           *http.ServeMux
+
+	selfMu        sync.Mutex
+	selfAccountID string // Jira account the agent acts as, resolved on first use
 }
 
 // NewJiraRetrievalAgent creates a new agent for handling Jira webhooks and A2A tasks.
@@ -37,12 +51,46 @@ func NewJiraRetrievalAgent(cfg *config.Config) *JiraRetrievalAgent {
 	if err != nil {
 		log.Fatalf("Failed to create A2A client: %v", err)
 	}
+	rules, err := config.LoadTransitionRules(cfg.JiraTransitionRulesFile)
+	if err != nil {
+		log.Fatalf("Failed to load transition rules: %v", err)
+	}
+	visibilityRules, err := config.LoadCommentVisibilityRules(cfg.JiraCommentVisibilityFile)
+	if err != nil {
+		log.Fatalf("Failed to load comment visibility rules: %v", err)
+	}
+	checklists := quality.DefaultChecklists
+	if cfg.QualityChecklistFile != "" {
+		checklists, err = config.LoadQualityChecklists(cfg.QualityChecklistFile)
+		if err != nil {
+			log.Fatalf("Failed to load quality checklists: %v", err)
+		}
+	}
+	switch cfg.JiraCommentLowConfidence {
+	case "mark", "hide", "show":
+	default:
+		log.Fatalf("Unsupported JIRA_COMMENT_LOW_CONFIDENCE %q: expected mark, hide or show", cfg.JiraCommentLowConfidence)
+	}
+	switch cfg.JiraAcceptanceCriteria {
+	case "off", "comment":
+	case "field":
+		if cfg.JiraAcceptanceCriteriaField == "" {
+			log.Fatalf("JIRA_ACCEPTANCE_CRITERIA=field requires JIRA_ACCEPTANCE_CRITERIA_FIELD")
+		}
+	default:
+		log.Fatalf("Unsupported JIRA_ACCEPTANCE_CRITERIA %q: expected off, comment or field", cfg.JiraAcceptanceCriteria)
+	}
 	mux := http.NewServeMux()
 	return &JiraRetrievalAgent{
-		cfg:             cfg,
-		jiraClient:      jiraCli,
-		infoAgentClient: a2aClient,
-		httpMux:         mux,
+		cfg:               cfg,
+		jiraClient:        jiraCli,
+		users:             jira.NewUserResolver(jiraCli, time.Duration(cfg.JiraUserCacheTTL)*time.Second),
+		transitionRules:   rules,
+		visibilityRules:   visibilityRules,
+		qualityChecklists: checklists,
+		infoAgentClient:   a2aClient,
+		jobs:              newJobTracker(),
+		httpMux:           mux,
 	}
 }
 
@@ -79,16 +127,41 @@ func (j *JiraRetrievalAgent) StartA2AServer(ctx context.Context) error {
 	return common.StartServer(ctx, j.a2aServer, j.cfg.ServerHost, j.cfg.ServerPort)
 }
 
-// SetupHTTPServer registers the webhook handler.
-func (j *JiraRetrievalAgent) SetupHTTPServer() {
+// SetupHTTPServer registers the webhook and job status handlers. Job status includes the
+// analysis summary, so it requires the same authentication as the A2A server.
+func (j *JiraRetrievalAgent) SetupHTTPServer() error {
+	provider, err := common.NewAuthProvider(j.cfg.AuthType, j.cfg.JWTSecret, j.cfg.APIKey)
+	if err != nil {
+		return fmt.Errorf("failed to setup job status authentication: %w", err)
+	}
+	jobs, job := http.Handler(http.HandlerFunc(j.handleJobs)), http.Handler(http.HandlerFunc(j.handleJob))
+	if provider != nil {
+		middleware := auth.NewMiddleware(provider)
+		jobs, job = middleware.Wrap(jobs), middleware.Wrap(job)
+	}
 	j.httpMux.HandleFunc("/webhook", j.handleWebhook)
+	j.httpMux.Handle("GET /jobs", jobs)
+	j.httpMux.Handle("GET /jobs/{id}", job)
+	return nil
 }
 
-// StartHTTPServer starts an HTTP server for Jira webhook events.
-func (j *JiraRetrievalAgent) StartHTTPServer() error {
+// StartHTTPServer starts an HTTP server for Jira webhook events and shuts it down when ctx is canceled.
+func (j *JiraRetrievalAgent) StartHTTPServer(ctx context.Context) error {
 	addr := fmt.Sprintf("%s:%d", j.cfg.ServerHost, j.cfg.WebhookPort)
+	srv := &http.Server{Addr: addr, Handler: j.httpMux}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Errorf("Failed to shutdown webhook server: %v", err)
+		}
+	}()
 	log.Infof("Starting webhook server on %s", addr)
-	return http.ListenAndServe(addr, j.httpMux)
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
 }
 
 // handleWebhook processes Jira webhook requests.
@@ -119,66 +192,252 @@ func (j *JiraRetrievalAgent) handleWebhook(w http.ResponseWriter, r *http.Reques
 // ProcessWebhook fetches ticket data and forwards it to InformationGatheringAgent.
 func (j *JiraRetrievalAgent) ProcessWebhook(ctx context.Context, webReq *jira.WebhookRequest) error {
 	log.Infof("Processing Jira webhook for ticket %s, event %s", webReq.TicketID, webReq.Event)
+	// The agent's own comments, transitions and field updates would otherwise trigger another
+	// analysis. Creation is kept: a ticket the agent created still needs analyzing.
+	if webReq.Event != "created" {
+		if self := j.agentAccountID(); self != "" && webReq.UserAccountID == self {
+			log.Infof("Ignoring %s event for ticket %s triggered by the agent itself", webReq.Event, webReq.TicketID)
+			return nil
+		}
+	}
+	taskID := uuid.New().String()
+	j.jobs.Start(taskID, webReq.TicketID, "Fetching ticket context from Jira")
 	ticket, err := j.jiraClient.GetTicket(webReq.TicketID)
 	if err != nil {
 		log.Errorf("Jira API fetch failed for ticket %s: %v", webReq.TicketID, err)
 		// Use client ticket type for fallback
 		ticket = &jira.ClientJiraTicket{Key: webReq.TicketID, Summary: webReq.TicketID}
 	}
+	taskData := j.collectTicketTask(ticket, webReq)
+	msg := newTicketTaskMessage(taskData)
+	log.Infof("Sending TicketAvailableTask for ticket %s to InformationGatheringAgent", ticket.Key)
+	log.Infof("A2A client target = %s", j.infoAgentClient)
+	// Kick off event handling in background
+	params := protocol.SendTaskParams{ID: taskID, Message: msg}
+	go j.handleTicketEvents(taskData, params)
+	log.Infof("Subscribed to TicketAvailableTask for ticket %s (Task ID: %s)", ticket.Key, taskID)
+	return nil
+}
+
+// agentAccountID returns the Jira account the agent acts as. It is looked up once; while
+// the lookup fails it returns "", so events are processed rather than dropped.
+func (j *JiraRetrievalAgent) agentAccountID() string {
+	j.selfMu.Lock()
+	defer j.selfMu.Unlock()
+	if j.selfAccountID == "" {
+		user, err := j.jiraClient.GetMyself()
+		if err != nil {
+			log.Warnf("Failed to look up the Jira account of the agent: %v", err)
+			return ""
+		}
+		j.selfAccountID = user.AccountID
+	}
+	return j.selfAccountID
+}
+
+// collectTicketTask builds a TicketAvailableTask and enriches it with related context from Jira.
+// Failures to fetch optional context are logged and do not block the analysis.
+func (j *JiraRetrievalAgent) collectTicketTask(ticket *jira.ClientJiraTicket, webReq *jira.WebhookRequest) models.TicketAvailableTask {
+	taskData := buildTicketTask(ticket, webReq)
+
+	// Replace account ID mentions with readable names before analysis
+	if accountID, ok := ticket.Fields["assigneeAccountId"].(string); ok {
+		j.users.Remember(accountID, taskData.Assignee)
+	}
+	if accountID, ok := ticket.Fields["reporterAccountId"].(string); ok {
+		j.users.Remember(accountID, taskData.Reporter)
+	}
+	taskData.Description = j.users.ResolveMentions(taskData.Description)
+	self := j.agentAccountID()
+	for i := range taskData.Comments {
+		taskData.Comments[i].Body = j.users.ResolveMentions(taskData.Comments[i].Body)
+		// Comments are converted in order, so the index matches the Jira comment
+		taskData.Comments[i].ByAgent = self != "" && ticket.Comments[i].AuthorAccountID == self
+	}
+
+	if hierarchy, err := j.jiraClient.GetHierarchy(ticket.Key); err != nil {
+		log.Warnf("Failed to fetch issue hierarchy for ticket %s: %v", ticket.Key, err)
+	} else {
+		taskData.Hierarchy = toTicketHierarchy(hierarchy)
+	}
+
+	if agile, err := j.jiraClient.GetAgileContext(ticket.Key); err != nil {
+		log.Warnf("Failed to fetch sprint context for ticket %s: %v", ticket.Key, err)
+	} else {
+		taskData.Agile = toAgileContext(agile)
+	}
+
+	if links, err := j.jiraClient.GetRemoteLinks(ticket.Key); err != nil {
+		log.Warnf("Failed to fetch remote links for ticket %s: %v", ticket.Key, err)
+	} else {
+		taskData.RemoteLinks = toRemoteLinks(links)
+	}
+
+	if dev, err := j.jiraClient.GetDevelopmentInfo(ticket.ID); err != nil {
+		log.Warnf("Failed to fetch development info for ticket %s: %v", ticket.Key, err)
+	} else {
+		taskData.Development = toDevelopmentInfo(dev)
+	}
+
+	// Updates revise the previous analysis stored on the ticket
+	if webReq.Event == "updated" && j.cfg.JiraAnalysisProperty != "" {
+		var previous models.AnalysisProperty
+		if found, err := j.jiraClient.GetIssueProperty(ticket.Key, j.cfg.JiraAnalysisProperty, &previous); err != nil {
+			log.Warnf("Failed to fetch previous analysis of ticket %s: %v", ticket.Key, err)
+		} else if found && previous.AnalysisResult != nil {
+			taskData.Previous = previous.Analysis(ticket.Key)
+		}
+	}
+
+	if j.cfg.JiraAttachmentMaxBytes > 0 {
+		if attachments, err := j.jiraClient.GetTextAttachments(ticket.Key, j.cfg.JiraAttachmentMaxBytes, j.cfg.JiraAttachmentTotalMaxBytes); err != nil {
+			log.Warnf("Failed to fetch attachments for ticket %s: %v", ticket.Key, err)
+		} else {
+			taskData.Attachments = toAttachments(attachments)
+		}
+	}
+
+	return taskData
+}
+
+// buildTicketTask converts a fetched Jira ticket and the triggering webhook into a TicketAvailableTask.
+func buildTicketTask(ticket *jira.ClientJiraTicket, webReq *jira.WebhookRequest) models.TicketAvailableTask {
 	// Convert Changes map to JSON string
 	rawChanges, _ := json.Marshal(webReq.Changes)
-	taskData := models.TicketAvailableTask{
+	issueType, _ := ticket.Fields["issueType"].(string)
+	return models.TicketAvailableTask{
 		TicketID:    ticket.Key,
 		Summary:     ticket.Summary,
 		Description: ticket.Description,
 		Status:      fmt.Sprintf("%v", ticket.Fields["status"]),
+		IssueType:   issueType,
 		Reporter:    fmt.Sprintf("%v", ticket.Fields["reporter"]),
 		Assignee:    fmt.Sprintf("%v", ticket.Fields["assignee"]),
 		Priority:    fmt.Sprintf("%v", ticket.Fields["priority"]),
 		Labels:      toStringSlice(ticket.Fields["labels"]),
 		Created:     fmt.Sprintf("%v", ticket.Fields["created"]),
 		Updated:     fmt.Sprintf("%v", ticket.Fields["updated"]),
+		DueDate:     ticket.DueDate,
 		Changes:     string(rawChanges),
+		Event:       webReq.Event,
 		Metadata:    webReq.CustomFields,
+		Comments:    toComments(ticket.Comments),
+
+		FieldChanges: toFieldChanges(webReq.FieldChanges),
 	}
+}
+
+// newTicketTaskMessage wraps a TicketAvailableTask in an A2A message.
+func newTicketTaskMessage(taskData models.TicketAvailableTask) protocol.Message {
 	// Send task data as DataPart with explicit type and metadata (role must be set)
-	msg := protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{&protocol.DataPart{
+	return protocol.NewMessage(protocol.MessageRoleUser, []protocol.Part{&protocol.DataPart{
 		Type:     protocol.PartTypeData,
 		Data:     taskData,
 		Metadata: map[string]interface{}{"content-type": "application/json"},
 	}})
-	log.Infof("Sending TicketAvailableTask for ticket %s to InformationGatheringAgent", ticket.Key)
-	log.Infof("A2A client target = %s", j.infoAgentClient)
-	// Kick off event handling in background
-	taskID := uuid.New().String()
-	params := protocol.SendTaskParams{ID: taskID, Message: msg}
-	go j.handleTicketEvents(ticket.Key, params)
-	log.Infof("Subscribed to TicketAvailableTask for ticket %s (Task ID: %s)", ticket.Key, taskID)
-	return nil
 }
 
-func (j *JiraRetrievalAgent) handleTicketEvents(key string, params protocol.SendTaskParams) {
-	// Direct JSON-RPC call to InformationGatheringAgent; all artifacts returned in one shot
-	log.Infof("Invoking JSON-RPC SendTasks for ticket %s (Task ID: %s)", key, params.ID)
-	respMsg, err := common.SendTask(context.Background(), j.infoAgentClient, params)
+func (j *JiraRetrievalAgent) handleTicketEvents(taskData models.TicketAvailableTask, params protocol.SendTaskParams) {
+	key := taskData.TicketID
+	infoTask, err := j.requestAnalysis(context.Background(), key, params)
 	if err != nil {
-		log.Errorf("SendTask RPC failed for ticket %s: %v", key, err)
+		log.Errorf("Analysis failed for ticket %s: %v", key, err)
+		j.jobs.Finish(params.ID, err)
 		return
 	}
-	var infoTask models.InfoGatheredTask
-	if err := common.ExtractInfoGatheredTask(&respMsg, &infoTask); err != nil {
-		log.Errorf("Failed to extract InfoGatheredTask for ticket %s: %v", key, err)
+	// A failed comment is logged by postAnalysis and does not fail the job
+	_ = j.postAnalysis(context.Background(), &taskData, params.ID, infoTask)
+	j.jobs.Finish(params.ID, nil)
+}
+
+// postAnalysis writes an analysis back to Jira: the comment, the analysis actions and, for the
+// ticket the analysis was requested with, the drafted acceptance criteria. Cached analyses and
+// revisions that change nothing are not posted again, and revisions are posted as a follow-up.
+// task may be nil when only the analysis is known. Progress is recorded on the job jobID.
+func (j *JiraRetrievalAgent) postAnalysis(ctx context.Context, task *models.TicketAvailableTask, jobID string, infoTask *models.InfoGatheredTask) error {
+	key := infoTask.TicketID
+	// The property is written even when no comment is posted, so it reflects the latest analysis
+	j.storeAnalysisProperty(infoTask)
+	if infoTask.Cached {
+		// The ticket already has a comment with this analysis
+		log.Infof("Ticket %s unchanged since its analysis at %s, not posting a comment", key, infoTask.AnalyzedAt)
+		return nil
+	}
+	if infoTask.Delta != nil && len(infoTask.Delta.Changes) == 0 {
+		log.Infof("Update of ticket %s does not change its analysis, not posting a comment", key)
+		return nil
+	}
+	j.jobs.Progress(jobID, "Posting analysis to Jira")
+	commentText := j.formatJiraComment(infoTask)
+	if infoTask.Delta != nil {
+		commentText = j.formatDeltaComment(infoTask)
+	}
+	log.Infof("Posting Jira comment for ticket %s", key)
+	visibility, err := j.commentVisibility(key)
+	if err != nil {
+		log.Errorf("Not posting Jira comment for ticket %s: %v", key, err)
+		return err
+	}
+	cmt, err := j.jiraClient.PostComment(key, commentText, visibility)
+	if err != nil {
+		log.Errorf("Failed to post Jira comment for ticket %s: %v", key, err)
+		return fmt.Errorf("failed to post comment: %w", err)
+	}
+	log.Infof("Successfully posted Jira comment for ticket %s (URL: %s)", key, cmt.URL)
+	j.applyTransitionRules(infoTask)
+	if task != nil {
+		j.applyAcceptanceCriteria(ctx, task, jobID, infoTask)
+	}
+	return nil
+}
+
+// storeAnalysisProperty writes the analysis to the configured issue entity property, if any.
+func (j *JiraRetrievalAgent) storeAnalysisProperty(infoTask *models.InfoGatheredTask) {
+	if j.cfg.JiraAnalysisProperty == "" {
 		return
 	}
-	commentText := j.formatJiraComment(&infoTask)
-	log.Infof("Posting Jira comment for ticket %s", infoTask.TicketID)
-	if cmt, err := j.jiraClient.PostComment(infoTask.TicketID, commentText); err != nil {
-		log.Errorf("Failed to post Jira comment for ticket %s: %v", infoTask.TicketID, err)
+	property := models.NewAnalysisProperty(infoTask)
+	err := j.jiraClient.SetIssueProperty(infoTask.TicketID, j.cfg.JiraAnalysisProperty, property)
+	if errors.Is(err, jira.ErrPropertyTooLarge) && property.Summary != "" {
+		log.Warnf("Analysis property for ticket %s is too large, storing it without the summary", infoTask.TicketID)
+		property.Summary = ""
+		err = j.jiraClient.SetIssueProperty(infoTask.TicketID, j.cfg.JiraAnalysisProperty, property)
+	}
+	if err != nil {
+		log.Errorf("Failed to store analysis property for ticket %s: %v", infoTask.TicketID, err)
 	} else {
-		log.Infof("Successfully posted Jira comment for ticket %s (URL: %s)", infoTask.TicketID, cmt.URL)
+		log.Infof("Stored analysis in issue property %s for ticket %s", j.cfg.JiraAnalysisProperty, infoTask.TicketID)
 	}
 }
 
+// streamTimeout returns how long a task streamed to InformationGatheringAgent may run.
+func (j *JiraRetrievalAgent) streamTimeout() time.Duration {
+	return time.Duration(j.cfg.A2AStreamTimeout) * time.Second
+}
+
+// requestAnalysis sends a TicketAvailableTask to InformationGatheringAgent and waits for the InfoGatheredTask.
+// Progress updates streamed by the agent are recorded on the job with the task's ID.
+func (j *JiraRetrievalAgent) requestAnalysis(ctx context.Context, key string, params protocol.SendTaskParams) (*models.InfoGatheredTask, error) {
+	log.Infof("Invoking JSON-RPC SendTaskSubscribe for ticket %s (Task ID: %s)", key, params.ID)
+	j.jobs.Progress(params.ID, "Waiting for analysis")
+	respMsg, err := common.StreamTask(ctx, j.infoAgentClient, params, j.streamTimeout(), func(u common.TaskUpdate) {
+		switch {
+		case u.Partial != "":
+			j.jobs.AppendSummary(params.ID, u.Partial)
+		case u.Message != "" && u.State == protocol.TaskStateWorking:
+			j.jobs.Progress(params.ID, u.Message)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("SendTaskSubscribe RPC failed: %w", err)
+	}
+	var infoTask models.InfoGatheredTask
+	if err := common.ExtractInfoGatheredTask(&respMsg, &infoTask); err != nil {
+		return nil, fmt.Errorf("failed to extract InfoGatheredTask: %w", err)
+	}
+	return &infoTask, nil
+}
+
 // Process handles responses (InfoGatheredTask) from InformationGatheringAgent.
 func (j *JiraRetrievalAgent) Process(ctx context.Context, taskID string, msg protocol.Message, handle taskmanager.TaskHandle) error {
 	var infoTask models.InfoGatheredTask
@@ -190,24 +449,136 @@ func (j *JiraRetrievalAgent) Process(ctx context.Context, taskID string, msg pro
 	}
 	log.Infof("Processing InfoGatheredTask for ticket %s", infoTask.TicketID)
 
-	// Format and post comment to Jira
-	commentText := j.formatJiraComment(&infoTask)
-	log.Infof("Posting comment to Jira API for ticket %s", infoTask.TicketID)
-	cmt, err := j.jiraClient.PostComment(infoTask.TicketID, commentText)
-	if err != nil {
-		log.Errorf("Failed to post comment for ticket %s: %v", infoTask.TicketID, err)
-	} else {
-		log.Infof("Successfully posted comment to Jira API for ticket %s (URL: %s)", infoTask.TicketID, cmt.URL)
+	// Cached and unchanged analyses are skipped like in the webhook flow
+	if err := j.postAnalysis(ctx, nil, "", &infoTask); err != nil {
+		return err
 	}
 
 	// Send final completion status
-	completeMsg := protocol.NewMessage(protocol.MessageRoleAgent, []protocol.Part{&protocol.TextPart{Text: fmt.Sprintf("Comment posted for ticket %s", infoTask.TicketID)}})
+	completeMsg := protocol.NewMessage(protocol.MessageRoleAgent, []protocol.Part{&protocol.TextPart{Text: fmt.Sprintf("Analysis processed for ticket %s", infoTask.TicketID)}})
 	if err := handle.UpdateStatus(protocol.TaskStateCompleted, &completeMsg); err != nil {
 		return err
 	}
 	return nil
 }
 
+// toTicketHierarchy converts the Jira client hierarchy into the A2A task model.
+func toTicketHierarchy(h *jira.ClientJiraHierarchy) *models.TicketHierarchy {
+	if h == nil || (len(h.Parents) == 0 && len(h.Siblings) == 0 && len(h.Children) == 0) {
+		return nil
+	}
+	return &models.TicketHierarchy{
+		Parents:  toIssueRefs(h.Parents),
+		Siblings: toIssueRefs(h.Siblings),
+		Children: toIssueRefs(h.Children),
+	}
+}
+
+// toAgileContext converts the Jira client sprint data into the A2A task model.
+func toAgileContext(a *jira.ClientJiraAgileContext) *models.AgileContext {
+	if a == nil || (a.Sprint == nil && a.StoryPoints == nil && a.ClosedSprints == 0) {
+		return nil
+	}
+	agile := &models.AgileContext{
+		Board:           a.BoardName,
+		ClosedSprints:   a.ClosedSprints,
+		StoryPoints:     a.StoryPoints,
+		AddedToSprintAt: a.AddedToSprintAt,
+		AddedMidSprint:  a.AddedMidSprint,
+	}
+	if a.Sprint != nil {
+		agile.Sprint = &models.Sprint{
+			ID:        a.Sprint.ID,
+			Name:      a.Sprint.Name,
+			State:     a.Sprint.State,
+			Goal:      a.Sprint.Goal,
+			StartDate: a.Sprint.StartDate,
+			EndDate:   a.Sprint.EndDate,
+		}
+	}
+	return agile
+}
+
+// toRemoteLinks converts the Jira client remote links into the A2A task model.
+func toRemoteLinks(links []jira.ClientJiraRemoteLink) []models.RemoteLink {
+	result := make([]models.RemoteLink, 0, len(links))
+	for _, l := range links {
+		result = append(result, models.RemoteLink{
+			Title:        l.Title,
+			URL:          l.URL,
+			Summary:      l.Summary,
+			Relationship: l.Relationship,
+			Application:  l.Application,
+			Resolved:     l.Resolved,
+		})
+	}
+	return result
+}
+
+// toDevelopmentInfo converts the Jira client development panel data into the A2A task model.
+// It returns nil when no development work is linked.
+func toDevelopmentInfo(d *jira.ClientJiraDevelopmentInfo) *models.DevelopmentInfo {
+	if d == nil || (d.Branches == 0 && d.Commits == 0 && d.PullRequestCount == 0) {
+		return nil
+	}
+	dev := &models.DevelopmentInfo{Branches: d.Branches, Commits: d.Commits}
+	for _, pr := range d.PullRequests {
+		dev.PullRequests = append(dev.PullRequests, models.PullRequest{
+			Title:      pr.Title,
+			URL:        pr.URL,
+			Status:     pr.Status,
+			Repository: pr.Repository,
+		})
+	}
+	return dev
+}
+
+// toAttachments converts the Jira client attachments into the A2A task model.
+func toAttachments(attachments []jira.ClientJiraAttachment) []models.Attachment {
+	if len(attachments) == 0 {
+		return nil
+	}
+	out := make([]models.Attachment, 0, len(attachments))
+	for _, a := range attachments {
+		out = append(out, models.Attachment{Filename: a.Filename, MimeType: a.MimeType, Author: a.Author, Created: a.Created, Content: a.Content})
+	}
+	return out
+}
+
+func toIssueRefs(refs []jira.ClientJiraIssueRef) []models.IssueRef {
+	if len(refs) == 0 {
+		return nil
+	}
+	out := make([]models.IssueRef, 0, len(refs))
+	for _, r := range refs {
+		out = append(out, models.IssueRef{Key: r.Key, Summary: r.Summary, Status: r.Status, IssueType: r.IssueType})
+	}
+	return out
+}
+
+// toFieldChanges converts the changes of an update event into the A2A task model.
+func toFieldChanges(changes []jira.FieldChange) []models.FieldChange {
+	if len(changes) == 0 {
+		return nil
+	}
+	out := make([]models.FieldChange, len(changes))
+	for i, c := range changes {
+		out[i] = models.FieldChange{Field: c.Field, From: c.From, To: c.To}
+	}
+	return out
+}
+
+func toComments(comments []jira.ClientJiraComment) []models.JiraComment {
+	if len(comments) == 0 {
+		return nil
+	}
+	out := make([]models.JiraComment, 0, len(comments))
+	for _, c := range comments {
+		out = append(out, models.JiraComment{ID: c.ID, Body: c.Body, Created: c.Created, Author: c.Author})
+	}
+	return out
+}
+
 func toStringSlice(val interface{}) []string {
 	if arr, ok := val.([]interface{}); ok {
 		out := make([]string, 0, len(arr))
@@ -225,10 +596,115 @@ func (j *JiraRetrievalAgent) formatJiraComment(task *models.InfoGatheredTask) st
 	sb.WriteString("*Information Gathering Results*\n\n")
 	sb.WriteString(fmt.Sprintf("*Summary:* %s\n\n", task.Summary))
 	sb.WriteString("*Analysis:*\n")
-	for k, v := range task.AnalysisResult {
-		sb.WriteString(fmt.Sprintf("- *%s:* %s\n", common.Capitalize(k), v))
+	var hidden []string
+	for _, f := range task.AnalysisResult.Fields() {
+		score, low := j.lowConfidence(task, f.Name)
+		if low && j.cfg.JiraCommentLowConfidence == "hide" {
+			hidden = append(hidden, f.Name)
+			continue
+		}
+		v := f.Value
+		if f.Name == "SuggestedAssignee" {
+			// Turn the suggested name into a mention so the user gets notified
+			v = j.users.Mention(v)
+		}
+		if low {
+			v += fmt.Sprintf(" _(low confidence: %.2f)_", score)
+		}
+		sb.WriteString(fmt.Sprintf("- *%s:* %s\n", f.Name, v))
+	}
+	if len(hidden) > 0 {
+		sb.WriteString(fmt.Sprintf("_Left out for low confidence: %s_\n", strings.Join(hidden, ", ")))
+	}
+	var duplicates []models.SimilarTicket
+	for _, t := range task.SimilarTickets {
+		if t.ProbableDuplicate {
+			duplicates = append(duplicates, t)
+		}
+	}
+	if len(duplicates) > 0 {
+		sb.WriteString("\n*Probable Duplicates:*\n")
+		for _, t := range duplicates {
+			sb.WriteString(fmt.Sprintf("- %s %s (similarity %.2f)\n", t.Key, t.Summary, t.Score))
+		}
+	}
+	if task.Quality != nil {
+		sb.WriteString("\n" + j.formatQuality(task.Quality, j.cfg.JiraCommentQualityQuestions))
 	}
 	sb.WriteString("\n*LLM Summary:*\n")
 	sb.WriteString(task.Summary)
 	return sb.String()
 }
+
+// formatDeltaComment formats a revised analysis as a concise follow-up to the previous comment.
+func (j *JiraRetrievalAgent) formatDeltaComment(task *models.InfoGatheredTask) string {
+	var sb strings.Builder
+	sb.WriteString("*Information Gathering Update*\n\n")
+	if task.Delta.Summary != "" {
+		sb.WriteString(task.Delta.Summary + "\n\n")
+	}
+	sb.WriteString(fmt.Sprintf("*Changes since the analysis of %s:*\n", task.Delta.Since))
+	for _, c := range task.Delta.Changes {
+		score, low := j.lowConfidence(task, c.Field)
+		if low && j.cfg.JiraCommentLowConfidence == "hide" {
+			continue
+		}
+		from, to := c.From, c.To
+		if c.Field == "SuggestedAssignee" && to != "" {
+			to = j.users.Mention(to)
+		}
+		if from == "" {
+			from = "none"
+		}
+		if to == "" {
+			to = "none"
+		}
+		sb.WriteString(fmt.Sprintf("- *%s:* %s → %s", c.Field, from, to))
+		if c.Reason != "" {
+			sb.WriteString(" (" + c.Reason + ")")
+		}
+		if low {
+			sb.WriteString(fmt.Sprintf(" _(low confidence: %.2f)_", score))
+		}
+		sb.WriteString("\n")
+	}
+	if task.Quality != nil && !task.Quality.Ready {
+		// The reporter was asked for the missing items with the full analysis
+		sb.WriteString("\n" + j.formatQuality(task.Quality, false))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// formatQuality formats a Definition-of-Ready assessment for a comment. With ask set, the
+// reporter of a ticket that is not ready is mentioned and asked for the missing items.
+func (j *JiraRetrievalAgent) formatQuality(q *models.QualityAssessment, ask bool) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("*Definition of Ready:* %d/100", q.Score))
+	if q.Ready {
+		sb.WriteString(" (ready)\n")
+		return sb.String()
+	}
+	sb.WriteString(fmt.Sprintf(" (missing: %s)\n", strings.Join(q.Missing, ", ")))
+	if !ask || len(q.Questions) == 0 {
+		return sb.String()
+	}
+	if q.Reporter != "" {
+		sb.WriteString(fmt.Sprintf("%s, could you help us with the following?\n", j.users.Mention(q.Reporter)))
+	} else {
+		sb.WriteString("Could the reporter help us with the following?\n")
+	}
+	for _, question := range q.Questions {
+		sb.WriteString("- " + question + "\n")
+	}
+	return sb.String()
+}
+
+// lowConfidence returns the confidence of an analysis field and whether it is below the
+// minimum for comments. Fields without a confidence are never low.
+func (j *JiraRetrievalAgent) lowConfidence(task *models.InfoGatheredTask, field string) (float64, bool) {
+	c, ok := task.Confidence[field]
+	if !ok || j.cfg.JiraCommentLowConfidence == "show" {
+		return 0, false
+	}
+	return c.Score, c.Score < j.cfg.JiraCommentMinConfidence
+}
@@ -0,0 +1,147 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/tuannvm/jira-a2a/internal/common"
+	"github.com/tuannvm/jira-a2a/internal/config"
+	"github.com/tuannvm/jira-a2a/internal/jira"
+	"github.com/tuannvm/jira-a2a/internal/jira/jiratest"
+	"github.com/tuannvm/jira-a2a/internal/models"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// newRetrievalTestAgent returns an agent ready to process webhooks against a fresh fake Jira
+func newRetrievalTestAgent(t *testing.T) *JiraRetrievalAgent {
+	t.Helper()
+	agent, _ := newWebhookTestAgent(t)
+	agent.users = jira.NewUserResolver(agent.jiraClient, time.Minute)
+	agent.jobs = newJobTracker()
+	return agent
+}
+
+// recordingHandle is a task handle that records the last state it was set to
+type recordingHandle struct {
+	state protocol.TaskState
+}
+
+func (h *recordingHandle) UpdateStatus(state protocol.TaskState, _ *protocol.Message) error {
+	h.state = state
+	return nil
+}
+
+func (h *recordingHandle) AddArtifact(protocol.Artifact) error { return nil }
+
+func (h *recordingHandle) IsStreamingRequest() bool { return false }
+
+func TestProcessSkipsUnchangedAnalyses(t *testing.T) {
+	agent, server := newWebhookTestAgent(t)
+	agent.jobs = newJobTracker()
+
+	analyses := []models.InfoGatheredTask{
+		{TicketID: "PROJ-2", Summary: "Login fails with SSO", Cached: true},
+		{TicketID: "PROJ-2", Summary: "Login fails with SSO", Delta: &models.AnalysisDelta{}},
+	}
+	for _, infoTask := range analyses {
+		msg := protocol.NewMessage(protocol.MessageRoleAgent, []protocol.Part{&protocol.DataPart{Data: infoTask}})
+		handle := &recordingHandle{}
+		if err := agent.Process(context.Background(), "task-1", msg, handle); err != nil {
+			t.Fatalf("Process: %v", err)
+		}
+		if handle.state != protocol.TaskStateCompleted {
+			t.Errorf("task state = %s, want completed", handle.state)
+		}
+	}
+	if writes := server.Writes(); len(writes) != 0 {
+		t.Errorf("unchanged analyses were written to Jira: %+v", writes)
+	}
+}
+
+func TestProcessStoresPropertyOfUnchangedAnalyses(t *testing.T) {
+	agent, server := newWebhookTestAgent(t)
+	agent.jobs = newJobTracker()
+	agent.cfg.JiraAnalysisProperty = "jira-a2a.analysis"
+
+	infoTask := models.InfoGatheredTask{
+		TicketID:       "PROJ-2",
+		AnalysisResult: &models.Analysis{Urgency: "High"},
+		Summary:        "Login fails with SSO",
+		AnalyzedAt:     "2025-01-12T08:31:02Z",
+		Cached:         true,
+	}
+	msg := protocol.NewMessage(protocol.MessageRoleAgent, []protocol.Part{&protocol.DataPart{Data: infoTask}})
+	if err := agent.Process(context.Background(), "task-1", msg, &recordingHandle{}); err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+
+	raw, ok := server.Property("PROJ-2", "jira-a2a.analysis")
+	if !ok {
+		t.Fatal("analysis property not stored")
+	}
+	var property models.AnalysisProperty
+	if err := json.Unmarshal(raw, &property); err != nil {
+		t.Fatalf("decoding property: %v", err)
+	}
+	if property.SchemaVersion != models.AnalysisPropertySchemaVersion || property.AnalysisResult.Urgency != "High" || property.AnalyzedAt != infoTask.AnalyzedAt {
+		t.Errorf("got property %+v", property)
+	}
+	for _, w := range server.Writes() {
+		if w.Method == "POST" {
+			t.Errorf("comment posted for an unchanged analysis: %+v", w)
+		}
+	}
+}
+
+func TestProcessWebhookIgnoresOwnEvents(t *testing.T) {
+	agent := newRetrievalTestAgent(t)
+	self, _ := jiratest.DefaultFixtures().Myself["accountId"].(string)
+
+	for _, event := range []string{"comment_created", "commented", "updated"} {
+		webReq := &jira.WebhookRequest{TicketID: "PROJ-2", Event: event, UserAccountID: self}
+		if err := agent.ProcessWebhook(context.Background(), webReq); err != nil {
+			t.Fatalf("ProcessWebhook(%s): %v", event, err)
+		}
+	}
+	if jobs := agent.jobs.List("PROJ-2"); len(jobs) != 0 {
+		t.Errorf("events triggered by the agent started jobs: %+v", jobs)
+	}
+
+	// Tickets the agent created are still analyzed; nothing listens for the analysis request
+	client, err := common.SetupA2AClient(&config.Config{}, "http://127.0.0.1:1")
+	if err != nil {
+		t.Fatalf("SetupA2AClient: %v", err)
+	}
+	agent.infoAgentClient = client
+	webReq := &jira.WebhookRequest{TicketID: "PROJ-2", Event: "created", UserAccountID: self}
+	if err := agent.ProcessWebhook(context.Background(), webReq); err != nil {
+		t.Fatalf("ProcessWebhook(created): %v", err)
+	}
+	if jobs := agent.jobs.List("PROJ-2"); len(jobs) != 1 {
+		t.Errorf("created event started %d jobs, want 1", len(jobs))
+	}
+}
+
+func TestCollectTicketTaskMarksAgentComments(t *testing.T) {
+	agent := newRetrievalTestAgent(t)
+	if _, err := agent.jiraClient.PostComment("PROJ-2", "Analysis done", nil); err != nil {
+		t.Fatalf("PostComment: %v", err)
+	}
+	ticket, err := agent.jiraClient.GetTicket("PROJ-2")
+	if err != nil {
+		t.Fatalf("GetTicket: %v", err)
+	}
+
+	task := agent.collectTicketTask(ticket, &jira.WebhookRequest{TicketID: "PROJ-2", Event: "updated"})
+	if len(task.Comments) < 2 {
+		t.Fatalf("got comments %+v, want the fixture comments and the agent's", task.Comments)
+	}
+	for i, c := range task.Comments {
+		last := i == len(task.Comments)-1
+		if c.ByAgent != last {
+			t.Errorf("comment %q ByAgent = %v, want %v", c.Body, c.ByAgent, last)
+		}
+	}
+}
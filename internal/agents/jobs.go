@@ -0,0 +1,136 @@
+package agents
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// maxJobs bounds the number of jobs kept in memory; the oldest are forgotten first.
+const maxJobs = 1000
+
+// Job is the progress of the analysis of one ticket, from the webhook to the Jira comment.
+type Job struct {
+	ID       string             `json:"id"` // A2A task ID of the analysis request
+	TicketID string             `json:"ticketId"`
+	State    protocol.TaskState `json:"state"`
+	Stage    string             `json:"stage,omitempty"`   // Latest progress message
+	Summary  string             `json:"summary,omitempty"` // Summary text streamed so far
+	Error    string             `json:"error,omitempty"`
+	Created  string             `json:"created"` // ISO 8601 format string
+	Updated  string             `json:"updated"` // ISO 8601 format string
+}
+
+// jobTracker keeps the progress of recent jobs in memory. It is safe for concurrent use.
+// Updates for unknown job IDs are ignored.
+type jobTracker struct {
+	mu    sync.RWMutex
+	jobs  map[string]*Job
+	order []string // Job IDs, oldest first
+}
+
+func newJobTracker() *jobTracker {
+	return &jobTracker{jobs: make(map[string]*Job)}
+}
+
+// Start records a new job for a ticket.
+func (t *jobTracker) Start(id, ticketID, stage string) {
+	now := time.Now().UTC().Format(time.RFC3339)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.jobs[id] = &Job{ID: id, TicketID: ticketID, State: protocol.TaskStateSubmitted, Stage: stage, Created: now, Updated: now}
+	t.order = append(t.order, id)
+	if len(t.order) > maxJobs {
+		delete(t.jobs, t.order[0])
+		t.order = t.order[1:]
+	}
+}
+
+// Progress moves a job to the working state with the given stage.
+func (t *jobTracker) Progress(id, stage string) {
+	t.update(id, func(job *Job) {
+		job.State = protocol.TaskStateWorking
+		job.Stage = stage
+	})
+}
+
+// AppendSummary adds streamed summary text to a job.
+func (t *jobTracker) AppendSummary(id, text string) {
+	t.update(id, func(job *Job) { job.Summary += text })
+}
+
+// Finish marks a job completed, or failed when err is not nil.
+func (t *jobTracker) Finish(id string, err error) {
+	t.update(id, func(job *Job) {
+		if err != nil {
+			job.State = protocol.TaskStateFailed
+			job.Error = err.Error()
+			return
+		}
+		job.State = protocol.TaskStateCompleted
+		job.Stage = "Done"
+	})
+}
+
+func (t *jobTracker) update(id string, fn func(job *Job)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	job, ok := t.jobs[id]
+	if !ok {
+		return
+	}
+	fn(job)
+	job.Updated = time.Now().UTC().Format(time.RFC3339)
+}
+
+// Get returns a copy of a job.
+func (t *jobTracker) Get(id string) (Job, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	job, ok := t.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return *job, true
+}
+
+// List returns copies of the jobs, newest first, optionally only those of one ticket.
+func (t *jobTracker) List(ticketID string) []Job {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	jobs := make([]Job, 0, len(t.jobs))
+	for i := len(t.order) - 1; i >= 0; i-- {
+		job := t.jobs[t.order[i]]
+		if ticketID == "" || job.TicketID == ticketID {
+			jobs = append(jobs, *job)
+		}
+	}
+	return jobs
+}
+
+// handleJobs lists recent jobs, optionally filtered with ?ticket=KEY.
+func (j *JiraRetrievalAgent) handleJobs(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, j.jobs.List(r.URL.Query().Get("ticket")))
+}
+
+// handleJob returns the progress of one job.
+func (j *JiraRetrievalAgent) handleJob(w http.ResponseWriter, r *http.Request) {
+	job, ok := j.jobs.Get(r.PathValue("id"))
+	if !ok {
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Warnf("Failed to write JSON response: %v", err)
+	}
+}
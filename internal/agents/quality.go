@@ -0,0 +1,125 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tuannvm/jira-a2a/internal/config"
+	"github.com/tuannvm/jira-a2a/internal/llm"
+	"github.com/tuannvm/jira-a2a/internal/models"
+	"github.com/tuannvm/jira-a2a/internal/prompts"
+	"github.com/tuannvm/jira-a2a/internal/quality"
+	"github.com/tuannvm/jira-a2a/internal/redaction"
+	"github.com/tuannvm/jira-a2a/internal/schema"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// qualityToolName is the tool the LLM calls to return a ticket's Definition-of-Ready assessment.
+const qualityToolName = "record_ticket_quality"
+
+// Quality prompt tuning.
+const (
+	// qualityDescriptionShare is the percentage of the prompt budget the description may use
+	qualityDescriptionShare = 50
+	// qualityCommentsShare is the percentage of the prompt budget the comments may use
+	qualityCommentsShare = 25
+)
+
+// qualityResponse is the LLM's answer to a quality prompt.
+type qualityResponse struct {
+	Checks []struct {
+		ID       string `json:"ID"`
+		Present  bool   `json:"Present"`
+		Evidence string `json:"Evidence"`
+		Question string `json:"Question"`
+	} `json:"Checks"`
+}
+
+// processQuality runs the assess-ticket-quality skill, returning the assessment alone as
+// the task's artifact.
+func (a *InformationGatheringAgent) processQuality(ctx context.Context, taskID string, task *models.TicketAvailableTask, handle taskmanager.TaskHandle) error {
+	log.Infof("Assessing quality of ticket %s (Task ID: %s)", task.TicketID, taskID)
+	if a.llmClient != nil {
+		ctx = llm.WithTicket(ctx, a.llmTicket(task))
+	}
+	project := strings.Split(task.TicketID, "-")[0]
+	tmpl := a.prompts.Select(prompts.KindQuality, project, task.IssueType)
+	reportProgress(handle, taskID, "Checking Definition of Ready...")
+	assessment := a.assessQuality(ctx, task, tmpl, a.redactor.NewSession())
+	log.Infof("Ticket %s scored %d/100 against its Definition of Ready (%s)", task.TicketID, assessment.Score, assessment.Source)
+	return sendArtifact(taskID, handle, "TicketQuality", "Definition-of-Ready score, missing items and follow-up questions",
+		assessment, "Completed ticket quality assessment.")
+}
+
+// assessQuality scores a ticket against the Definition-of-Ready checklist of its issue type.
+// An item counts as present when the LLM finds it or one of its patterns matches the ticket;
+// without the LLM, or when it fails, the patterns decide alone.
+func (a *InformationGatheringAgent) assessQuality(ctx context.Context, task *models.TicketAvailableTask, tmpl *prompts.Template, session *redaction.Session) *models.QualityAssessment {
+	checks := quality.Checklist(a.qualityChecklists, task.IssueType)
+	heuristic := quality.Evaluate(task, checks)
+	if a.llmClient == nil || len(checks) == 0 {
+		return heuristic
+	}
+	response, err := a.completeQuality(ctx, task, checks, tmpl, session)
+	if err != nil {
+		log.Warnf("LLM quality assessment of ticket %s failed, using checklist patterns only: %v", task.TicketID, err)
+		return heuristic
+	}
+
+	answers := make(map[string]int, len(response.Checks))
+	for i, c := range response.Checks {
+		answers[c.ID] = i
+	}
+	results := make([]models.QualityCheckResult, len(checks))
+	questions := make(map[string]string)
+	source := models.AnalysisSourceLLM
+	for i, check := range checks {
+		results[i] = heuristic.Checks[i]
+		j, ok := answers[check.ID]
+		if !ok {
+			source = models.AnalysisSourceMerged
+			continue
+		}
+		answer := response.Checks[j]
+		switch {
+		case answer.Present:
+			results[i].Present = true
+			results[i].Evidence = session.Restore(answer.Evidence)
+		case results[i].Present:
+			// A pattern matched what the LLM did not count
+			source = models.AnalysisSourceMerged
+		default:
+			results[i].Evidence = session.Restore(answer.Evidence)
+			questions[check.ID] = session.Restore(answer.Question)
+		}
+	}
+	return quality.Assess(task, checks, results, questions, source)
+}
+
+// completeQuality asks the LLM which checklist items the redacted ticket contains.
+func (a *InformationGatheringAgent) completeQuality(ctx context.Context, task *models.TicketAvailableTask, checks []config.QualityCheck, tmpl *prompts.Template, session *redaction.Session) (*qualityResponse, error) {
+	ticket := promptTicket(task, session)
+	budget := a.promptBudget()
+	ticket.Description = a.tokens.Truncate(ticket.Description, budget*qualityDescriptionShare/100)
+	prompt, err := tmpl.Execute(prompts.QualityData{
+		Ticket:   ticket,
+		Comments: a.tokens.Truncate(formatComments(ticket.HumanComments()), budget*qualityCommentsShare/100),
+		Checks:   checks,
+		Schema:   string(schema.QualityJSON()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := a.completeAnalysis(ctx, prompt, qualityToolName, schema.QualityJSON())
+	if err != nil {
+		return nil, fmt.Errorf("LLM quality completion failed: %w", err)
+	}
+	var answer qualityResponse
+	if err := decodeStructured(response, schema.Quality(), &answer); err != nil {
+		return nil, fmt.Errorf("LLM quality assessment: %w", err)
+	}
+	return &answer, nil
+}
@@ -0,0 +1,54 @@
+package agents
+
+import (
+	"context"
+
+	"github.com/tuannvm/jira-a2a/internal/models"
+	"github.com/tuannvm/jira-a2a/internal/redaction"
+	"github.com/tuannvm/jira-a2a/internal/similarity"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+)
+
+// maxEmbeddingChars bounds the ticket text sent to the embedder.
+const maxEmbeddingChars = 8000
+
+// findSimilarTickets returns the indexed tickets most similar to the ticket, then adds the
+// ticket to the index so later duplicates of it are found. It returns nil when similarity
+// detection is disabled or fails. Text sent to an external embedding provider is redacted by session.
+func (a *InformationGatheringAgent) findSimilarTickets(task *models.TicketAvailableTask, session *redaction.Session) []models.SimilarTicket {
+	if a.similarityIndex == nil {
+		return nil
+	}
+
+	text := task.Summary + "\n\n" + task.Description
+	if _, local := a.embedder.(*similarity.HashEmbedder); !local {
+		text = session.Redact(text)
+	}
+	if runes := []rune(text); len(runes) > maxEmbeddingChars {
+		text = string(runes[:maxEmbeddingChars])
+	}
+	vectors, err := a.embedder.Embed(context.Background(), []string{text})
+	if err != nil || len(vectors) != 1 {
+		log.Warnf("Failed to embed ticket %s for similarity search: %v", task.TicketID, err)
+		return nil
+	}
+
+	var similar []models.SimilarTicket
+	for _, m := range a.similarityIndex.Search(vectors[0], a.config.SimilarityTopK, task.TicketID) {
+		if m.Score < a.config.SimilarityMinScore {
+			break
+		}
+		similar = append(similar, models.SimilarTicket{
+			Key:               m.Key,
+			Summary:           m.Summary,
+			Score:             m.Score,
+			ProbableDuplicate: m.Score >= a.config.SimilarityDuplicateThreshold,
+		})
+	}
+
+	a.similarityIndex.Upsert(task.TicketID, task.Summary, vectors[0])
+	if err := a.similarityIndex.Save(); err != nil {
+		log.Errorf("Failed to save similarity index: %v", err)
+	}
+	return similar
+}
@@ -0,0 +1,92 @@
+package agents
+
+import (
+	"strings"
+	"time"
+
+	"github.com/tuannvm/jira-a2a/internal/common"
+	"github.com/tuannvm/jira-a2a/internal/redaction"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+	"trpc.group/trpc-go/trpc-a2a-go/taskmanager"
+)
+
+// summaryStreamInterval is the minimum time between two partial summary artifacts. The task
+// manager drops events a subscriber cannot take, so tokens are batched rather than sent one by one.
+const summaryStreamInterval = 500 * time.Millisecond
+
+// reportProgress sends an intermediate working status for the task. Failures are only logged,
+// as progress updates are informative.
+func reportProgress(handle taskmanager.TaskHandle, taskID, text string) {
+	msg := protocol.NewMessage(protocol.MessageRoleAgent, []protocol.Part{protocol.NewTextPart(text)})
+	if err := handle.UpdateStatus(protocol.TaskStateWorking, &msg); err != nil {
+		log.Warnf("Failed to send progress status for task %s: %v", taskID, err)
+	}
+}
+
+// summaryStream forwards summary tokens to subscribers as partial artifacts while the LLM
+// generates them. Redacted values are restored before anything is sent.
+type summaryStream struct {
+	handle   taskmanager.TaskHandle
+	taskID   string
+	session  *redaction.Session
+	pending  strings.Builder
+	lastSent time.Time
+	chunks   int
+}
+
+func newSummaryStream(handle taskmanager.TaskHandle, taskID string, session *redaction.Session) *summaryStream {
+	return &summaryStream{handle: handle, taskID: taskID, session: session, lastSent: time.Now()}
+}
+
+// Write buffers a token and sends the buffered text once the stream interval has passed.
+func (s *summaryStream) Write(token string) {
+	s.pending.WriteString(token)
+	if time.Since(s.lastSent) >= summaryStreamInterval {
+		s.send(false)
+	}
+}
+
+// Flush sends the remaining buffered text and closes the stream with a final chunk.
+func (s *summaryStream) Flush() {
+	s.send(true)
+	if s.chunks == 0 {
+		return
+	}
+	s.addChunk("", true)
+}
+
+func (s *summaryStream) send(all bool) {
+	buffered := s.pending.String()
+	text := buffered
+	if !all {
+		// Hold back a placeholder split across tokens so it can be restored once complete
+		if i := strings.LastIndex(text, "["); i >= 0 && !strings.Contains(text[i:], "]") {
+			text = text[:i]
+		}
+	}
+	if text == "" {
+		return
+	}
+	s.pending.Reset()
+	s.pending.WriteString(buffered[len(text):])
+
+	s.addChunk(s.session.Restore(text), false)
+}
+
+// addChunk sends one chunk of the summary artifact. Chunks after the first are appended to it.
+func (s *summaryStream) addChunk(text string, last bool) {
+	artifact := protocol.Artifact{
+		Name:        common.StringPtr("Summary"),
+		Description: common.StringPtr("Summary text streamed as it is generated"),
+		Index:       1,
+		Parts:       []protocol.Part{protocol.NewTextPart(text)},
+		Append:      common.BoolPtr(s.chunks > 0),
+		LastChunk:   common.BoolPtr(last),
+	}
+	if err := s.handle.AddArtifact(artifact); err != nil {
+		log.Warnf("Failed to stream summary for task %s: %v", s.taskID, err)
+	}
+	s.chunks++
+	s.lastSent = time.Now()
+}
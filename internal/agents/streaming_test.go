@@ -0,0 +1,59 @@
+package agents
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tuannvm/jira-a2a/internal/config"
+	"github.com/tuannvm/jira-a2a/internal/redaction"
+	"trpc.group/trpc-go/trpc-a2a-go/protocol"
+)
+
+// artifactHandle is a task handle that records the artifacts it receives
+type artifactHandle struct {
+	recordingHandle
+	artifacts []protocol.Artifact
+}
+
+func (h *artifactHandle) AddArtifact(artifact protocol.Artifact) error {
+	h.artifacts = append(h.artifacts, artifact)
+	return nil
+}
+
+func TestSummaryStreamRestoresSplitPlaceholders(t *testing.T) {
+	redactor, err := redaction.New(&config.RedactionRules{})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	session := redactor.NewSession()
+	if got := session.Redact("jane@example.com"); got != "[EMAIL_1]" {
+		t.Fatalf("Redact = %q", got)
+	}
+
+	handle := &artifactHandle{}
+	stream := newSummaryStream(handle, "task-1", session)
+	for _, token := range []string{"Ask [EM", "AIL_", "1] about", " the [EMAIL_1", "] login"} {
+		stream.lastSent = time.Time{} // send after every token
+		stream.Write(token)
+	}
+	stream.Flush()
+
+	var chunks []string
+	for i, artifact := range handle.artifacts {
+		text := artifact.Parts[0].(protocol.TextPart).Text
+		if strings.Contains(text, "[") || strings.Contains(text, "]") {
+			t.Errorf("chunk %d sent an unrestored placeholder: %q", i, text)
+		}
+		if appended := i > 0; *artifact.Append != appended {
+			t.Errorf("chunk %d append = %v", i, *artifact.Append)
+		}
+		if last := i == len(handle.artifacts)-1; *artifact.LastChunk != last {
+			t.Errorf("chunk %d last chunk = %v", i, *artifact.LastChunk)
+		}
+		chunks = append(chunks, text)
+	}
+	if got, want := strings.Join(chunks, ""), "Ask jane@example.com about the jane@example.com login"; got != want {
+		t.Errorf("streamed summary = %q, want %q", got, want)
+	}
+}
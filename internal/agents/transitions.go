@@ -0,0 +1,118 @@
+package agents
+
+import (
+	"strings"
+
+	"github.com/tuannvm/jira-a2a/internal/config"
+	"github.com/tuannvm/jira-a2a/internal/jira"
+	"github.com/tuannvm/jira-a2a/internal/models"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+)
+
+// applyTransitionRules executes the transition of the first rule matching the analysis,
+// if transitions are enabled for the ticket's project.
+func (j *JiraRetrievalAgent) applyTransitionRules(infoTask *models.InfoGatheredTask) {
+	project := strings.Split(infoTask.TicketID, "-")[0]
+	if len(j.transitionRules) == 0 || !projectEnabled(j.cfg.JiraTransitionProjects, project) {
+		return
+	}
+	rule := matchTransitionRule(j.transitionRules, project, infoTask.AnalysisResult)
+	if rule == nil {
+		return
+	}
+
+	transitions, err := j.jiraClient.GetTransitions(infoTask.TicketID)
+	if err != nil {
+		log.Errorf("Failed to list transitions for ticket %s: %v", infoTask.TicketID, err)
+		return
+	}
+	transition := findTransition(transitions, rule.Transition)
+	if transition == nil {
+		log.Infof("Transition %q (rule %s) is not available for ticket %s in its current status", rule.Transition, rule.Name, infoTask.TicketID)
+		return
+	}
+	var missing []string
+	for _, field := range transition.RequiredFields {
+		if _, ok := rule.Fields[field]; !ok {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		log.Warnf("Skipping transition %q (rule %s) for ticket %s: required fields %v are not set by the rule",
+			transition.Name, rule.Name, infoTask.TicketID, missing)
+		return
+	}
+
+	if j.cfg.JiraTransitionDryRun {
+		log.Infof("[dry-run] Would transition ticket %s via %q to %s (rule %s, fields %v, comment %q)",
+			infoTask.TicketID, transition.Name, transition.ToStatus, rule.Name, rule.Fields, rule.Comment)
+		return
+	}
+	comment := rule.Comment
+	var visibility *jira.ClientJiraCommentVisibility
+	if comment != "" {
+		var err error
+		if visibility, err = j.commentVisibility(infoTask.TicketID); err != nil {
+			log.Errorf("Transitioning ticket %s without the comment of rule %s: %v", infoTask.TicketID, rule.Name, err)
+			comment = ""
+		}
+	}
+	if err := j.jiraClient.DoTransition(infoTask.TicketID, transition.ID, rule.Fields, comment, visibility); err != nil {
+		log.Errorf("Failed to transition ticket %s via %q (rule %s): %v", infoTask.TicketID, transition.Name, rule.Name, err)
+		return
+	}
+	log.Infof("Transitioned ticket %s via %q to %s (rule %s)", infoTask.TicketID, transition.Name, transition.ToStatus, rule.Name)
+}
+
+// matchTransitionRule returns the first rule whose conditions all hold for the analysis.
+func matchTransitionRule(rules []config.TransitionRule, project string, analysis *models.Analysis) *config.TransitionRule {
+	for i := range rules {
+		rule := &rules[i]
+		if len(rule.Projects) > 0 && !containsFold(rule.Projects, project) {
+			continue
+		}
+		matched := true
+		for field, want := range rule.When {
+			value, _ := analysis.Field(field)
+			if !strings.EqualFold(strings.TrimSpace(value), strings.TrimSpace(want)) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return rule
+		}
+	}
+	return nil
+}
+
+// findTransition finds an available transition by ID, name or target status.
+func findTransition(transitions []jira.ClientJiraTransition, target string) *jira.ClientJiraTransition {
+	for i := range transitions {
+		if transitions[i].ID == target || strings.EqualFold(transitions[i].Name, target) {
+			return &transitions[i]
+		}
+	}
+	for i := range transitions {
+		if strings.EqualFold(transitions[i].ToStatus, target) {
+			return &transitions[i]
+		}
+	}
+	return nil
+}
+
+// projectEnabled reports whether a project is in a comma separated list, where "*" matches all.
+func projectEnabled(list, project string) bool {
+	projects := splitList(list)
+	return containsFold(projects, "*") || containsFold(projects, project)
+}
+
+// containsFold reports whether values contains s, ignoring case.
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,209 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tuannvm/jira-a2a/internal/config"
+	"github.com/tuannvm/jira-a2a/internal/jira"
+	"trpc.group/trpc-go/trpc-a2a-go/log"
+)
+
+// Webhook refresh timing. Jira expires dynamic webhooks after 30 days unless refreshed.
+const (
+	webhookRefreshMargin = 24 * time.Hour  // Refresh this long before the webhooks expire
+	webhookRetryInterval = 5 * time.Minute // Wait before retrying a failed refresh
+)
+
+// ManageWebhooks registers the webhooks the agent needs through Jira's dynamic webhook API,
+// keeps them refreshed until ctx is canceled and then removes them.
+// Existing webhooks delivering to the agent's URL whose filter or events no longer match the
+// configuration are deleted, and matching ones are reused, so restarts do not accumulate
+// duplicates. Webhooks delivering elsewhere, e.g. to another deployment, are left alone.
+// Only Connect and OAuth 2.0 apps may use the API; when Jira refuses it, an error is returned.
+func (j *JiraRetrievalAgent) ManageWebhooks(ctx context.Context) error {
+	specs, err := webhookSpecs(j.cfg)
+	if err != nil {
+		return err
+	}
+	url := webhookURL(j.cfg)
+
+	ids, expiry, err := j.reconcileWebhooks(url, specs)
+	if err != nil {
+		return webhookAPIError(err)
+	}
+	log.Infof("Registered Jira webhooks %v delivering to %s, expiring %s", ids, url, expiry.Format(time.RFC3339))
+
+	for {
+		wait := time.Until(expiry) - webhookRefreshMargin
+		if wait < webhookRetryInterval {
+			wait = webhookRetryInterval
+		}
+		select {
+		case <-ctx.Done():
+			if err := j.jiraClient.DeleteWebhooks(ids); err != nil {
+				return fmt.Errorf("failed to remove webhooks on shutdown: %w", err)
+			}
+			log.Infof("Removed Jira webhooks %v", ids)
+			return nil
+		case <-time.After(wait):
+		}
+
+		newExpiry, err := j.jiraClient.RefreshWebhooks(ids)
+		if err == nil {
+			expiry = newExpiry
+			log.Infof("Refreshed Jira webhooks %v, expiring %s", ids, expiry.Format(time.RFC3339))
+			continue
+		}
+
+		// The webhooks may have been removed in Jira; register them again
+		log.Warnf("Failed to refresh Jira webhooks %v, reconciling: %v", ids, err)
+		newIDs, newExpiry, err := j.reconcileWebhooks(url, specs)
+		if errors.Is(err, jira.ErrForbidden) {
+			return webhookAPIError(err)
+		}
+		if err != nil {
+			log.Errorf("Failed to reconcile Jira webhooks: %v", err)
+			continue
+		}
+		ids, expiry = newIDs, newExpiry
+	}
+}
+
+// webhookAPIError explains a refused dynamic webhook API call.
+func webhookAPIError(err error) error {
+	if errors.Is(err, jira.ErrForbidden) {
+		return fmt.Errorf("jira refused the dynamic webhook API, which only Connect and OAuth 2.0 apps may use; "+
+			"create the webhook in Jira's system settings and set WEBHOOK_AUTO_REGISTER=false: %w", err)
+	}
+	return err
+}
+
+// reconcileWebhooks makes the registered webhooks delivering to url match specs, returning
+// their IDs and expiry.
+func (j *JiraRetrievalAgent) reconcileWebhooks(url string, specs []jira.ClientJiraWebhookSpec) ([]int, time.Time, error) {
+	registered, err := j.jiraClient.ListWebhooks()
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	// Jira lists only the webhooks of the calling app, but several deployments may share it
+	var existing []jira.ClientJiraWebhook
+	for _, hook := range registered {
+		if hook.URL == url {
+			existing = append(existing, hook)
+		}
+	}
+
+	var ids, stale []int
+	var missing []jira.ClientJiraWebhookSpec
+	matched := make(map[int]bool)
+	for _, spec := range specs {
+		found := false
+		for _, hook := range existing {
+			if !matched[hook.ID] && webhookMatches(hook, spec) {
+				matched[hook.ID] = true
+				ids = append(ids, hook.ID)
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, spec)
+		}
+	}
+	for _, hook := range existing {
+		if !matched[hook.ID] {
+			stale = append(stale, hook.ID)
+		}
+	}
+
+	if len(stale) > 0 {
+		log.Infof("Removing stale Jira webhooks %v", stale)
+		if err := j.jiraClient.DeleteWebhooks(stale); err != nil {
+			return nil, time.Time{}, err
+		}
+	}
+	if len(missing) > 0 {
+		created, err := j.jiraClient.RegisterWebhooks(url, missing)
+		if err != nil {
+			// Remove the webhooks this call did create, so a retry does not leave them behind
+			if len(created) > 0 {
+				if derr := j.jiraClient.DeleteWebhooks(created); derr != nil {
+					log.Errorf("Failed to remove partially registered Jira webhooks %v: %v", created, derr)
+				}
+			}
+			return nil, time.Time{}, err
+		}
+		ids = append(ids, created...)
+	}
+	if len(ids) == 0 {
+		return nil, time.Time{}, errors.New("no webhooks registered")
+	}
+
+	// Refreshing aligns the expiry of reused and newly created webhooks
+	expiry, err := j.jiraClient.RefreshWebhooks(ids)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return ids, expiry, nil
+}
+
+// webhookSpecs derives the webhooks to register from the configuration.
+func webhookSpecs(cfg *config.Config) ([]jira.ClientJiraWebhookSpec, error) {
+	var clauses []string
+	if projects := splitList(cfg.WebhookProjects); len(projects) > 0 {
+		clauses = append(clauses, fmt.Sprintf("project in (%s)", strings.Join(projects, ", ")))
+	}
+	if jql := strings.TrimSpace(cfg.WebhookJQL); jql != "" {
+		clauses = append(clauses, jql)
+	}
+	if len(clauses) == 0 {
+		return nil, errors.New("webhook auto-registration requires WEBHOOK_PROJECTS or WEBHOOK_JQL")
+	}
+
+	events := splitList(cfg.WebhookEvents)
+	if len(events) == 0 {
+		return nil, errors.New("webhook auto-registration requires at least one WEBHOOK_EVENTS entry")
+	}
+	return []jira.ClientJiraWebhookSpec{{JQLFilter: strings.Join(clauses, " AND "), Events: events}}, nil
+}
+
+// webhookURL returns the URL Jira should deliver webhooks to.
+func webhookURL(cfg *config.Config) string {
+	if cfg.WebhookURL != "" {
+		return cfg.WebhookURL
+	}
+	return fmt.Sprintf("http://%s:%d/webhook", cfg.ServerHost, cfg.WebhookPort)
+}
+
+// webhookMatches reports whether a registered webhook has the filter and events of spec.
+func webhookMatches(hook jira.ClientJiraWebhook, spec jira.ClientJiraWebhookSpec) bool {
+	if strings.TrimSpace(hook.JQLFilter) != strings.TrimSpace(spec.JQLFilter) || len(hook.Events) != len(spec.Events) {
+		return false
+	}
+	have := append([]string(nil), hook.Events...)
+	want := append([]string(nil), spec.Events...)
+	sort.Strings(have)
+	sort.Strings(want)
+	for i := range have {
+		if have[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// splitList splits a comma separated configuration value, dropping empty entries.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
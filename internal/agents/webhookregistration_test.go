@@ -0,0 +1,196 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tuannvm/jira-a2a/internal/config"
+	"github.com/tuannvm/jira-a2a/internal/jira"
+	"github.com/tuannvm/jira-a2a/internal/jira/jiratest"
+)
+
+const testWebhookURL = "https://agent.example.com/webhook"
+
+// newWebhookTestAgent returns an agent whose Jira client talks to a fresh fake Jira
+func newWebhookTestAgent(t *testing.T) (*JiraRetrievalAgent, *jiratest.Server) {
+	t.Helper()
+	server := jiratest.NewServer(nil)
+	t.Cleanup(server.Close)
+	cfg := &config.Config{
+		JiraBaseURL:     server.Start(),
+		WebhookURL:      testWebhookURL,
+		WebhookProjects: "PROJ",
+		WebhookEvents:   "jira:issue_created, jira:issue_updated",
+	}
+	return &JiraRetrievalAgent{cfg: cfg, jiraClient: jira.NewClient(cfg)}, server
+}
+
+func webhookIDs(hooks []jiratest.Webhook) []int {
+	ids := []int{}
+	for _, hook := range hooks {
+		ids = append(ids, hook.ID)
+	}
+	return ids
+}
+
+func TestReconcileWebhooksRegisters(t *testing.T) {
+	agent, server := newWebhookTestAgent(t)
+	specs, err := webhookSpecs(agent.cfg)
+	if err != nil {
+		t.Fatalf("webhookSpecs: %v", err)
+	}
+
+	ids, expiry, err := agent.reconcileWebhooks(testWebhookURL, specs)
+	if err != nil {
+		t.Fatalf("reconcileWebhooks: %v", err)
+	}
+	hooks := server.Webhooks()
+	if len(hooks) != 1 || !reflect.DeepEqual(ids, webhookIDs(hooks)) {
+		t.Fatalf("got IDs %v, registered webhooks %+v", ids, hooks)
+	}
+	hook := hooks[0]
+	if hook.URL != testWebhookURL || hook.JQLFilter != "project in (PROJ)" {
+		t.Errorf("registered webhook %+v", hook)
+	}
+	if !reflect.DeepEqual(hook.Events, []string{"jira:issue_created", "jira:issue_updated"}) {
+		t.Errorf("registered events %v", hook.Events)
+	}
+	if time.Until(expiry) < 29*24*time.Hour {
+		t.Errorf("expiry %s is not about 30 days away", expiry)
+	}
+
+	// A second run reuses the webhook instead of registering a duplicate
+	again, _, err := agent.reconcileWebhooks(testWebhookURL, specs)
+	if err != nil {
+		t.Fatalf("reconcileWebhooks: %v", err)
+	}
+	if !reflect.DeepEqual(again, ids) || len(server.Webhooks()) != 1 {
+		t.Errorf("second run returned %v with webhooks %+v", again, server.Webhooks())
+	}
+}
+
+func TestReconcileWebhooksRefreshesAndDeletesStale(t *testing.T) {
+	agent, server := newWebhookTestAgent(t)
+	specs, err := webhookSpecs(agent.cfg)
+	if err != nil {
+		t.Fatalf("webhookSpecs: %v", err)
+	}
+	soon := time.Now().Add(time.Hour)
+	current := server.AddWebhook(jiratest.Webhook{
+		URL:            testWebhookURL,
+		JQLFilter:      "project in (PROJ)",
+		Events:         []string{"jira:issue_updated", "jira:issue_created"},
+		ExpirationDate: soon,
+	})
+	stale := server.AddWebhook(jiratest.Webhook{URL: testWebhookURL, JQLFilter: "project = OLD", Events: []string{"jira:issue_created"}})
+
+	ids, expiry, err := agent.reconcileWebhooks(testWebhookURL, specs)
+	if err != nil {
+		t.Fatalf("reconcileWebhooks: %v", err)
+	}
+	if !reflect.DeepEqual(ids, []int{current.ID}) {
+		t.Errorf("got IDs %v, want the existing webhook %d", ids, current.ID)
+	}
+	hooks := server.Webhooks()
+	if !reflect.DeepEqual(webhookIDs(hooks), []int{current.ID}) {
+		t.Errorf("stale webhook %d not deleted: %+v", stale.ID, hooks)
+	}
+	if !expiry.After(soon) || !hooks[0].ExpirationDate.After(soon) {
+		t.Errorf("webhook not refreshed: expiry %s, stored %s", expiry, hooks[0].ExpirationDate)
+	}
+	for _, w := range server.Writes() {
+		if w.Method == "POST" {
+			t.Errorf("unexpected webhook registration %s", w.Body)
+		}
+	}
+}
+
+func TestReconcileWebhooksKeepsOtherDeployments(t *testing.T) {
+	agent, server := newWebhookTestAgent(t)
+	specs, err := webhookSpecs(agent.cfg)
+	if err != nil {
+		t.Fatalf("webhookSpecs: %v", err)
+	}
+	staging := server.AddWebhook(jiratest.Webhook{URL: "https://staging.example.com/webhook", JQLFilter: "project = STAGE", Events: []string{"jira:issue_created"}})
+	sameFilter := server.AddWebhook(jiratest.Webhook{URL: "https://staging.example.com/webhook", JQLFilter: "project in (PROJ)", Events: []string{"jira:issue_created", "jira:issue_updated"}})
+
+	ids, _, err := agent.reconcileWebhooks(testWebhookURL, specs)
+	if err != nil {
+		t.Fatalf("reconcileWebhooks: %v", err)
+	}
+	if len(ids) != 1 || ids[0] == staging.ID || ids[0] == sameFilter.ID {
+		t.Errorf("got IDs %v, want a new webhook of its own", ids)
+	}
+	if got := webhookIDs(server.Webhooks()); !reflect.DeepEqual(got, []int{staging.ID, sameFilter.ID, ids[0]}) {
+		t.Errorf("registered webhooks %v, want the other deployment's kept", got)
+	}
+}
+
+func TestManageWebhooksFailsWhenForbidden(t *testing.T) {
+	agent, _ := newWebhookTestAgent(t)
+	// Jira refuses the dynamic webhook API to basic auth clients
+	forbidden := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"errorMessages":["Only Connect and OAuth 2.0 apps can use this operation"]}`, http.StatusForbidden)
+	}))
+	t.Cleanup(forbidden.Close)
+	agent.cfg.JiraBaseURL = forbidden.URL
+	agent.jiraClient = jira.NewClient(agent.cfg)
+
+	done := make(chan error, 1)
+	go func() { done <- agent.ManageWebhooks(context.Background()) }()
+	select {
+	case err := <-done:
+		if !errors.Is(err, jira.ErrForbidden) || !strings.Contains(err.Error(), "WEBHOOK_AUTO_REGISTER") {
+			t.Errorf("ManageWebhooks error = %v, want an explanation of the refused API", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("ManageWebhooks kept retrying a refused registration")
+	}
+}
+
+func TestReconcileWebhooksRemovesPartialRegistration(t *testing.T) {
+	agent, server := newWebhookTestAgent(t)
+	specs := []jira.ClientJiraWebhookSpec{
+		{JQLFilter: "project = PROJ", Events: []string{"jira:issue_created"}},
+		{JQLFilter: "", Events: []string{"jira:issue_updated"}}, // rejected by Jira
+	}
+
+	ids, _, err := agent.reconcileWebhooks(testWebhookURL, specs)
+	if err == nil {
+		t.Fatalf("reconcileWebhooks succeeded with IDs %v", ids)
+	}
+	if ids != nil {
+		t.Errorf("got IDs %v on failure", ids)
+	}
+	if hooks := server.Webhooks(); len(hooks) != 0 {
+		t.Errorf("partially registered webhooks left behind: %+v", hooks)
+	}
+}
+
+func TestManageWebhooksRemovesOnShutdown(t *testing.T) {
+	agent, server := newWebhookTestAgent(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- agent.ManageWebhooks(ctx) }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(server.Webhooks()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("webhook was not registered")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("ManageWebhooks: %v", err)
+	}
+	if hooks := server.Webhooks(); len(hooks) != 0 {
+		t.Errorf("webhooks left after shutdown: %+v", hooks)
+	}
+}
@@ -0,0 +1,221 @@
+// Package cache keeps analysis results on disk keyed by a hash of the ticket content,
+// so tickets whose analysis-relevant fields did not change are not analyzed again.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/tuannvm/jira-a2a/internal/logging"
+	"github.com/tuannvm/jira-a2a/internal/models"
+)
+
+// fileVersion is the layout version of the cache file. Files of another version are discarded.
+const fileVersion = 1
+
+// hashInput is the part of a ticket the analysis depends on. Fields changed by routine
+// activity, such as the update timestamp, the change log and custom fields like rank
+// or watchers, are left out so that such updates do not invalidate the cache. So are
+// the agent's own comments, which would otherwise invalidate every analysis it posts.
+type hashInput struct {
+	Summary     string                  `json:"summary"`
+	Description string                  `json:"description"`
+	Status      string                  `json:"status"`
+	IssueType   string                  `json:"issueType"`
+	Reporter    string                  `json:"reporter"`
+	Assignee    string                  `json:"assignee"`
+	Priority    string                  `json:"priority"`
+	Labels      []string                `json:"labels"`
+	Created     string                  `json:"created"`
+	DueDate     string                  `json:"dueDate"`
+	Hierarchy   *models.TicketHierarchy `json:"hierarchy"`
+	Comments    []models.JiraComment    `json:"comments"`
+	Agile       *models.AgileContext    `json:"agile"`
+	RemoteLinks []models.RemoteLink     `json:"remoteLinks"`
+	Development *models.DevelopmentInfo `json:"development"`
+	Attachments []models.Attachment     `json:"attachments"`
+	Versions    []string                `json:"versions"`
+}
+
+// Hash returns a canonical hash of the analysis-relevant content of a ticket and of the
+// versions of everything else the result depends on, such as the model and prompt templates.
+func Hash(task *models.TicketAvailableTask, versions ...string) string {
+	labels := append([]string(nil), task.Labels...)
+	sort.Strings(labels)
+	input := hashInput{
+		Summary:     task.Summary,
+		Description: task.Description,
+		Status:      task.Status,
+		IssueType:   task.IssueType,
+		Reporter:    task.Reporter,
+		Assignee:    task.Assignee,
+		Priority:    task.Priority,
+		Labels:      labels,
+		Created:     task.Created,
+		DueDate:     task.DueDate,
+		Hierarchy:   task.Hierarchy,
+		Comments:    task.HumanComments(),
+		Agile:       task.Agile,
+		RemoteLinks: task.RemoteLinks,
+		Development: task.Development,
+		Attachments: task.Attachments,
+		Versions:    versions,
+	}
+	// encoding/json writes struct fields in declaration order and map keys sorted,
+	// so equal content always encodes to the same bytes
+	data, _ := json.Marshal(input)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Store is an on-disk cache of the latest analysis of each ticket. It is safe for concurrent use.
+type Store struct {
+	path    string
+	ttl     time.Duration
+	mu      sync.RWMutex
+	entries map[string]*entry
+}
+
+type entry struct {
+	Key    string                   `json:"key"`
+	Hash   string                   `json:"hash"`
+	Stored string                   `json:"stored"` // ISO 8601 format string
+	Result *models.InfoGatheredTask `json:"result"`
+}
+
+// storeFile is the layout of the cache file.
+type storeFile struct {
+	Version int      `json:"version"`
+	Entries []*entry `json:"entries"`
+}
+
+// Open loads the cache stored at path, or starts an empty one if the file does not exist.
+// Entries older than ttl are neither returned nor saved again.
+func Open(path string, ttl time.Duration) (*Store, error) {
+	s := &Store{path: path, ttl: ttl, entries: make(map[string]*entry)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read analysis cache: %w", err)
+	}
+	var file storeFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse analysis cache %s: %w", path, err)
+	}
+	if file.Version != fileVersion {
+		log.Warnf("Analysis cache %s has version %d, not %d; starting a new cache", path, file.Version, fileVersion)
+		return s, nil
+	}
+	for _, e := range file.Entries {
+		if !s.expired(e) {
+			s.entries[e.Key] = e
+		}
+	}
+	return s, nil
+}
+
+// Len returns the number of cached tickets.
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.entries)
+}
+
+// Get returns a copy of the cached analysis of a ticket if it was made for content with the
+// given hash and has not expired.
+func (s *Store) Get(key, hash string) (*models.InfoGatheredTask, bool) {
+	s.mu.RLock()
+	e, ok := s.entries[key]
+	s.mu.RUnlock()
+	if !ok || e.Hash != hash || s.expired(e) {
+		return nil, false
+	}
+	return copyResult(e.Result)
+}
+
+// Latest returns a copy of the cached analysis of a ticket whatever content it was made for,
+// if it has not expired.
+func (s *Store) Latest(key string) (*models.InfoGatheredTask, bool) {
+	s.mu.RLock()
+	e, ok := s.entries[key]
+	s.mu.RUnlock()
+	if !ok || s.expired(e) {
+		return nil, false
+	}
+	return copyResult(e.Result)
+}
+
+// copyResult deep copies a cached result through JSON so callers cannot modify the cache.
+func copyResult(cached *models.InfoGatheredTask) (*models.InfoGatheredTask, bool) {
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return nil, false
+	}
+	var result models.InfoGatheredTask
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, false
+	}
+	return &result, true
+}
+
+// Put caches the analysis of a ticket, replacing any previous one.
+func (s *Store) Put(key, hash string, result *models.InfoGatheredTask) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = &entry{Key: key, Hash: hash, Stored: time.Now().UTC().Format(time.RFC3339), Result: result}
+}
+
+// Save writes the unexpired entries to disk, replacing the file atomically.
+func (s *Store) Save() error {
+	s.mu.RLock()
+	file := storeFile{Version: fileVersion, Entries: make([]*entry, 0, len(s.entries))}
+	for _, e := range s.entries {
+		if !s.expired(e) {
+			file.Entries = append(file.Entries, e)
+		}
+	}
+	s.mu.RUnlock()
+	sort.Slice(file.Entries, func(i, j int) bool { return file.Entries[i].Key < file.Entries[j].Key })
+
+	data, err := json.Marshal(file)
+	if err != nil {
+		return fmt.Errorf("failed to encode analysis cache: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write analysis cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write analysis cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write analysis cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to replace analysis cache: %w", err)
+	}
+	return nil
+}
+
+// expired reports whether an entry is older than the TTL. A TTL of zero never expires.
+func (s *Store) expired(e *entry) bool {
+	if s.ttl <= 0 {
+		return false
+	}
+	stored, err := time.Parse(time.RFC3339, e.Stored)
+	return err != nil || time.Since(stored) > s.ttl
+}
@@ -0,0 +1,166 @@
+package cache
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tuannvm/jira-a2a/internal/models"
+)
+
+func newTestTask() *models.TicketAvailableTask {
+	return &models.TicketAvailableTask{
+		TicketID:    "PROJ-2",
+		Summary:     "Login fails with SSO",
+		Description: "Users get a 500 after the redirect",
+		Status:      "Open",
+		Labels:      []string{"sso", "login"},
+		Updated:     "2025-01-12T08:31:02Z",
+		Comments:    []models.JiraComment{{ID: "1", Body: "Also on staging"}},
+	}
+}
+
+func TestHash(t *testing.T) {
+	base := Hash(newTestTask(), "gpt-4o", "prompts-1")
+
+	tests := []struct {
+		name     string
+		change   func(task *models.TicketAvailableTask)
+		versions []string
+		changed  bool
+	}{
+		{name: "unchanged", change: func(task *models.TicketAvailableTask) {}},
+		{name: "label order", change: func(task *models.TicketAvailableTask) { task.Labels = []string{"login", "sso"} }},
+		{name: "update timestamp", change: func(task *models.TicketAvailableTask) { task.Updated = "2025-01-13T10:00:00Z" }},
+		{name: "event and changes", change: func(task *models.TicketAvailableTask) {
+			task.Event, task.Changes = "updated", "rank changed"
+			task.FieldChanges = []models.FieldChange{{Field: "Rank"}}
+		}},
+		{name: "agent comment", change: func(task *models.TicketAvailableTask) {
+			task.Comments = append(task.Comments, models.JiraComment{ID: "2", Body: "Analysis", ByAgent: true})
+		}},
+		{name: "description", change: func(task *models.TicketAvailableTask) { task.Description += "." }, changed: true},
+		{name: "human comment", change: func(task *models.TicketAvailableTask) {
+			task.Comments = append(task.Comments, models.JiraComment{ID: "2", Body: "And on production"})
+		}, changed: true},
+		{name: "new label", change: func(task *models.TicketAvailableTask) { task.Labels = append(task.Labels, "p1") }, changed: true},
+		{name: "model version", change: func(task *models.TicketAvailableTask) {}, versions: []string{"gpt-4.1", "prompts-1"}, changed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := newTestTask()
+			tt.change(task)
+			versions := tt.versions
+			if versions == nil {
+				versions = []string{"gpt-4o", "prompts-1"}
+			}
+			if changed := Hash(task, versions...) != base; changed != tt.changed {
+				t.Errorf("hash changed = %v, want %v", changed, tt.changed)
+			}
+		})
+	}
+}
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	store, err := Open(path, time.Hour)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if _, ok := store.Latest("PROJ-2"); ok {
+		t.Fatal("empty cache returned an analysis")
+	}
+
+	store.Put("PROJ-2", "hash-1", &models.InfoGatheredTask{TicketID: "PROJ-2", Summary: "Login fails", Redactions: map[string]int{"email": 1}})
+
+	got, ok := store.Get("PROJ-2", "hash-1")
+	if !ok || got.Summary != "Login fails" {
+		t.Fatalf("Get = %+v, %v", got, ok)
+	}
+	got.Redactions["email"] = 2 // callers get a copy
+	if _, ok := store.Get("PROJ-2", "hash-2"); ok {
+		t.Error("Get returned an analysis of other content")
+	}
+	if latest, ok := store.Latest("PROJ-2"); !ok || latest.Redactions["email"] != 1 {
+		t.Errorf("Latest = %+v, %v", latest, ok)
+	}
+
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	reopened, err := Open(path, time.Hour)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if got, ok := reopened.Get("PROJ-2", "hash-1"); !ok || got.Summary != "Login fails" {
+		t.Errorf("reopened Get = %+v, %v", got, ok)
+	}
+	matches, _ := filepath.Glob(path + ".*")
+	if len(matches) != 0 {
+		t.Errorf("temporary files left behind: %v", matches)
+	}
+}
+
+func TestStoreExpiry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	old := time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+	recent := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+	writeFile(t, path, storeFile{Version: fileVersion, Entries: []*entry{
+		{Key: "PROJ-1", Hash: "a", Stored: old, Result: &models.InfoGatheredTask{TicketID: "PROJ-1"}},
+		{Key: "PROJ-2", Hash: "b", Stored: recent, Result: &models.InfoGatheredTask{TicketID: "PROJ-2"}},
+	}})
+
+	tests := []struct {
+		name string
+		ttl  time.Duration
+		want int
+	}{
+		{name: "expired entries dropped", ttl: time.Hour, want: 1},
+		{name: "zero ttl keeps everything", ttl: 0, want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := Open(path, tt.ttl)
+			if err != nil {
+				t.Fatalf("Open: %v", err)
+			}
+			if store.Len() != tt.want {
+				t.Errorf("Len = %d, want %d", store.Len(), tt.want)
+			}
+			_, ok := store.Latest("PROJ-1")
+			if ok != (tt.ttl == 0) {
+				t.Errorf("expired entry returned = %v", ok)
+			}
+		})
+	}
+
+	// A cache file of another layout version is discarded
+	writeFile(t, path, storeFile{Version: fileVersion + 1, Entries: []*entry{{Key: "PROJ-2", Hash: "b", Stored: recent}}})
+	store, err := Open(path, time.Hour)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if store.Len() != 0 {
+		t.Errorf("cache of another version kept %d entries", store.Len())
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(path, time.Hour); err == nil {
+		t.Error("Open accepted a corrupt cache file")
+	}
+}
+
+func writeFile(t *testing.T, path string, file storeFile) {
+	t.Helper()
+	data, err := json.Marshal(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
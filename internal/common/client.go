@@ -3,6 +3,9 @@ package common
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/tuannvm/jira-a2a/internal/config"
 	"trpc.group/trpc-go/trpc-a2a-go/client"
@@ -10,6 +13,10 @@ import (
 	"trpc.group/trpc-go/trpc-a2a-go/protocol"
 )
 
+// RequestTimeout bounds a synchronous A2A request. Streamed tasks are bounded by the
+// timeout passed to StreamTask instead, so the HTTP client itself has no timeout.
+const RequestTimeout = 60 * time.Second
+
 // SetupA2AClient creates and configures an A2A client with appropriate authentication
 func SetupA2AClient(cfg *config.Config, targetURL string) (*client.A2AClient, error) {
 	var a2aClient *client.A2AClient
@@ -20,15 +27,15 @@ func SetupA2AClient(cfg *config.Config, targetURL string) (*client.A2AClient, er
 	case "jwt":
 		// JWT authentication
 		log.Infof("Using JWT authentication for A2A client")
-		a2aClient, err = client.NewA2AClient(targetURL)
+		a2aClient, err = client.NewA2AClient(targetURL, client.WithHTTPClient(&http.Client{}))
 	case "apikey":
 		// API key authentication
 		log.Infof("Using API key authentication for A2A client (key length=%d)", len(cfg.APIKey))
-		a2aClient, err = client.NewA2AClient(targetURL, client.WithAPIKeyAuth(cfg.APIKey, "X-API-Key"))
+		a2aClient, err = client.NewA2AClient(targetURL, client.WithHTTPClient(&http.Client{}), client.WithAPIKeyAuth(cfg.APIKey, "X-API-Key"))
 	default:
 		// Default to no authentication
 		log.Warn("No authentication configured for A2A client; using unauthenticated client")
-		a2aClient, err = client.NewA2AClient(targetURL)
+		a2aClient, err = client.NewA2AClient(targetURL, client.WithHTTPClient(&http.Client{}))
 	}
 
 	if err != nil {
@@ -41,13 +48,95 @@ func SetupA2AClient(cfg *config.Config, targetURL string) (*client.A2AClient, er
 
 // SendTask synchronously sends a task via JSON-RPC and returns the consolidated Message.
 func SendTask(ctx context.Context, a2aClient *client.A2AClient, params protocol.SendTaskParams) (protocol.Message, error) {
+	ctx, cancel := context.WithTimeout(ctx, RequestTimeout)
+	defer cancel()
+
 	task, err := a2aClient.SendTasks(ctx, params)
 	if err != nil {
 		return protocol.Message{}, fmt.Errorf("SendTasks RPC failed: %w", err)
 	}
 	var parts []protocol.Part
 	for _, art := range task.Artifacts {
+		if isPartialArtifact(art) {
+			continue
+		}
 		parts = append(parts, art.Parts...)
 	}
 	return protocol.Message{Parts: parts}, nil
 }
+
+// TaskUpdate is a progress update received while a streamed task is running.
+type TaskUpdate struct {
+	State   protocol.TaskState
+	Message string // Text of the status message, if any
+	Partial string // Text of a partial artifact chunk, if any
+}
+
+// StreamTask sends a task via tasks/sendSubscribe and passes every status update and partial
+// artifact chunk to onUpdate until the task reaches a final state. It returns the consolidated
+// Message of the complete artifacts, like SendTask; a failed or canceled task is an error.
+// The stream is abandoned after timeout (0 for no limit).
+func StreamTask(ctx context.Context, a2aClient *client.A2AClient, params protocol.SendTaskParams, timeout time.Duration, onUpdate func(TaskUpdate)) (protocol.Message, error) {
+	// The server keeps the stream open after the final event, so it is closed from this side
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	events, err := a2aClient.StreamTask(ctx, params)
+	if err != nil {
+		return protocol.Message{}, fmt.Errorf("StreamTask RPC failed: %w", err)
+	}
+	var parts []protocol.Part
+	for event := range events {
+		switch e := event.(type) {
+		case protocol.TaskArtifactUpdateEvent:
+			if !isPartialArtifact(e.Artifact) {
+				parts = append(parts, e.Artifact.Parts...)
+			} else if onUpdate != nil {
+				onUpdate(TaskUpdate{State: protocol.TaskStateWorking, Partial: partsText(e.Artifact.Parts)})
+			}
+		case protocol.TaskStatusUpdateEvent:
+			var text string
+			if e.Status.Message != nil {
+				text = partsText(e.Status.Message.Parts)
+			}
+			if onUpdate != nil {
+				onUpdate(TaskUpdate{State: e.Status.State, Message: text})
+			}
+			switch e.Status.State {
+			case protocol.TaskStateCompleted:
+				return protocol.Message{Parts: parts}, nil
+			case protocol.TaskStateFailed, protocol.TaskStateCanceled:
+				return protocol.Message{}, fmt.Errorf("task %s %s: %s", params.ID, e.Status.State, text)
+			}
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return protocol.Message{}, fmt.Errorf("event stream for task %s ended before the task completed: %w", params.ID, err)
+	}
+	return protocol.Message{}, fmt.Errorf("event stream for task %s ended before the task completed", params.ID)
+}
+
+// isPartialArtifact reports whether an artifact is a chunk streamed while the task runs,
+// including the last chunk appended to a streamed artifact, rather than part of the task's result.
+func isPartialArtifact(art protocol.Artifact) bool {
+	return (art.LastChunk != nil && !*art.LastChunk) || (art.Append != nil && *art.Append)
+}
+
+// partsText joins the text parts of a message or artifact.
+func partsText(parts []protocol.Part) string {
+	var sb strings.Builder
+	for _, part := range parts {
+		switch p := part.(type) {
+		case protocol.TextPart:
+			sb.WriteString(p.Text)
+		case *protocol.TextPart:
+			sb.WriteString(p.Text)
+		}
+	}
+	return sb.String()
+}
@@ -169,3 +169,33 @@ func ExtractInfoGatheredTask(message *protocol.Message, task *models.InfoGathere
 
 	return fmt.Errorf("could not extract InfoGatheredTask from message")
 }
+
+// ExtractAcceptanceCriteria extracts drafted AcceptanceCriteria from a message
+func ExtractAcceptanceCriteria(message *protocol.Message, criteria *models.AcceptanceCriteria) error {
+	if message == nil || len(message.Parts) == 0 {
+		return fmt.Errorf("message is nil or has no parts")
+	}
+
+	for _, part := range message.Parts {
+		var dp *protocol.DataPart
+		switch v := part.(type) {
+		case *protocol.DataPart:
+			dp = v
+		case protocol.DataPart:
+			dp = &v
+		}
+		if dp == nil || dp.Data == nil {
+			continue
+		}
+		dataBytes, err := json.Marshal(dp.Data)
+		if err != nil {
+			continue
+		}
+		// Only acceptance criteria carry a criteria list next to the ticket ID
+		if err := json.Unmarshal(dataBytes, criteria); err == nil && criteria.TicketID != "" && criteria.Criteria != nil {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("could not extract AcceptanceCriteria from message")
+}
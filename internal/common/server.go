@@ -40,6 +40,8 @@ func SetupServer(opts SetupServerOptions) (*server.A2AServer, error) {
 		Provider: &server.AgentProvider{
 			Organization: "Your Organization",
 		},
+		// Progress updates and partial artifacts are available over tasks/sendSubscribe
+		Capabilities:       server.AgentCapabilities{Streaming: true},
 		DefaultInputModes:  []string{"text", "data"},
 		DefaultOutputModes: []string{"text", "data"},
 		Skills:             opts.Skills,
@@ -62,27 +64,18 @@ func SetupServer(opts SetupServerOptions) (*server.A2AServer, error) {
 	)
 
 	// Add authentication if configured
-	if opts.AuthType != "" {
-		var authProvider auth.Provider
-		switch opts.AuthType {
-		case "jwt":
-			log.Default.Infof("Configuring JWT authentication for %s", opts.AgentName)
-			authProvider = auth.NewJWTAuthProvider(
-				[]byte(opts.JWTSecret),
-				"", // audience (empty for any)
-				"", // issuer (empty for any)
-				24*time.Hour,
-			)
-		case "apikey":
-			log.Default.Infof("Configuring API key authentication for %s (API key length: %d)", opts.AgentName, len(opts.APIKey))
-			apiKeys := map[string]string{
-				opts.APIKey: "user",
-			}
-			authProvider = auth.NewAPIKeyAuthProvider(apiKeys, "X-API-Key")
-		default:
-			log.Default.Warnf("Unsupported authentication type '%s', skipping auth setup", opts.AuthType)
-			return nil, fmt.Errorf("unsupported auth type: %s", opts.AuthType)
-		}
+	authProvider, err := NewAuthProvider(opts.AuthType, opts.JWTSecret, opts.APIKey)
+	if err != nil {
+		log.Default.Warnf("Unsupported authentication type '%s', skipping auth setup", opts.AuthType)
+		return nil, err
+	}
+	switch opts.AuthType {
+	case "jwt":
+		log.Default.Infof("Configuring JWT authentication for %s", opts.AgentName)
+	case "apikey":
+		log.Default.Infof("Configuring API key authentication for %s (API key length: %d)", opts.AgentName, len(opts.APIKey))
+	}
+	if authProvider != nil {
 		serverOpts = append(serverOpts, server.WithAuthProvider(authProvider))
 	} else {
 		log.Default.Warnf("No authentication configured for %s, running unauthenticated", opts.AgentName)
@@ -97,6 +90,25 @@ func SetupServer(opts SetupServerOptions) (*server.A2AServer, error) {
 	return srv, nil
 }
 
+// NewAuthProvider returns the authentication provider for an auth type ("jwt" or "apikey"),
+// or nil when authType is empty and authentication is disabled.
+func NewAuthProvider(authType, jwtSecret, apiKey string) (auth.Provider, error) {
+	switch authType {
+	case "":
+		return nil, nil
+	case "jwt":
+		return auth.NewJWTAuthProvider(
+			[]byte(jwtSecret),
+			"", // audience (empty for any)
+			"", // issuer (empty for any)
+			24*time.Hour,
+		), nil
+	case "apikey":
+		return auth.NewAPIKeyAuthProvider(map[string]string{apiKey: "user"}, "X-API-Key"), nil
+	}
+	return nil, fmt.Errorf("unsupported auth type: %s", authType)
+}
+
 // StartServer starts the A2A server and handles graceful shutdown
 func StartServer(ctx context.Context, srv *server.A2AServer, host string, port int) error {
 	// Start the server in a goroutine
@@ -41,24 +41,113 @@ type Config struct {
 	JiraBaseURL  string `mapstructure:"jira_base_url"`
 	JiraUsername string `mapstructure:"jira_username"`
 	JiraAPIToken string `mapstructure:"jira_api_token"`
+	// Custom field holding the epic link in company-managed projects
+	JiraEpicLinkField string `mapstructure:"jira_epic_link_field"`
+	// Custom field holding story points (discovered by name when empty)
+	JiraStoryPointsField string `mapstructure:"jira_story_points_field"`
+	// How long resolved user names are cached, in seconds
+	JiraUserCacheTTL int `mapstructure:"jira_user_cache_ttl"`
+	// Issue entity property the analysis is written to (empty disables)
+	JiraAnalysisProperty string `mapstructure:"jira_analysis_property"`
+	// JSON file mapping analysis outcomes to workflow transitions
+	JiraTransitionRulesFile string `mapstructure:"jira_transition_rules_file"`
+	// Comma separated project keys transitions are applied in ("*" for all, empty disables)
+	JiraTransitionProjects string `mapstructure:"jira_transition_projects"`
+	// Log the transitions that would be applied without executing them
+	JiraTransitionDryRun bool `mapstructure:"jira_transition_dry_run"`
+	// JSON file restricting comment visibility per project and issue type
+	JiraCommentVisibilityFile string `mapstructure:"jira_comment_visibility_file"`
+	// Largest text attachment, such as a log, read for analysis in bytes (0 disables)
+	JiraAttachmentMaxBytes int `mapstructure:"jira_attachment_max_bytes"`
+	// Total size of the text attachments read for one ticket in bytes (0 for no limit)
+	JiraAttachmentTotalMaxBytes int `mapstructure:"jira_attachment_total_max_bytes"`
+	// Confidence below which analysis fields are marked or hidden in comments
+	JiraCommentMinConfidence float64 `mapstructure:"jira_comment_min_confidence"`
+	// What to do with low-confidence fields in comments: "mark", "hide" or "show"
+	JiraCommentLowConfidence string `mapstructure:"jira_comment_low_confidence"`
+	// Ask the reporter the follow-up questions of a ticket failing its Definition of Ready
+	JiraCommentQualityQuestions bool `mapstructure:"jira_comment_quality_questions"`
+	// Draft acceptance criteria and test cases: "off", "comment" to post them or "field" to write them into a field
+	JiraAcceptanceCriteria string `mapstructure:"jira_acceptance_criteria"`
+	// Field ID the drafted acceptance criteria are written into, e.g. customfield_10040
+	JiraAcceptanceCriteriaField string `mapstructure:"jira_acceptance_criteria_field"`
+	// Comma separated issue types to draft acceptance criteria for, or "*" for all
+	JiraAcceptanceCriteriaIssueTypes string `mapstructure:"jira_acceptance_criteria_issue_types"`
 
 	// Authentication
 	AuthType  string `mapstructure:"auth_type"`  // "jwt" or "apikey"
 	JWTSecret string `mapstructure:"jwt_secret"`
 	APIKey    string `mapstructure:"api_key"`
+	// How long a streamed A2A task may run before it is abandoned, in seconds
+	A2AStreamTimeout int `mapstructure:"a2a_stream_timeout"`
 	
 	// LLM configuration
-	LLMEnabled     bool    `mapstructure:"llm_enabled"`
-	LLMProvider    string  `mapstructure:"llm_provider"`  // "openai", "azure", "anthropic"
-	LLMModel       string  `mapstructure:"llm_model"`
-	LLMAPIKey      string  `mapstructure:"llm_api_key"`
-	LLMServiceURL  string  `mapstructure:"llm_service_url"`
-	LLMMaxTokens   int     `mapstructure:"llm_max_tokens"`
-	LLMTimeout     int     `mapstructure:"llm_timeout"`      // in seconds
-	LLMTemperature float64 `mapstructure:"llm_temperature"`
+	LLMEnabled          bool    `mapstructure:"llm_enabled"`
+	LLMProvider         string  `mapstructure:"llm_provider"`          // "openai", "azure", "anthropic"
+	LLMModel            string  `mapstructure:"llm_model"`
+	LLMAPIKey           string  `mapstructure:"llm_api_key"`
+	LLMServiceURL       string  `mapstructure:"llm_service_url"`
+	LLMMaxTokens        int     `mapstructure:"llm_max_tokens"`
+	LLMTimeout          int     `mapstructure:"llm_timeout"`           // in seconds
+	LLMTemperature      float64 `mapstructure:"llm_temperature"`
+	LLMRepairAttempts   int     `mapstructure:"llm_repair_attempts"`   // Re-prompts for output failing schema validation
+	LLMStructuredOutput bool    `mapstructure:"llm_structured_output"` // Use tool calling to get JSON matching the schema
+	LLMPromptDir        string  `mapstructure:"llm_prompt_dir"`        // Directory of prompt templates overriding the built-in ones
+	LLMContextWindow    int     `mapstructure:"llm_context_window"`    // Prompt plus reply size in tokens, 0 to infer from the model
+	LLMMapReduce        bool    `mapstructure:"llm_map_reduce"`        // Summarize prompt sections too large for the context instead of truncating them
+	LLMRoutingFile      string  `mapstructure:"llm_routing_file"`      // JSON file with fallback providers and per-ticket routing rules
+
+	// Confidence of LLM analyses
+	// Additional analyses sampled to measure how consistently each field is answered (0 disables)
+	LLMConfidenceSamples int `mapstructure:"llm_confidence_samples"`
+	// Temperature the additional analyses are sampled at
+	LLMConfidenceTemperature float64 `mapstructure:"llm_confidence_temperature"`
+	// Request token log probabilities for the additional analyses (OpenAI-compatible providers)
+	LLMConfidenceLogProbs bool `mapstructure:"llm_confidence_logprobs"`
 	
 	// Webhook configuration
 	WebhookPort int `mapstructure:"webhook_port"`
+	// Register webhooks through Jira's dynamic webhook API on startup (Connect and OAuth 2.0 apps only)
+	WebhookAutoRegister bool `mapstructure:"webhook_auto_register"`
+	// URL Jira delivers webhooks to (defaults to http://{server_host}:{webhook_port}/webhook)
+	WebhookURL string `mapstructure:"webhook_url"`
+	// Comma separated project keys to receive events for
+	WebhookProjects string `mapstructure:"webhook_projects"`
+	// Additional JQL restricting which issues trigger webhooks
+	WebhookJQL string `mapstructure:"webhook_jql"`
+	// Comma separated webhook events to subscribe to
+	WebhookEvents string `mapstructure:"webhook_events"`
+
+	// Similar ticket detection
+	// File holding the vector index of analyzed tickets (empty disables)
+	SimilarityIndexFile string `mapstructure:"similarity_index_file"`
+	// Number of similar tickets returned with each analysis
+	SimilarityTopK int `mapstructure:"similarity_top_k"`
+	// Minimum cosine similarity for a ticket to be returned
+	SimilarityMinScore float64 `mapstructure:"similarity_min_score"`
+	// Cosine similarity above which a ticket is reported as a probable duplicate
+	SimilarityDuplicateThreshold float64 `mapstructure:"similarity_duplicate_threshold"`
+	// Embedding provider: "hash" (local, no external calls) or "openai"
+	EmbeddingProvider string `mapstructure:"embedding_provider"`
+	// Embedding model used by the openai provider
+	EmbeddingModel string `mapstructure:"embedding_model"`
+
+	// Redaction of personal data and secrets before content is sent to the LLM
+	RedactionEnabled bool `mapstructure:"redaction_enabled"`
+	// JSON file with additional patterns, internal domains and dictionaries to redact
+	RedactionRulesFile string `mapstructure:"redaction_rules_file"`
+
+	// Analysis cache
+	// File holding the latest analysis of each ticket (empty disables)
+	AnalysisCacheFile string `mapstructure:"analysis_cache_file"`
+	// How long a cached analysis is reused, in seconds (0 keeps it until the ticket changes)
+	AnalysisCacheTTL int `mapstructure:"analysis_cache_ttl"`
+
+	// Definition-of-Ready quality assessment
+	// Assess every analyzed ticket against the checklist of its issue type
+	QualityEnabled bool `mapstructure:"quality_enabled"`
+	// JSON file with the checklist of each issue type (empty uses the built-in checklists)
+	QualityChecklistFile string `mapstructure:"quality_checklist_file"`
 }
 
 // viperInstance is the singleton instance of viper
@@ -134,11 +223,28 @@ func setDefaults() {
 	viperInstance.SetDefault("jira_base_url", "https://your-jira-instance.atlassian.net")
 	viperInstance.SetDefault("jira_username", "")
 	viperInstance.SetDefault("jira_api_token", "")
+	viperInstance.SetDefault("jira_epic_link_field", "customfield_10014")
+	viperInstance.SetDefault("jira_story_points_field", "")
+	viperInstance.SetDefault("jira_user_cache_ttl", 3600)
+	viperInstance.SetDefault("jira_analysis_property", "")
+	viperInstance.SetDefault("jira_transition_rules_file", "")
+	viperInstance.SetDefault("jira_transition_projects", "")
+	viperInstance.SetDefault("jira_transition_dry_run", true)
+	viperInstance.SetDefault("jira_comment_visibility_file", "")
+	viperInstance.SetDefault("jira_attachment_max_bytes", 1048576)
+	viperInstance.SetDefault("jira_attachment_total_max_bytes", 4194304)
+	viperInstance.SetDefault("jira_comment_min_confidence", 0.5)
+	viperInstance.SetDefault("jira_comment_low_confidence", "mark")
+	viperInstance.SetDefault("jira_comment_quality_questions", true)
+	viperInstance.SetDefault("jira_acceptance_criteria", "off")
+	viperInstance.SetDefault("jira_acceptance_criteria_field", "")
+	viperInstance.SetDefault("jira_acceptance_criteria_issue_types", "Story")
 	
 	// Authentication
 	viperInstance.SetDefault("auth_type", "apikey") // "jwt" or "apikey"
 	viperInstance.SetDefault("jwt_secret", "your-jwt-secret")
 	viperInstance.SetDefault("api_key", "your-api-key")
+	viperInstance.SetDefault("a2a_stream_timeout", 600)
 	
 	// LLM configuration
 	viperInstance.SetDefault("llm_enabled", false)
@@ -149,9 +255,43 @@ func setDefaults() {
 	viperInstance.SetDefault("llm_max_tokens", 4000)
 	viperInstance.SetDefault("llm_timeout", 30)
 	viperInstance.SetDefault("llm_temperature", 0.0)
+	viperInstance.SetDefault("llm_repair_attempts", 2)
+	viperInstance.SetDefault("llm_structured_output", true)
+	viperInstance.SetDefault("llm_prompt_dir", "")
+	viperInstance.SetDefault("llm_context_window", 0)
+	viperInstance.SetDefault("llm_map_reduce", true)
+	viperInstance.SetDefault("llm_routing_file", "")
+	viperInstance.SetDefault("llm_confidence_samples", 0)
+	viperInstance.SetDefault("llm_confidence_temperature", 0.7)
+	viperInstance.SetDefault("llm_confidence_logprobs", false)
 	
 	// Webhook configuration
 	viperInstance.SetDefault("webhook_port", DefaultWebhookPort)
+	viperInstance.SetDefault("webhook_auto_register", false)
+	viperInstance.SetDefault("webhook_url", "")
+	viperInstance.SetDefault("webhook_projects", "")
+	viperInstance.SetDefault("webhook_jql", "")
+	viperInstance.SetDefault("webhook_events", "jira:issue_created,jira:issue_updated,comment_created")
+	
+	// Similar ticket detection
+	viperInstance.SetDefault("similarity_index_file", "")
+	viperInstance.SetDefault("similarity_top_k", 5)
+	viperInstance.SetDefault("similarity_min_score", 0.3)
+	viperInstance.SetDefault("similarity_duplicate_threshold", 0.85)
+	viperInstance.SetDefault("embedding_provider", "hash")
+	viperInstance.SetDefault("embedding_model", "text-embedding-3-small")
+	
+	// Redaction
+	viperInstance.SetDefault("redaction_enabled", true)
+	viperInstance.SetDefault("redaction_rules_file", "")
+
+	// Analysis cache
+	viperInstance.SetDefault("analysis_cache_file", "")
+	viperInstance.SetDefault("analysis_cache_ttl", 604800)
+
+	// Definition-of-Ready quality assessment
+	viperInstance.SetDefault("quality_enabled", false)
+	viperInstance.SetDefault("quality_checklist_file", "")
 }
 
 // NewConfig creates a new configuration with values from environment variables and .env file
@@ -0,0 +1,232 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// TransitionRule maps an analysis outcome to a workflow transition.
+// A rule matches when every entry in When equals the analysis field of the same
+// name (case-insensitively); the first matching rule is applied.
+type TransitionRule struct {
+	Name       string                 `json:"name"`
+	When       map[string]string      `json:"when"`               // Analysis field -> value, e.g. {"RequiresClarification": "true"}
+	Projects   []string               `json:"projects,omitempty"` // Restrict the rule to these projects
+	Transition string                 `json:"transition"`         // Transition name, ID or target status name
+	Fields     map[string]interface{} `json:"fields,omitempty"`   // Fields set during the transition
+	Comment    string                 `json:"comment,omitempty"`  // Comment added with the transition
+}
+
+// LoadTransitionRules reads transition rules from a JSON file containing an array of rules.
+// An empty path returns no rules.
+func LoadTransitionRules(path string) ([]TransitionRule, error) {
+	var rules []TransitionRule
+	if err := readJSONFile(path, "transition rules", &rules); err != nil {
+		return nil, err
+	}
+	for i, rule := range rules {
+		if strings.TrimSpace(rule.Transition) == "" {
+			return nil, fmt.Errorf("transition rule %d (%s) has no transition", i, rule.Name)
+		}
+		if len(rule.When) == 0 {
+			return nil, fmt.Errorf("transition rule %d (%s) has no conditions", i, rule.Name)
+		}
+	}
+	return rules, nil
+}
+
+// CommentVisibilityRule restricts who can see analysis comments for matching tickets.
+// Empty Projects or IssueTypes match any project or issue type; the first matching rule is applied.
+type CommentVisibilityRule struct {
+	Projects   []string `json:"projects,omitempty"`
+	IssueTypes []string `json:"issueTypes,omitempty"`
+	Type       string   `json:"type,omitempty"`     // "role" or "group"
+	Value      string   `json:"value,omitempty"`    // Role or group name
+	Internal   bool     `json:"internal,omitempty"` // Jira Service Management internal note (sd.public.comment)
+	Public     bool     `json:"public,omitempty"`   // Post unrestricted, overriding the service desk default
+}
+
+// LoadCommentVisibilityRules reads comment visibility rules from a JSON file containing an array of rules.
+// An empty path returns no rules.
+func LoadCommentVisibilityRules(path string) ([]CommentVisibilityRule, error) {
+	var rules []CommentVisibilityRule
+	if err := readJSONFile(path, "comment visibility rules", &rules); err != nil {
+		return nil, err
+	}
+	for i, rule := range rules {
+		if rule.Type != "" && rule.Type != "role" && rule.Type != "group" {
+			return nil, fmt.Errorf("comment visibility rule %d has unsupported type %q", i, rule.Type)
+		}
+		if (rule.Type == "") != (rule.Value == "") {
+			return nil, fmt.Errorf("comment visibility rule %d must set both type and value", i)
+		}
+		if rule.Public && (rule.Type != "" || rule.Internal) {
+			return nil, fmt.Errorf("comment visibility rule %d cannot be public and restricted", i)
+		}
+	}
+	return rules, nil
+}
+
+// readJSONFile decodes a JSON file into out, leaving out untouched when path is empty.
+func readJSONFile(path, what string, out interface{}) error {
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", what, err)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", what, err)
+	}
+	return nil
+}
+
+// RedactionRules customizes what is redacted from ticket content before it is sent to the LLM.
+type RedactionRules struct {
+	Disable    []string            `json:"disable,omitempty"`    // Built-in detectors to turn off, e.g. "phone"
+	Patterns   []RedactionPattern  `json:"patterns,omitempty"`   // Additional regular expressions
+	Domains    []string            `json:"domains,omitempty"`    // Internal domains whose host names are redacted
+	Dictionary map[string][]string `json:"dictionary,omitempty"` // Category -> terms, e.g. {"customer": ["Acme Corp"]}
+}
+
+// RedactionPattern is a named regular expression whose matches are redacted.
+type RedactionPattern struct {
+	Name    string `json:"name"`
+	Pattern string `json:"pattern"`
+}
+
+// LoadRedactionRules reads redaction rules from a JSON file. An empty path returns empty rules.
+func LoadRedactionRules(path string) (*RedactionRules, error) {
+	rules := &RedactionRules{}
+	if err := readJSONFile(path, "redaction rules", rules); err != nil {
+		return nil, err
+	}
+	for i, p := range rules.Patterns {
+		if p.Name == "" || p.Pattern == "" {
+			return nil, fmt.Errorf("redaction pattern %d must set both name and pattern", i)
+		}
+	}
+	return rules, nil
+}
+
+// LLMRouting adds LLM providers to the one configured with LLM_PROVIDER and LLM_MODEL, which
+// is named "default", and decides which of them analyze which tickets.
+type LLMRouting struct {
+	Providers []LLMProvider `json:"providers"`
+	// Routes send matching tickets to their providers first; the first matching route is used
+	Routes []LLMRoute `json:"routes,omitempty"`
+	// Providers tried in order after the route's own, and for tickets no route matches
+	// (defaults to "default" followed by the providers in file order)
+	Fallback []string `json:"fallback,omitempty"`
+	// Consecutive failures after which a provider is skipped (default 3)
+	FailureThreshold int `json:"failureThreshold,omitempty"`
+	// Seconds a failing provider is skipped before it is tried again (default 60)
+	Cooldown int `json:"cooldown,omitempty"`
+}
+
+// LLMProvider is a named LLM provider and model. The API key, max tokens and timeout
+// default to the LLM_* configuration.
+type LLMProvider struct {
+	Name       string `json:"name"`
+	Provider   string `json:"provider"` // "openai", "azure" or "anthropic"
+	Model      string `json:"model"`
+	APIKeyEnv  string `json:"apiKeyEnv,omitempty"` // Environment variable holding the API key
+	ServiceURL string `json:"serviceUrl,omitempty"`
+	MaxTokens  int    `json:"maxTokens,omitempty"`
+	Timeout    int    `json:"timeout,omitempty"` // in seconds
+}
+
+// LLMRoute sends tickets matching all of its conditions to its providers, in order.
+// Empty conditions match any ticket.
+type LLMRoute struct {
+	Name        string   `json:"name"`
+	Projects    []string `json:"projects,omitempty"`
+	IssueTypes  []string `json:"issueTypes,omitempty"`
+	Priorities  []string `json:"priorities,omitempty"`
+	MinTokens   int      `json:"minTokens,omitempty"`   // Ticket description and comments size
+	MaxTokens   int      `json:"maxTokens,omitempty"`   // Ticket description and comments size
+	MinComments int      `json:"minComments,omitempty"` // Number of comments
+	Providers   []string `json:"providers"`
+}
+
+// LoadLLMRouting reads LLM providers and routing rules from a JSON file. An empty path returns nil.
+func LoadLLMRouting(path string) (*LLMRouting, error) {
+	if path == "" {
+		return nil, nil
+	}
+	routing := &LLMRouting{}
+	if err := readJSONFile(path, "LLM routing", routing); err != nil {
+		return nil, err
+	}
+	known := map[string]bool{"default": true}
+	for i, p := range routing.Providers {
+		if p.Name == "" || p.Provider == "" || p.Model == "" {
+			return nil, fmt.Errorf("LLM provider %d must set name, provider and model", i)
+		}
+		if known[p.Name] {
+			return nil, fmt.Errorf("LLM provider %d reuses the name %q", i, p.Name)
+		}
+		known[p.Name] = true
+	}
+	for i, route := range routing.Routes {
+		if len(route.Providers) == 0 {
+			return nil, fmt.Errorf("LLM route %d (%s) has no providers", i, route.Name)
+		}
+		for _, name := range route.Providers {
+			if !known[name] {
+				return nil, fmt.Errorf("LLM route %d (%s) uses unknown provider %q", i, route.Name, name)
+			}
+		}
+	}
+	for _, name := range routing.Fallback {
+		if !known[name] {
+			return nil, fmt.Errorf("LLM fallback uses unknown provider %q", name)
+		}
+	}
+	return routing, nil
+}
+
+// QualityCheck is an item of a Definition-of-Ready checklist, such as steps to reproduce.
+type QualityCheck struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"` // What the item asks for, as explained to the LLM
+	Patterns    []string `json:"patterns,omitempty"`    // Regular expressions whose match in the ticket satisfies the item
+	Question    string   `json:"question,omitempty"`    // Follow-up question asked when the item is missing
+	Weight      int      `json:"weight,omitempty"`      // Share of the score (default 1)
+}
+
+// QualityChecklists maps issue types to the checklist their tickets are scored against.
+// The "*" checklist applies to issue types without one of their own.
+type QualityChecklists map[string][]QualityCheck
+
+// LoadQualityChecklists reads Definition-of-Ready checklists from a JSON file mapping issue
+// types to lists of checks. An empty path returns nil.
+func LoadQualityChecklists(path string) (QualityChecklists, error) {
+	var checklists QualityChecklists
+	if err := readJSONFile(path, "quality checklists", &checklists); err != nil {
+		return nil, err
+	}
+	for issueType, checks := range checklists {
+		ids := make(map[string]bool)
+		for i, check := range checks {
+			if check.ID == "" || check.Name == "" {
+				return nil, fmt.Errorf("quality check %d of %s must set id and name", i, issueType)
+			}
+			if ids[check.ID] {
+				return nil, fmt.Errorf("quality checklist %s has duplicate check %q", issueType, check.ID)
+			}
+			ids[check.ID] = true
+			for _, p := range check.Patterns {
+				if _, err := regexp.Compile(p); err != nil {
+					return nil, fmt.Errorf("quality check %s of %s has invalid pattern: %w", check.ID, issueType, err)
+				}
+			}
+		}
+	}
+	return checklists, nil
+}
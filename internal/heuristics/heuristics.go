@@ -0,0 +1,278 @@
+// Package heuristics provides a deterministic, pattern-based ticket analysis.
+// It is used when the LLM is disabled or fails, and merged with the LLM analysis
+// when both run so that facts found by patterns are never lost.
+package heuristics
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/tuannvm/jira-a2a/internal/models"
+	"github.com/tuannvm/jira-a2a/internal/stacktrace"
+)
+
+var (
+	ticketKeyPattern   = regexp.MustCompile(`\b[A-Z][A-Z0-9_]+-\d+\b`)
+	errorCodePattern   = regexp.MustCompile(`\b(?:(?:ERR|ERROR|ORA|SQLSTATE|HTTP|E)[-_ ]?\d{3,}|0x[0-9A-Fa-f]{4,}|[A-Z][A-Za-z0-9]*(?:Exception|Error))\b`)
+	urlPattern         = regexp.MustCompile(`https?://[^\s<>"'\])|]+`)
+	versionPattern     = regexp.MustCompile(`(?i)\b(?:version|ver\.?|release|v)\s*:?\s*(\d+(?:\.\d+){1,3}(?:[-+][0-9A-Za-z.]+)?)\b`)
+	semverPattern      = regexp.MustCompile(`\b\d+\.\d+\.\d+(?:-[0-9A-Za-z.]+)?\b`)
+	envLinePattern     = regexp.MustCompile(`(?im)^\s*[*-]?\s*(environment|env|os|browser|platform|device|region|cluster)\s*[:=]\s*(.+?)\s*$`)
+	envKeywordPattern  = regexp.MustCompile(`(?i)\b(production|staging|sandbox|windows|macos|linux|ios|android|chrome|firefox|safari|edge)\b`)
+	reproPattern       = regexp.MustCompile(`(?i)(steps to reproduce|to reproduce|repro(?:duction)? steps|how to reproduce|\bSTR\b)`)
+	numberedStepLine   = regexp.MustCompile(`(?m)^\s*(?:\d+[.)]|#)\s+\S`)
+	criticalKeywords   = regexp.MustCompile(`(?i)\b(outage|data loss|security breach|production (?:is )?down|sev ?1|all users)\b`)
+	highKeywords       = regexp.MustCompile(`(?i)\b(urgent|asap|blocker|blocking|crash(?:es|ed|ing)?|cannot log ?in|customers? (?:are )?affected)\b`)
+	negativeKeywords   = regexp.MustCompile(`(?i)\b(not working|broken|fails?|failed|failing|error|frustrat\w*|unacceptable|again)\b`)
+	positiveKeywords   = regexp.MustCompile(`(?i)\b(thanks?|thank you|great|appreciate|works now)\b`)
+	nonTicketPrefixes  = map[string]bool{"ERR": true, "ERROR": true, "ORA": true, "SQLSTATE": true, "HTTP": true, "CVE": true, "UTF": true, "ISO": true, "SHA": true, "MD": true}
+	bugLikeIssueTypes  = map[string]bool{"bug": true, "defect": true, "incident": true, "problem": true}
+	urgencyByPriority  = map[string]models.Urgency{"highest": models.UrgencyCritical, "blocker": models.UrgencyCritical, "critical": models.UrgencyHigh, "high": models.UrgencyHigh, "medium": models.UrgencyMedium, "low": models.UrgencyLow, "lowest": models.UrgencyLow, "trivial": models.UrgencyLow}
+	urgencyRank        = map[models.Urgency]int{models.UrgencyLow: 1, models.UrgencyMedium: 2, models.UrgencyHigh: 3, models.UrgencyCritical: 4}
+	effortByStoryPoint = []struct {
+		max    float64
+		effort models.Effort
+	}{{2, models.EffortSmall}, {5, models.EffortMedium}, {8, models.EffortLarge}}
+)
+
+// Analyze derives an analysis from the ticket text and fields alone. now is used to judge the due date.
+func Analyze(task *models.TicketAvailableTask, now time.Time) *models.Analysis {
+	text := ticketText(task)
+	result := &models.Analysis{
+		SchemaVersion: models.AnalysisSchemaVersion,
+		Source:        models.AnalysisSourceHeuristic,
+		Sentiment:     sentiment(text),
+	}
+
+	result.RelatedTickets = relatedTickets(task, text)
+	result.DetectedEntities = entities(text)
+
+	trace, hasTrace := firstStackTrace(task)
+	if hasTrace {
+		result.DetectedEntities = append(result.DetectedEntities, models.Entity{Type: "stack_trace", Value: trace})
+		result.KeyInformation = append(result.KeyInformation, "Stack trace: "+trace)
+	}
+
+	var urgencyReason string
+	result.Urgency, urgencyReason = urgency(task, text, now)
+	if urgencyReason != "" {
+		result.KeyInformation = append(result.KeyInformation, urgencyReason)
+	}
+
+	if task.Agile != nil && task.Agile.StoryPoints != nil {
+		result.EstimatedEffort = effort(*task.Agile.StoryPoints)
+	}
+
+	switch {
+	case strings.TrimSpace(task.Description) == "":
+		result.RequiresClarification = true
+		result.KeyInformation = append(result.KeyInformation, "Description is empty")
+		result.SuggestedAction = "Ask the reporter to describe the problem or request"
+	case isBugLike(task, hasTrace) && !hasReproSteps(task):
+		result.RequiresClarification = true
+		result.KeyInformation = append(result.KeyInformation, "No steps to reproduce found")
+		result.SuggestedAction = "Ask the reporter for steps to reproduce, the expected and the actual behaviour"
+	case hasTrace:
+		result.SuggestedAction = "Investigate the reported stack trace: " + trace
+	}
+	return result
+}
+
+// Merge combines an LLM analysis with a heuristic one. The LLM's judgement wins for
+// fields both produce, and heuristic values only fill the fields the LLM left empty.
+// Lists are combined and a clarification need found by either is kept, so facts found
+// by patterns are never lost.
+func Merge(llm, heuristic *models.Analysis) *models.Analysis {
+	merged := *llm
+	merged.Source = models.AnalysisSourceMerged
+
+	if merged.Urgency == "" {
+		merged.Urgency = heuristic.Urgency
+	}
+	if merged.Sentiment == "" {
+		merged.Sentiment = heuristic.Sentiment
+	}
+	if merged.EstimatedEffort == "" {
+		merged.EstimatedEffort = heuristic.EstimatedEffort
+	}
+	if merged.SuggestedAction == "" {
+		merged.SuggestedAction = heuristic.SuggestedAction
+	}
+	if heuristic.RequiresClarification && !merged.RequiresClarification {
+		merged.RequiresClarification = true
+		merged.KeyInformation = appendUnique(merged.KeyInformation, heuristic.KeyInformation...)
+	}
+	merged.RelatedTickets = appendUnique(merged.RelatedTickets, heuristic.RelatedTickets...)
+
+	seen := make(map[string]bool)
+	merged.DetectedEntities = nil
+	for _, e := range append(append([]models.Entity(nil), llm.DetectedEntities...), heuristic.DetectedEntities...) {
+		key := strings.ToLower(e.Type + "\x00" + e.Value)
+		if !seen[key] {
+			seen[key] = true
+			merged.DetectedEntities = append(merged.DetectedEntities, e)
+		}
+	}
+	return &merged
+}
+
+// ticketText joins the free text of a ticket that patterns are matched against.
+func ticketText(task *models.TicketAvailableTask) string {
+	parts := []string{task.Summary, task.Description}
+	for _, c := range task.Comments {
+		parts = append(parts, c.Body)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// relatedTickets returns the ticket keys mentioned in text, other than the ticket itself.
+func relatedTickets(task *models.TicketAvailableTask, text string) []string {
+	var keys []string
+	for _, key := range ticketKeyPattern.FindAllString(text, -1) {
+		if key == task.TicketID || nonTicketPrefixes[key[:strings.LastIndex(key, "-")]] {
+			continue
+		}
+		keys = appendUnique(keys, key)
+	}
+	return keys
+}
+
+// entities extracts error codes, URLs, versions and environment details from text.
+func entities(text string) []models.Entity {
+	var found []models.Entity
+	seen := make(map[string]bool)
+	add := func(typ, value string) {
+		value = strings.TrimRight(strings.TrimSpace(value), ".,;:")
+		key := typ + "\x00" + strings.ToLower(value)
+		if value != "" && !seen[key] {
+			seen[key] = true
+			found = append(found, models.Entity{Type: typ, Value: value})
+		}
+	}
+
+	for _, code := range errorCodePattern.FindAllString(text, -1) {
+		add("error_code", code)
+	}
+	for _, url := range urlPattern.FindAllString(text, -1) {
+		add("url", url)
+	}
+	for _, m := range versionPattern.FindAllStringSubmatch(text, -1) {
+		add("version", m[1])
+	}
+	for _, loc := range semverPattern.FindAllStringIndex(text, -1) {
+		// Skip parts of longer dotted numbers such as IP addresses
+		if loc[1] < len(text)-1 && text[loc[1]] == '.' && text[loc[1]+1] >= '0' && text[loc[1]+1] <= '9' {
+			continue
+		}
+		if loc[0] > 0 && text[loc[0]-1] == '.' {
+			continue
+		}
+		add("version", text[loc[0]:loc[1]])
+	}
+	for _, m := range envLinePattern.FindAllStringSubmatch(text, -1) {
+		add("environment", fmt.Sprintf("%s: %s", strings.ToLower(m[1]), m[2]))
+	}
+	for _, m := range envKeywordPattern.FindAllString(text, -1) {
+		add("environment", strings.ToLower(m))
+	}
+	return found
+}
+
+// firstStackTrace returns the headline of the first stack trace in the ticket's description,
+// comments or attachments: the exception or panic that introduces it.
+func firstStackTrace(task *models.TicketAvailableTask) (string, bool) {
+	d := stacktrace.Extract(task)
+	if d == nil || len(d.StackTraces) == 0 {
+		return "", false
+	}
+	return d.StackTraces[0].Headline(), true
+}
+
+// urgency infers urgency from the priority, raised by alarming keywords and a near or past due date.
+// It also returns the reason for raising it, if any.
+func urgency(task *models.TicketAvailableTask, text string, now time.Time) (models.Urgency, string) {
+	result, ok := urgencyByPriority[strings.ToLower(strings.TrimSpace(task.Priority))]
+	if !ok {
+		result = models.UrgencyMedium
+	}
+	reason := ""
+	raise := func(to models.Urgency, why string) {
+		if urgencyRank[to] > urgencyRank[result] {
+			result, reason = to, why
+		}
+	}
+
+	if m := criticalKeywords.FindString(text); m != "" {
+		raise(models.UrgencyCritical, fmt.Sprintf("Mentions %q", m))
+	} else if m := highKeywords.FindString(text); m != "" {
+		raise(models.UrgencyHigh, fmt.Sprintf("Mentions %q", m))
+	}
+
+	if due, err := time.Parse("2006-01-02", task.DueDate); err == nil {
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		days := int(due.Sub(today).Hours() / 24)
+		switch {
+		case days < 0:
+			raise(models.UrgencyCritical, fmt.Sprintf("Overdue since %s", task.DueDate))
+		case days <= 3:
+			raise(models.UrgencyHigh, fmt.Sprintf("Due %s (in %d day(s))", task.DueDate, days))
+		}
+	}
+	return result, reason
+}
+
+// sentiment classifies the tone of text by keywords.
+func sentiment(text string) models.Sentiment {
+	negative := len(negativeKeywords.FindAllString(text, -1))
+	positive := len(positiveKeywords.FindAllString(text, -1))
+	switch {
+	case negative > positive:
+		return models.SentimentNegative
+	case positive > negative:
+		return models.SentimentPositive
+	}
+	return models.SentimentNeutral
+}
+
+// effort maps story points to a t-shirt size.
+func effort(points float64) models.Effort {
+	for _, e := range effortByStoryPoint {
+		if points <= e.max {
+			return e.effort
+		}
+	}
+	return models.EffortXLarge
+}
+
+// isBugLike reports whether a ticket reports a defect, by issue type or, without one, by a stack trace.
+func isBugLike(task *models.TicketAvailableTask, hasTrace bool) bool {
+	if task.IssueType != "" {
+		return bugLikeIssueTypes[strings.ToLower(task.IssueType)]
+	}
+	return hasTrace
+}
+
+// hasReproSteps reports whether the description contains steps to reproduce,
+// either under a heading or as a numbered list of at least two steps.
+func hasReproSteps(task *models.TicketAvailableTask) bool {
+	return reproPattern.MatchString(task.Description) || len(numberedStepLine.FindAllString(task.Description, -1)) >= 2
+}
+
+// appendUnique appends the values not already present, keeping order.
+func appendUnique(list []string, values ...string) []string {
+	for _, v := range values {
+		found := false
+		for _, existing := range list {
+			if strings.EqualFold(existing, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, v)
+		}
+	}
+	return list
+}
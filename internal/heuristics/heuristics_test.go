@@ -0,0 +1,137 @@
+package heuristics
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tuannvm/jira-a2a/internal/models"
+)
+
+var now = time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC)
+
+func TestAnalyzeUrgency(t *testing.T) {
+	tests := []struct {
+		name     string
+		task     models.TicketAvailableTask
+		want     models.Urgency
+		wantInfo string
+	}{
+		{name: "by priority", task: models.TicketAvailableTask{Priority: "Low"}, want: models.UrgencyLow},
+		{name: "unknown priority", task: models.TicketAvailableTask{Priority: "P3"}, want: models.UrgencyMedium},
+		{name: "critical keyword", task: models.TicketAvailableTask{Priority: "Low", Summary: "Production down for all users"}, want: models.UrgencyCritical, wantInfo: `Mentions "Production down"`},
+		{name: "high keyword", task: models.TicketAvailableTask{Priority: "Medium", Description: "This is a blocker for the release"}, want: models.UrgencyHigh, wantInfo: `Mentions "blocker"`},
+		{name: "keyword does not lower", task: models.TicketAvailableTask{Priority: "Highest", Description: "urgent"}, want: models.UrgencyCritical},
+		{name: "overdue", task: models.TicketAvailableTask{Priority: "Low", DueDate: "2025-01-10"}, want: models.UrgencyCritical, wantInfo: "Overdue since 2025-01-10"},
+		{name: "due soon", task: models.TicketAvailableTask{Priority: "Low", DueDate: "2025-01-17"}, want: models.UrgencyHigh, wantInfo: "Due 2025-01-17 (in 2 day(s))"},
+		{name: "due later", task: models.TicketAvailableTask{Priority: "Low", DueDate: "2025-02-01"}, want: models.UrgencyLow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.task.Description += "\nSteps to reproduce: none needed"
+			got := Analyze(&tt.task, now)
+			if got.Urgency != tt.want {
+				t.Errorf("urgency = %s, want %s", got.Urgency, tt.want)
+			}
+			var info string
+			if len(got.KeyInformation) > 0 {
+				info = got.KeyInformation[0]
+			}
+			if info != tt.wantInfo {
+				t.Errorf("key information = %v, want %q", got.KeyInformation, tt.wantInfo)
+			}
+		})
+	}
+}
+
+func TestAnalyzeClarification(t *testing.T) {
+	tests := []struct {
+		name   string
+		task   models.TicketAvailableTask
+		want   bool
+		action string
+	}{
+		{name: "empty description", task: models.TicketAvailableTask{IssueType: "Story"}, want: true, action: "Ask the reporter to describe the problem or request"},
+		{name: "bug without steps", task: models.TicketAvailableTask{IssueType: "Bug", Description: "Login is broken"}, want: true, action: "Ask the reporter for steps to reproduce, the expected and the actual behaviour"},
+		{name: "bug with numbered steps", task: models.TicketAvailableTask{IssueType: "Bug", Description: "1. Open the portal\n2. Click sign in"}},
+		{name: "story without steps", task: models.TicketAvailableTask{IssueType: "Story", Description: "As a user I want SSO"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Analyze(&tt.task, now)
+			if got.RequiresClarification != tt.want || got.SuggestedAction != tt.action {
+				t.Errorf("clarification = %v, action = %q; want %v, %q", got.RequiresClarification, got.SuggestedAction, tt.want, tt.action)
+			}
+		})
+	}
+}
+
+func TestAnalyzeEntities(t *testing.T) {
+	points := 5.0
+	task := &models.TicketAvailableTask{
+		TicketID:    "PROJ-2",
+		Summary:     "Login fails with SSO",
+		Description: "Broken since release 2.3.1, see PROJ-7 and https://status.example.com/incident.\nSeen ERR-5001 from 10.0.1.25\nBrowser: Firefox 128\nThanks",
+		Comments:    []models.JiraComment{{Body: "Also PROJ-2 and PROJ-7, HTTP-500 in production"}},
+		Agile:       &models.AgileContext{StoryPoints: &points},
+	}
+	got := Analyze(task, now)
+
+	if want := []string{"PROJ-7"}; !reflect.DeepEqual(got.RelatedTickets, want) {
+		t.Errorf("related tickets = %v, want %v", got.RelatedTickets, want)
+	}
+	want := []models.Entity{
+		{Type: "error_code", Value: "ERR-5001"},
+		{Type: "error_code", Value: "HTTP-500"},
+		{Type: "url", Value: "https://status.example.com/incident"},
+		{Type: "version", Value: "2.3.1"},
+		{Type: "environment", Value: "browser: Firefox 128"},
+		{Type: "environment", Value: "firefox"},
+		{Type: "environment", Value: "production"},
+	}
+	if !reflect.DeepEqual(got.DetectedEntities, want) {
+		t.Errorf("entities = %+v, want %+v", got.DetectedEntities, want)
+	}
+	if got.EstimatedEffort != models.EffortMedium {
+		t.Errorf("effort = %s, want Medium for 5 points", got.EstimatedEffort)
+	}
+	if got.Sentiment != models.SentimentNegative {
+		t.Errorf("sentiment = %s", got.Sentiment)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	llm := &models.Analysis{
+		Urgency:          models.UrgencyHigh,
+		KeyInformation:   []string{"SSO fails"},
+		RelatedTickets:   []string{"PROJ-7"},
+		DetectedEntities: []models.Entity{{Type: "error_code", Value: "AUTH-401"}},
+		Source:           models.AnalysisSourceLLM,
+	}
+	heuristic := &models.Analysis{
+		Urgency:               models.UrgencyCritical,
+		Sentiment:             models.SentimentNegative,
+		EstimatedEffort:       models.EffortSmall,
+		KeyInformation:        []string{"No steps to reproduce found"},
+		RelatedTickets:        []string{"proj-7", "PROJ-9"},
+		DetectedEntities:      []models.Entity{{Type: "error_code", Value: "auth-401"}, {Type: "version", Value: "2.3.1"}},
+		RequiresClarification: true,
+	}
+	got := Merge(llm, heuristic)
+	want := &models.Analysis{
+		Urgency:               models.UrgencyHigh,
+		Sentiment:             models.SentimentNegative,
+		EstimatedEffort:       models.EffortSmall,
+		KeyInformation:        []string{"SSO fails", "No steps to reproduce found"},
+		RelatedTickets:        []string{"PROJ-7", "PROJ-9"},
+		DetectedEntities:      []models.Entity{{Type: "error_code", Value: "AUTH-401"}, {Type: "version", Value: "2.3.1"}},
+		RequiresClarification: true,
+		Source:                models.AnalysisSourceMerged,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Merge = %+v\nwant %+v", got, want)
+	}
+	if len(llm.KeyInformation) != 1 || llm.Source != models.AnalysisSourceLLM {
+		t.Errorf("Merge modified the LLM analysis: %+v", llm)
+	}
+}
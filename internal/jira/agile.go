@@ -0,0 +1,224 @@
+package jira
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/tuannvm/jira-a2a/internal/logging"
+)
+
+// storyPointsFieldNames are the names Jira uses for the story points field in
+// company-managed and team-managed projects
+var storyPointsFieldNames = []string{"Story Points", "Story point estimate"}
+
+// ClientJiraSprint represents an agile sprint
+type ClientJiraSprint struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	State     string `json:"state"` // "active", "future" or "closed"
+	Goal      string `json:"goal,omitempty"`
+	StartDate string `json:"startDate,omitempty"`
+	EndDate   string `json:"endDate,omitempty"`
+	BoardID   int    `json:"originBoardId,omitempty"`
+}
+
+// ClientJiraAgileContext describes the sprint and board an issue belongs to
+type ClientJiraAgileContext struct {
+	Sprint          *ClientJiraSprint `json:"sprint,omitempty"` // Active or future sprint, nil when in the backlog
+	ClosedSprints   int               `json:"closedSprints"`    // Number of closed sprints the issue was carried over from
+	BoardID         int               `json:"boardId,omitempty"`
+	BoardName       string            `json:"boardName,omitempty"`
+	StoryPoints     *float64          `json:"storyPoints,omitempty"`
+	AddedToSprintAt string            `json:"addedToSprintAt,omitempty"` // When the issue was last moved into Sprint
+	AddedMidSprint  bool              `json:"addedMidSprint"`            // Added after the active sprint started
+}
+
+// GetAgileContext fetches sprint membership, board and story points for a ticket via the Agile REST API
+func (c *Client) GetAgileContext(ticketID string) (*ClientJiraAgileContext, error) {
+	if c.JiraClient == nil {
+		return nil, fmt.Errorf("jira client not initialized")
+	}
+
+	pointsField := c.storyPointsFieldID()
+	fields := []string{"sprint", "closedSprints"}
+	if pointsField != "" {
+		fields = append(fields, pointsField)
+	}
+
+	var issue struct {
+		Fields map[string]interface{} `json:"fields"`
+	}
+	endpoint := fmt.Sprintf("rest/agile/1.0/issue/%s?fields=%s", url.PathEscape(ticketID), url.QueryEscape(strings.Join(fields, ",")))
+	if err := c.getJSON(endpoint, &issue); err != nil {
+		return nil, fmt.Errorf("failed to get agile issue: %w", err)
+	}
+
+	agile := &ClientJiraAgileContext{}
+	if points, ok := issue.Fields[pointsField].(float64); ok && pointsField != "" {
+		agile.StoryPoints = &points
+	}
+	if closed, ok := issue.Fields["closedSprints"].([]interface{}); ok {
+		agile.ClosedSprints = len(closed)
+	}
+
+	sprintData, ok := issue.Fields["sprint"].(map[string]interface{})
+	if !ok {
+		return agile, nil
+	}
+	agile.Sprint = sprintFromMap(sprintData)
+	agile.BoardID = agile.Sprint.BoardID
+
+	if agile.BoardID != 0 {
+		var board struct {
+			Name string `json:"name"`
+		}
+		if err := c.getJSON(fmt.Sprintf("rest/agile/1.0/board/%d", agile.BoardID), &board); err == nil {
+			agile.BoardName = board.Name
+		}
+	}
+
+	// Work out when the issue joined the sprint from the changelog
+	if addedAt, err := c.sprintAddedAt(ticketID, agile.Sprint.ID); err == nil && !addedAt.IsZero() {
+		agile.AddedToSprintAt = addedAt.Format(time.RFC3339)
+		if started, err := parseJiraTime(agile.Sprint.StartDate); err == nil && agile.Sprint.State == "active" {
+			agile.AddedMidSprint = addedAt.After(started)
+		}
+	}
+
+	return agile, nil
+}
+
+// sprintAddedAt returns the most recent time the issue was moved into the given sprint
+func (c *Client) sprintAddedAt(ticketID string, sprintID int) (time.Time, error) {
+	var issue struct {
+		Changelog struct {
+			Histories []struct {
+				Created string `json:"created"`
+				Items   []struct {
+					Field string `json:"field"`
+					From  string `json:"from"`
+					To    string `json:"to"`
+				} `json:"items"`
+			} `json:"histories"`
+		} `json:"changelog"`
+	}
+	endpoint := fmt.Sprintf("rest/api/2/issue/%s?fields=summary&expand=changelog", url.PathEscape(ticketID))
+	if err := c.getJSON(endpoint, &issue); err != nil {
+		return time.Time{}, err
+	}
+
+	id := strconv.Itoa(sprintID)
+	var latest time.Time
+	for _, history := range issue.Changelog.Histories {
+		for _, item := range history.Items {
+			if item.Field != "Sprint" || !containsID(item.To, id) || containsID(item.From, id) {
+				continue
+			}
+			if created, err := parseJiraTime(history.Created); err == nil && created.After(latest) {
+				latest = created
+			}
+		}
+	}
+	return latest, nil
+}
+
+// storyPointsFieldID returns the configured story points field, discovering it by name when unset.
+// A failed discovery is not cached, so the next call tries again.
+func (c *Client) storyPointsFieldID() string {
+	c.storyPointsMu.Lock()
+	defer c.storyPointsMu.Unlock()
+	if c.storyPointsResolved {
+		return c.storyPointsField
+	}
+	if c.Config.JiraStoryPointsField != "" {
+		c.storyPointsField = c.Config.JiraStoryPointsField
+		c.storyPointsResolved = true
+		return c.storyPointsField
+	}
+	fields, err := c.GetFields()
+	if err != nil {
+		log.Warnf("Failed to discover the story points field: %v", err)
+		return ""
+	}
+	c.storyPointsResolved = true
+	for _, name := range storyPointsFieldNames {
+		for _, field := range fields {
+			if strings.EqualFold(field.Name, name) {
+				c.storyPointsField = field.ID
+				return c.storyPointsField
+			}
+		}
+	}
+	return ""
+}
+
+// ClientJiraField represents a Jira field definition
+type ClientJiraField struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Custom bool   `json:"custom"`
+}
+
+// GetFields lists the system and custom fields defined on the Jira site
+func (c *Client) GetFields() ([]ClientJiraField, error) {
+	if c.JiraClient == nil {
+		return nil, fmt.Errorf("jira client not initialized")
+	}
+
+	fields, response, err := c.JiraClient.Issue.Field.Gets(c.Ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fields: %w", err)
+	}
+
+	if response.StatusCode != 200 {
+		return nil, fmt.Errorf("failed to get fields, status: %d", response.StatusCode)
+	}
+
+	result := make([]ClientJiraField, 0, len(fields))
+	for _, field := range fields {
+		if field != nil {
+			result = append(result, ClientJiraField{ID: field.ID, Name: field.Name, Custom: field.Custom})
+		}
+	}
+	return result, nil
+}
+
+// sprintFromMap converts an Agile API sprint object
+func sprintFromMap(m map[string]interface{}) *ClientJiraSprint {
+	sprint := &ClientJiraSprint{}
+	if id, ok := m["id"].(float64); ok {
+		sprint.ID = int(id)
+	}
+	if board, ok := m["originBoardId"].(float64); ok {
+		sprint.BoardID = int(board)
+	}
+	sprint.Name, _ = m["name"].(string)
+	sprint.State, _ = m["state"].(string)
+	sprint.Goal, _ = m["goal"].(string)
+	sprint.StartDate, _ = m["startDate"].(string)
+	sprint.EndDate, _ = m["endDate"].(string)
+	return sprint
+}
+
+// containsID reports whether a comma separated changelog value contains the given ID
+func containsID(list, id string) bool {
+	for _, part := range strings.Split(list, ",") {
+		if strings.TrimSpace(part) == id {
+			return true
+		}
+	}
+	return false
+}
+
+// parseJiraTime parses the timestamp formats returned by the Jira REST and Agile APIs
+func parseJiraTime(s string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.000-0700", "2006-01-02T15:04:05-0700"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized time format: %q", s)
+}
@@ -0,0 +1,104 @@
+package jira
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	log "github.com/tuannvm/jira-a2a/internal/logging"
+)
+
+// textAttachmentExtensions are file types read as text regardless of their MIME type,
+// which Jira often reports as application/octet-stream for logs.
+var textAttachmentExtensions = map[string]bool{
+	".log": true, ".txt": true, ".out": true, ".err": true, ".trace": true, ".json": true, ".xml": true, ".csv": true,
+}
+
+// ClientJiraAttachment represents a text attachment of a Jira ticket with its content
+type ClientJiraAttachment struct {
+	ID       string `json:"id"`
+	Filename string `json:"filename"`
+	MimeType string `json:"mimeType,omitempty"`
+	Size     int    `json:"size"`
+	Created  string `json:"created,omitempty"`
+	Author   string `json:"author,omitempty"`
+	Content  string `json:"content"`
+}
+
+// GetTextAttachments fetches the text attachments of a ticket, such as logs, with their content.
+// Binary attachments, attachments larger than maxBytes and attachments that fail to download
+// are skipped. Newer attachments are read first, until their total size reaches maxTotalBytes
+// (0 for no limit).
+func (c *Client) GetTextAttachments(ticketID string, maxBytes, maxTotalBytes int) ([]ClientJiraAttachment, error) {
+	if c.JiraClient == nil {
+		return nil, fmt.Errorf("jira client not initialized")
+	}
+
+	// The v2 issue model has no attachment field, so read it from the raw issue
+	var issue struct {
+		Fields struct {
+			Attachment []struct {
+				ID       string `json:"id"`
+				Filename string `json:"filename"`
+				MimeType string `json:"mimeType"`
+				Size     int    `json:"size"`
+				Created  string `json:"created"`
+				Author   *struct {
+					DisplayName string `json:"displayName"`
+				} `json:"author"`
+			} `json:"attachment"`
+		} `json:"fields"`
+	}
+	if err := c.getJSON(fmt.Sprintf("rest/api/2/issue/%s?fields=attachment", url.PathEscape(ticketID)), &issue); err != nil {
+		return nil, fmt.Errorf("failed to get attachments: %w", err)
+	}
+
+	attachments := issue.Fields.Attachment
+	sort.SliceStable(attachments, func(i, j int) bool { return attachments[i].Created > attachments[j].Created })
+
+	var result []ClientJiraAttachment
+	total := 0
+	for _, a := range attachments {
+		if !isTextAttachment(a.Filename, a.MimeType) || a.Size > maxBytes {
+			continue
+		}
+		if maxTotalBytes > 0 && total+a.Size > maxTotalBytes {
+			log.Debugf("Skipping attachment %s of ticket %s: the attachments read exceed %d bytes", a.Filename, ticketID, maxTotalBytes)
+			continue
+		}
+		response, err := c.JiraClient.Issue.Attachment.Download(c.Ctx, a.ID, true)
+		if err != nil {
+			log.Warnf("Skipping attachment %s of ticket %s: failed to download it: %v", a.Filename, ticketID, err)
+			continue
+		}
+		content := response.Bytes.Bytes()
+		if !utf8.Valid(content) {
+			continue // Not text after all
+		}
+		total += len(content)
+		attachment := ClientJiraAttachment{
+			ID:       a.ID,
+			Filename: a.Filename,
+			MimeType: a.MimeType,
+			Size:     a.Size,
+			Created:  a.Created,
+			Content:  string(content),
+		}
+		if a.Author != nil {
+			attachment.Author = a.Author.DisplayName
+		}
+		result = append(result, attachment)
+	}
+	return result, nil
+}
+
+// isTextAttachment reports whether an attachment is likely to hold readable text.
+func isTextAttachment(filename, mimeType string) bool {
+	if strings.HasPrefix(mimeType, "text/") || mimeType == "application/json" || mimeType == "application/xml" {
+		return true
+	}
+	return textAttachmentExtensions[strings.ToLower(path.Ext(filename))]
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	log "github.com/tuannvm/jira-a2a/internal/logging"
+	"sync"
+	"time"
 
 	v2 "github.com/ctreminiom/go-atlassian/v2/jira/v2"
 	"github.com/ctreminiom/go-atlassian/v2/pkg/infra/models"
@@ -15,6 +17,11 @@ type Client struct {
 	Config     *config.Config
 	JiraClient *v2.Client
 	Ctx        context.Context
+
+	// Story points field ID, resolved lazily by storyPointsFieldID
+	storyPointsMu       sync.Mutex
+	storyPointsField    string
+	storyPointsResolved bool
 }
 
 // ClientJiraTicket represents a Jira ticket in the client
@@ -26,6 +33,7 @@ type ClientJiraTicket struct {
 	Fields      map[string]interface{} `json:"fields"`
 	Links       []ClientJiraLink       `json:"links,omitempty"`
 	DueDate     string                 `json:"dueDate,omitempty"`
+	Comments    []ClientJiraComment    `json:"comments,omitempty"`
 }
 
 // ClientJiraLink represents a link between Jira tickets
@@ -37,11 +45,18 @@ type ClientJiraLink struct {
 
 // ClientJiraComment represents a comment on a Jira ticket
 type ClientJiraComment struct {
-	ID      string `json:"id,omitempty"`
-	Body    string `json:"body"`
-	Created string `json:"created,omitempty"`
-	Author  string `json:"author,omitempty"`
-	URL     string `json:"url,omitempty"`
+	ID              string `json:"id,omitempty"`
+	Body            string `json:"body"`
+	Created         string `json:"created,omitempty"`
+	Author          string `json:"author,omitempty"`
+	AuthorAccountID string `json:"authorAccountId,omitempty"`
+	URL             string `json:"url,omitempty"`
+}
+
+// ClientJiraSearchPage represents one page of JQL search results
+type ClientJiraSearchPage struct {
+	Keys          []string `json:"keys"`
+	NextPageToken string   `json:"nextPageToken,omitempty"`
 }
 
 // NewClient creates a new Jira client
@@ -93,7 +108,7 @@ func (c *Client) GetTicket(ticketID string) (*ClientJiraTicket, error) {
 
 	// Define fields to retrieve and expand options
 	fields := []string{"summary", "description", "duedate", "issuelinks", "status", "priority", "resolution",
-		"assignee", "reporter", "issuetype", "project", "created", "updated", "components", "labels"}
+		"assignee", "reporter", "issuetype", "project", "created", "updated", "components", "labels", "parent", "subtasks", "comment"}
 	expand := []string{} // No expansion needed for now
 
 	// Fetch the issue with relevant fields
@@ -118,7 +133,7 @@ func (c *Client) GetTicket(ticketID string) (*ClientJiraTicket, error) {
 
 	// Handle due date (safely converted to string)
 	if issue.Fields.DueDate != nil {
-		ticket.DueDate = fmt.Sprintf("%v", issue.Fields.DueDate)
+		ticket.DueDate = time.Time(*issue.Fields.DueDate).Format("2006-01-02")
 	}
 
 	// Extract basic fields into the Fields map
@@ -136,10 +151,12 @@ func (c *Client) GetTicket(ticketID string) (*ClientJiraTicket, error) {
 
 	if issue.Fields.Assignee != nil {
 		ticket.Fields["assignee"] = issue.Fields.Assignee.DisplayName
+		ticket.Fields["assigneeAccountId"] = issue.Fields.Assignee.AccountID
 	}
 
 	if issue.Fields.Reporter != nil {
 		ticket.Fields["reporter"] = issue.Fields.Reporter.DisplayName
+		ticket.Fields["reporterAccountId"] = issue.Fields.Reporter.AccountID
 	}
 
 	if issue.Fields.IssueType != nil {
@@ -150,6 +167,10 @@ func (c *Client) GetTicket(ticketID string) (*ClientJiraTicket, error) {
 		ticket.Fields["project"] = issue.Fields.Project.Name
 	}
 
+	if issue.Fields.Parent != nil {
+		ticket.Fields["parent"] = issue.Fields.Parent.Key
+	}
+
 	// Handle datetime fields
 	if issue.Fields.Created != nil {
 		ticket.Fields["created"] = fmt.Sprintf("%v", issue.Fields.Created)
@@ -176,6 +197,16 @@ func (c *Client) GetTicket(ticketID string) (*ClientJiraTicket, error) {
 		ticket.Fields["labels"] = issue.Fields.Labels
 	}
 
+	if len(issue.Fields.Subtasks) > 0 {
+		subtasks := []string{}
+		for _, subtask := range issue.Fields.Subtasks {
+			if subtask != nil {
+				subtasks = append(subtasks, subtask.Key)
+			}
+		}
+		ticket.Fields["subtasks"] = subtasks
+	}
+
 	// Extract issue links if available
 	if len(issue.Fields.IssueLinks) > 0 {
 		for _, link := range issue.Fields.IssueLinks {
@@ -195,19 +226,47 @@ func (c *Client) GetTicket(ticketID string) (*ClientJiraTicket, error) {
 		}
 	}
 
+	// Extract comments if available
+	if issue.Fields.Comment != nil {
+		for _, comment := range issue.Fields.Comment.Comments {
+			if comment == nil {
+				continue
+			}
+			jiraComment := ClientJiraComment{
+				ID:      comment.ID,
+				Body:    comment.Body,
+				Created: comment.Created,
+			}
+			if comment.Author != nil {
+				jiraComment.Author = comment.Author.DisplayName
+				jiraComment.AuthorAccountID = comment.Author.AccountID
+			}
+			ticket.Comments = append(ticket.Comments, jiraComment)
+		}
+	}
+
 	return ticket, nil
 }
 
-// PostComment posts a comment to a Jira ticket
-func (c *Client) PostComment(ticketID, commentText string) (*ClientJiraComment, error) {
+// PostComment posts a comment to a Jira ticket.
+// A nil visibility posts a comment everyone with access to the ticket can see.
+func (c *Client) PostComment(ticketID, commentText string, visibility *ClientJiraCommentVisibility) (*ClientJiraComment, error) {
 	if c.JiraClient == nil {
 		return nil, fmt.Errorf("jira client not initialized")
 	}
 
+	// Internal notes need comment properties, which the typed payload does not support
+	if visibility != nil && visibility.Internal {
+		return c.postRawComment(ticketID, commentText, visibility)
+	}
+
 	// Create the comment payload for v2
 	commentPayload := &models.CommentPayloadSchemeV2{
 		Body: commentText,
 	}
+	if visibility != nil && visibility.Type != "" {
+		commentPayload.Visibility = &models.CommentVisibilityScheme{Type: visibility.Type, Value: visibility.Value}
+	}
 
 	// Post the comment to the issue using the v2 method
 	responseComment, response, err := c.JiraClient.Issue.Comment.Add(c.Ctx, ticketID, commentPayload, nil)
@@ -229,6 +288,7 @@ func (c *Client) PostComment(ticketID, commentText string) (*ClientJiraComment,
 	// Extract author if available
 	if responseComment.Author != nil {
 		jiraComment.Author = responseComment.Author.DisplayName
+		jiraComment.AuthorAccountID = responseComment.Author.AccountID
 	}
 
 	// Add the URL
@@ -252,3 +312,34 @@ func (c *Client) GetLinkedTickets(ticketID string) ([]ClientJiraLink, error) {
 
 	return ticket.Links, nil
 }
+
+// SearchTickets runs a JQL query and returns the ticket keys of a single result page.
+// Pass the NextPageToken of the previous page to continue paging; an empty token
+// in the returned page means there are no more results.
+func (c *Client) SearchTickets(jql, nextPageToken string, maxResults int) (*ClientJiraSearchPage, error) {
+	if c.JiraClient == nil {
+		return nil, fmt.Errorf("jira client not initialized")
+	}
+
+	// Only the key is needed, the full ticket is fetched separately with GetTicket
+	result, response, err := c.JiraClient.Issue.Search.SearchJQL(c.Ctx, jql, []string{"key"}, nil, maxResults, nextPageToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search issues: %w", err)
+	}
+
+	if response.StatusCode != 200 {
+		return nil, fmt.Errorf("failed to search issues, status: %d", response.StatusCode)
+	}
+
+	page := &ClientJiraSearchPage{
+		Keys:          make([]string, 0, len(result.Issues)),
+		NextPageToken: result.NextPageToken,
+	}
+	for _, issue := range result.Issues {
+		if issue != nil && issue.Key != "" {
+			page.Keys = append(page.Keys, issue.Key)
+		}
+	}
+
+	return page, nil
+}
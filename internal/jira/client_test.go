@@ -0,0 +1,257 @@
+package jira
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tuannvm/jira-a2a/internal/config"
+	"github.com/tuannvm/jira-a2a/internal/jira/jiratest"
+)
+
+// newTestClient returns a client connected to a fake Jira seeded with the default fixtures
+func newTestClient(t *testing.T) (*Client, *jiratest.Server) {
+	t.Helper()
+	server := jiratest.NewServer(nil)
+	t.Cleanup(server.Close)
+	client := NewClient(&config.Config{JiraBaseURL: server.Start(), JiraUsername: "agent@example.com", JiraAPIToken: "token"})
+	return client, server
+}
+
+func TestGetTicket(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	ticket, err := client.GetTicket("PROJ-2")
+	if err != nil {
+		t.Fatalf("GetTicket: %v", err)
+	}
+	if ticket.Key != "PROJ-2" || ticket.Summary != "Login fails with SSO for some users" {
+		t.Errorf("got ticket %s %q", ticket.Key, ticket.Summary)
+	}
+	for field, want := range map[string]interface{}{"status": "To Do", "priority": "High", "issueType": "Bug"} {
+		if got := ticket.Fields[field]; got != want {
+			t.Errorf("field %s = %v, want %v", field, got, want)
+		}
+	}
+
+	if _, err := client.GetTicket("PROJ-404"); err == nil {
+		t.Error("GetTicket of a missing issue succeeded")
+	}
+}
+
+func TestSearchTickets(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	tests := []struct {
+		jql  string
+		want []string
+	}{
+		{"project = PROJ ORDER BY key", []string{"PROJ-1", "PROJ-2", "PROJ-3", "PROJ-4"}},
+		{"project = PROJ AND statusCategory != Done", []string{"PROJ-1", "PROJ-2", "PROJ-3"}},
+		{"issuetype in (Bug, Story)", []string{"PROJ-2", "PROJ-3"}},
+		{`parent = "PROJ-3" OR key = HELP-1`, []string{"HELP-1", "PROJ-4"}},
+	}
+	for _, tt := range tests {
+		page, err := client.SearchTickets(tt.jql, "", 50)
+		if err != nil {
+			t.Errorf("SearchTickets(%q): %v", tt.jql, err)
+			continue
+		}
+		if !reflect.DeepEqual(page.Keys, tt.want) {
+			t.Errorf("SearchTickets(%q) = %v, want %v", tt.jql, page.Keys, tt.want)
+		}
+	}
+
+	if _, err := client.SearchTickets("sprint in openSprints()", "", 50); err == nil {
+		t.Error("SearchTickets with an unsupported field succeeded")
+	}
+}
+
+func TestSearchTicketsPaging(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	var keys []string
+	token := ""
+	for pages := 0; pages < 10; pages++ {
+		page, err := client.SearchTickets("project = PROJ", token, 3)
+		if err != nil {
+			t.Fatalf("SearchTickets: %v", err)
+		}
+		keys = append(keys, page.Keys...)
+		if page.NextPageToken == "" {
+			break
+		}
+		token = page.NextPageToken
+	}
+	if want := []string{"PROJ-1", "PROJ-2", "PROJ-3", "PROJ-4"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("paged keys = %v, want %v", keys, want)
+	}
+}
+
+func TestPostComment(t *testing.T) {
+	client, server := newTestClient(t)
+
+	comment, err := client.PostComment("PROJ-2", "Analysis done", &ClientJiraCommentVisibility{Type: "role", Value: "Developers"})
+	if err != nil {
+		t.Fatalf("PostComment: %v", err)
+	}
+	if comment.ID == "" || comment.Body != "Analysis done" {
+		t.Errorf("got comment %+v", comment)
+	}
+
+	writes := server.Writes()
+	if len(writes) != 1 {
+		t.Fatalf("got %d writes, want 1", len(writes))
+	}
+	if w := writes[0]; w.Method != "POST" || w.IssueKey != "PROJ-2" || w.Path != "/rest/api/2/issue/PROJ-2/comment" {
+		t.Errorf("got write %s %s for %s", w.Method, w.Path, w.IssueKey)
+	}
+	var body struct {
+		Body       string            `json:"body"`
+		Visibility map[string]string `json:"visibility"`
+	}
+	if err := json.Unmarshal(writes[0].Body, &body); err != nil {
+		t.Fatalf("decoding comment write: %v", err)
+	}
+	if body.Body != "Analysis done" || body.Visibility["type"] != "role" || body.Visibility["value"] != "Developers" {
+		t.Errorf("got comment payload %+v", body)
+	}
+
+	comments := server.Comments("PROJ-2")
+	if last := comments[len(comments)-1]; last["body"] != "Analysis done" {
+		t.Errorf("last comment on PROJ-2 = %v", last["body"])
+	}
+}
+
+func TestSetFields(t *testing.T) {
+	client, server := newTestClient(t)
+
+	if err := client.SetFields("PROJ-3", map[string]interface{}{"customfield_10100": "Given a login, when it fails, then it is logged"}); err != nil {
+		t.Fatalf("SetFields: %v", err)
+	}
+	value, err := client.GetFieldValue("PROJ-3", "customfield_10100")
+	if err != nil {
+		t.Fatalf("GetFieldValue: %v", err)
+	}
+	if value != "Given a login, when it fails, then it is logged" {
+		t.Errorf("field value = %v", value)
+	}
+
+	writes := server.Writes()
+	if len(writes) != 1 || writes[0].Method != "PUT" || writes[0].IssueKey != "PROJ-3" {
+		t.Fatalf("got writes %+v", writes)
+	}
+	var body struct {
+		Fields map[string]interface{} `json:"fields"`
+	}
+	if err := json.Unmarshal(writes[0].Body, &body); err != nil {
+		t.Fatalf("decoding edit write: %v", err)
+	}
+	if len(body.Fields) != 1 || body.Fields["customfield_10100"] == nil {
+		t.Errorf("got edit payload %+v", body.Fields)
+	}
+}
+
+func TestGetTextAttachments(t *testing.T) {
+	fixtures := jiratest.DefaultFixtures()
+	fixtures.AttachmentContent["10101"] = "ERROR newer log"
+	fixtures.AttachmentContent["10102"] = "ERROR oldest log"
+	server := jiratest.NewServer(fixtures)
+	t.Cleanup(server.Close)
+	client := NewClient(&config.Config{JiraBaseURL: server.Start()})
+
+	issue, _ := server.Issue("PROJ-2")
+	fields := issue["fields"].(map[string]interface{})
+	fields["attachment"] = append(fields["attachment"].([]interface{}),
+		map[string]interface{}{"id": "10101", "filename": "newer.log", "mimeType": "text/plain", "size": 15, "created": "2025-01-16T11:00:00.000+0000"},
+		map[string]interface{}{"id": "10102", "filename": "oldest.log", "mimeType": "text/plain", "size": 16, "created": "2025-01-14T11:00:00.000+0000"},
+		map[string]interface{}{"id": "10103", "filename": "missing.log", "mimeType": "text/plain", "size": 10, "created": "2025-01-17T11:00:00.000+0000"},
+	)
+	server.PutIssue(issue)
+
+	filenames := func(attachments []ClientJiraAttachment) []string {
+		names := []string{}
+		for _, a := range attachments {
+			names = append(names, a.Filename)
+		}
+		return names
+	}
+
+	// The attachment that fails to download is skipped; the rest are read newest first
+	attachments, err := client.GetTextAttachments("PROJ-2", 1<<20, 0)
+	if err != nil {
+		t.Fatalf("GetTextAttachments: %v", err)
+	}
+	if want := []string{"newer.log", "sso-callback.log", "oldest.log"}; !reflect.DeepEqual(filenames(attachments), want) {
+		t.Errorf("got attachments %v, want %v", filenames(attachments), want)
+	}
+	if attachments[0].Content != "ERROR newer log" {
+		t.Errorf("newer.log content = %q", attachments[0].Content)
+	}
+
+	// The total limit leaves out the oldest attachment, which no longer fits
+	attachments, err = client.GetTextAttachments("PROJ-2", 1<<20, 890)
+	if err != nil {
+		t.Fatalf("GetTextAttachments: %v", err)
+	}
+	if want := []string{"newer.log", "sso-callback.log"}; !reflect.DeepEqual(filenames(attachments), want) {
+		t.Errorf("with a total limit got attachments %v, want %v", filenames(attachments), want)
+	}
+}
+
+func TestSetIssueProperty(t *testing.T) {
+	client, server := newTestClient(t)
+
+	if err := client.SetIssueProperty("PROJ-2", "jira-a2a.analysis", map[string]string{"urgency": "High"}); err != nil {
+		t.Fatalf("SetIssueProperty: %v", err)
+	}
+	var got map[string]string
+	if found, err := client.GetIssueProperty("PROJ-2", "jira-a2a.analysis", &got); err != nil || !found {
+		t.Fatalf("GetIssueProperty = %v, %v", found, err)
+	}
+	if got["urgency"] != "High" {
+		t.Errorf("property = %v", got)
+	}
+
+	large := map[string]string{"summary": strings.Repeat("x", MaxIssuePropertyBytes)}
+	if err := client.SetIssueProperty("PROJ-2", "jira-a2a.large", large); !errors.Is(err, ErrPropertyTooLarge) {
+		t.Errorf("SetIssueProperty of a large value = %v, want ErrPropertyTooLarge", err)
+	}
+	if _, ok := server.Property("PROJ-2", "jira-a2a.large"); ok {
+		t.Error("a property over the size limit was sent to Jira")
+	}
+}
+
+func TestGetHierarchyEpicLink(t *testing.T) {
+	client, server := newTestClient(t)
+	client.Config.JiraEpicLinkField = "customfield_10014"
+	// A company-managed issue linked to the epic through the epic link field only
+	server.PutIssue(map[string]interface{}{
+		"id":  "10010",
+		"key": "PROJ-10",
+		"fields": map[string]interface{}{
+			"summary":           "Document the SSO setup",
+			"issuetype":         map[string]interface{}{"name": "Task"},
+			"project":           map[string]interface{}{"key": "PROJ"},
+			"status":            map[string]interface{}{"name": "To Do"},
+			"customfield_10014": "PROJ-1",
+		},
+	})
+
+	hierarchy, err := client.GetHierarchy("PROJ-10")
+	if err != nil {
+		t.Fatalf("GetHierarchy: %v", err)
+	}
+	if len(hierarchy.Parents) != 1 || hierarchy.Parents[0].Key != "PROJ-1" {
+		t.Errorf("parents = %+v, want the epic", hierarchy.Parents)
+	}
+	var siblings []string
+	for _, s := range hierarchy.Siblings {
+		siblings = append(siblings, s.Key)
+	}
+	if want := []string{"PROJ-2", "PROJ-3"}; !reflect.DeepEqual(siblings, want) {
+		t.Errorf("siblings = %v, want %v", siblings, want)
+	}
+}
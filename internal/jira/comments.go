@@ -0,0 +1,90 @@
+package jira
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ClientJiraCommentVisibility restricts who can see a comment
+type ClientJiraCommentVisibility struct {
+	Type     string `json:"type,omitempty"`     // "role" or "group"; empty for no restriction
+	Value    string `json:"value,omitempty"`    // Role or group name
+	Internal bool   `json:"internal,omitempty"` // Jira Service Management internal note, hidden from customers
+}
+
+// ClientJiraIssueMeta identifies the project and issue type of a ticket
+type ClientJiraIssueMeta struct {
+	ProjectKey  string `json:"projectKey"`
+	ProjectType string `json:"projectType"` // e.g. "software", "business" or "service_desk"
+	IssueType   string `json:"issueType"`
+}
+
+// GetIssueMeta fetches the project and issue type of a ticket
+func (c *Client) GetIssueMeta(ticketID string) (*ClientJiraIssueMeta, error) {
+	if c.JiraClient == nil {
+		return nil, fmt.Errorf("jira client not initialized")
+	}
+
+	var issue struct {
+		Fields struct {
+			Project struct {
+				Key            string `json:"key"`
+				ProjectTypeKey string `json:"projectTypeKey"`
+			} `json:"project"`
+			IssueType struct {
+				Name string `json:"name"`
+			} `json:"issuetype"`
+		} `json:"fields"`
+	}
+	endpoint := fmt.Sprintf("rest/api/2/issue/%s?fields=project,issuetype", url.PathEscape(ticketID))
+	if err := c.getJSON(endpoint, &issue); err != nil {
+		return nil, fmt.Errorf("failed to get issue: %w", err)
+	}
+	return &ClientJiraIssueMeta{
+		ProjectKey:  issue.Fields.Project.Key,
+		ProjectType: issue.Fields.Project.ProjectTypeKey,
+		IssueType:   issue.Fields.IssueType.Name,
+	}, nil
+}
+
+// commentPayload builds the REST representation of a comment with its visibility settings
+func commentPayload(body string, visibility *ClientJiraCommentVisibility) map[string]interface{} {
+	payload := map[string]interface{}{"body": body}
+	if visibility == nil {
+		return payload
+	}
+	if visibility.Type != "" {
+		payload["visibility"] = map[string]interface{}{"type": visibility.Type, "value": visibility.Value}
+	}
+	if visibility.Internal {
+		payload["properties"] = []interface{}{map[string]interface{}{
+			"key":   "sd.public.comment",
+			"value": map[string]interface{}{"internal": true},
+		}}
+	}
+	return payload
+}
+
+// postRawComment posts a comment through the REST API directly, for payloads the typed client cannot express
+func (c *Client) postRawComment(ticketID, commentText string, visibility *ClientJiraCommentVisibility) (*ClientJiraComment, error) {
+	var created struct {
+		ID      string `json:"id"`
+		Body    string `json:"body"`
+		Created string `json:"created"`
+		Author  struct {
+			DisplayName string `json:"displayName"`
+		} `json:"author"`
+	}
+	endpoint := fmt.Sprintf("rest/api/2/issue/%s/comment", url.PathEscape(ticketID))
+	if err := c.doJSON(http.MethodPost, endpoint, commentPayload(commentText, visibility), &created); err != nil {
+		return nil, fmt.Errorf("failed to post comment: %w", err)
+	}
+	return &ClientJiraComment{
+		ID:      created.ID,
+		Body:    created.Body,
+		Created: created.Created,
+		Author:  created.Author.DisplayName,
+		URL:     fmt.Sprintf("%s/browse/%s?focusedCommentId=%s", c.Config.JiraBaseURL, ticketID, created.ID),
+	}, nil
+}
@@ -0,0 +1,140 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ClientJiraWebhook represents a webhook registered through Jira's dynamic webhook API
+type ClientJiraWebhook struct {
+	ID             int       `json:"id"`
+	URL            string    `json:"url,omitempty"` // Empty when Jira does not report it
+	JQLFilter      string    `json:"jqlFilter"`
+	Events         []string  `json:"events"`
+	ExpirationDate time.Time `json:"expirationDate"`
+}
+
+// ClientJiraWebhookSpec describes a webhook to register
+type ClientJiraWebhookSpec struct {
+	JQLFilter string   `json:"jqlFilter"`
+	Events    []string `json:"events"`
+}
+
+// ListWebhooks lists the dynamic webhooks registered by the current app or user.
+// Jira only allows Connect and OAuth 2.0 apps to use the dynamic webhook API; with basic
+// auth and an API token, the calls fail with ErrForbidden.
+func (c *Client) ListWebhooks() ([]ClientJiraWebhook, error) {
+	if c.JiraClient == nil {
+		return nil, fmt.Errorf("jira client not initialized")
+	}
+
+	var webhooks []ClientJiraWebhook
+	startAt := 0
+	for {
+		var page struct {
+			IsLast bool `json:"isLast"`
+			Values []struct {
+				ID             int             `json:"id"`
+				URL            string          `json:"url"`
+				JQLFilter      string          `json:"jqlFilter"`
+				Events         []string        `json:"events"`
+				ExpirationDate json.RawMessage `json:"expirationDate"`
+			} `json:"values"`
+		}
+		endpoint := fmt.Sprintf("rest/api/2/webhook?startAt=%d&maxResults=100", startAt)
+		if err := c.getJSON(endpoint, &page); err != nil {
+			return nil, fmt.Errorf("failed to list webhooks: %w", err)
+		}
+		for _, v := range page.Values {
+			webhooks = append(webhooks, ClientJiraWebhook{
+				ID:             v.ID,
+				URL:            v.URL,
+				JQLFilter:      v.JQLFilter,
+				Events:         v.Events,
+				ExpirationDate: parseExpiration(v.ExpirationDate),
+			})
+		}
+		if page.IsLast || len(page.Values) == 0 {
+			return webhooks, nil
+		}
+		startAt += len(page.Values)
+	}
+}
+
+// RegisterWebhooks registers webhooks delivering to the given URL and returns their IDs.
+// Jira reports failures per webhook; they are returned together as a single error after
+// the successfully created IDs.
+func (c *Client) RegisterWebhooks(url string, specs []ClientJiraWebhookSpec) ([]int, error) {
+	if c.JiraClient == nil {
+		return nil, fmt.Errorf("jira client not initialized")
+	}
+
+	payload := map[string]interface{}{"url": url, "webhooks": specs}
+	var result struct {
+		WebhookRegistrationResult []struct {
+			CreatedWebhookID int      `json:"createdWebhookId"`
+			Errors           []string `json:"errors"`
+		} `json:"webhookRegistrationResult"`
+	}
+	if err := c.doJSON(http.MethodPost, "rest/api/2/webhook", payload, &result); err != nil {
+		return nil, fmt.Errorf("failed to register webhooks: %w", err)
+	}
+
+	var ids []int
+	var failures []string
+	for i, r := range result.WebhookRegistrationResult {
+		if len(r.Errors) > 0 || r.CreatedWebhookID == 0 {
+			failures = append(failures, fmt.Sprintf("webhook %d: %s", i, strings.Join(r.Errors, "; ")))
+			continue
+		}
+		ids = append(ids, r.CreatedWebhookID)
+	}
+	if len(failures) > 0 {
+		return ids, fmt.Errorf("failed to register webhooks: %s", strings.Join(failures, ", "))
+	}
+	return ids, nil
+}
+
+// RefreshWebhooks extends the life of the given webhooks and returns their new expiration date
+func (c *Client) RefreshWebhooks(ids []int) (time.Time, error) {
+	if c.JiraClient == nil {
+		return time.Time{}, fmt.Errorf("jira client not initialized")
+	}
+
+	var result struct {
+		ExpirationDate json.RawMessage `json:"expirationDate"`
+	}
+	payload := map[string]interface{}{"webhookIds": ids}
+	if err := c.doJSON(http.MethodPut, "rest/api/2/webhook/refresh", payload, &result); err != nil {
+		return time.Time{}, fmt.Errorf("failed to refresh webhooks: %w", err)
+	}
+	return parseExpiration(result.ExpirationDate), nil
+}
+
+// DeleteWebhooks removes the given webhooks
+func (c *Client) DeleteWebhooks(ids []int) error {
+	if c.JiraClient == nil {
+		return fmt.Errorf("jira client not initialized")
+	}
+
+	payload := map[string]interface{}{"webhookIds": ids}
+	if err := c.doJSON(http.MethodDelete, "rest/api/2/webhook", payload, nil); err != nil {
+		return fmt.Errorf("failed to delete webhooks: %w", err)
+	}
+	return nil
+}
+
+// parseExpiration reads a webhook expiration date, which Jira returns either as
+// epoch milliseconds or as a timestamp string depending on the deployment
+func parseExpiration(raw json.RawMessage) time.Time {
+	value := strings.Trim(string(raw), `"`)
+	if ms, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.UnixMilli(ms)
+	}
+	t, _ := parseJiraTime(value)
+	return t
+}
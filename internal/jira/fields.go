@@ -0,0 +1,37 @@
+package jira
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// GetFieldValue fetches the value of one field of a ticket, such as a custom field.
+// It returns nil when the field is not set.
+func (c *Client) GetFieldValue(ticketID, fieldID string) (interface{}, error) {
+	if c.JiraClient == nil {
+		return nil, fmt.Errorf("jira client not initialized")
+	}
+
+	var issue struct {
+		Fields map[string]interface{} `json:"fields"`
+	}
+	endpoint := fmt.Sprintf("rest/api/2/issue/%s?fields=%s", url.PathEscape(ticketID), url.QueryEscape(fieldID))
+	if err := c.getJSON(endpoint, &issue); err != nil {
+		return nil, fmt.Errorf("failed to get field %s: %w", fieldID, err)
+	}
+	return issue.Fields[fieldID], nil
+}
+
+// SetFields edits fields of a ticket, keyed by field ID, e.g. "customfield_10040".
+func (c *Client) SetFields(ticketID string, fields map[string]interface{}) error {
+	if c.JiraClient == nil {
+		return fmt.Errorf("jira client not initialized")
+	}
+
+	endpoint := fmt.Sprintf("rest/api/2/issue/%s", url.PathEscape(ticketID))
+	if err := c.doJSON(http.MethodPut, endpoint, map[string]interface{}{"fields": fields}, nil); err != nil {
+		return fmt.Errorf("failed to set fields: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package jira
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ErrForbidden is returned when Jira refuses a request the credentials are not allowed to make
+var ErrForbidden = errors.New("forbidden")
+
+// maxHierarchyDepth bounds how far up the parent chain is followed (e.g. subtask -> story -> epic -> initiative)
+const maxHierarchyDepth = 5
+
+// maxHierarchyIssues bounds the number of siblings and children returned
+const maxHierarchyIssues = 50
+
+// ClientJiraIssueRef is a lightweight reference to a related issue
+type ClientJiraIssueRef struct {
+	Key       string `json:"key"`
+	Summary   string `json:"summary,omitempty"`
+	Status    string `json:"status,omitempty"`
+	IssueType string `json:"issueType,omitempty"`
+}
+
+// ClientJiraHierarchy describes where an issue sits in the issue hierarchy
+type ClientJiraHierarchy struct {
+	Parents  []ClientJiraIssueRef `json:"parents,omitempty"`  // Nearest parent first, up to the epic/initiative
+	Siblings []ClientJiraIssueRef `json:"siblings,omitempty"` // Other children of the direct parent
+	Children []ClientJiraIssueRef `json:"children,omitempty"` // Subtasks or issues in the epic
+}
+
+// GetHierarchy fetches the parent chain, siblings and children of a Jira ticket.
+// Parents are resolved through the "parent" field and, for company-managed projects,
+// the configured epic link field.
+func (c *Client) GetHierarchy(ticketID string) (*ClientJiraHierarchy, error) {
+	if c.JiraClient == nil {
+		return nil, fmt.Errorf("jira client not initialized")
+	}
+
+	hierarchy := &ClientJiraHierarchy{}
+	visited := map[string]bool{}
+
+	// Walk up the parent chain
+	current, err := c.getIssueRef(ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hierarchy: %w", err)
+	}
+	visited[current.ref.Key] = true
+	for depth := 0; depth < maxHierarchyDepth && current.parentKey != "" && !visited[current.parentKey]; depth++ {
+		parent, err := c.getIssueRef(current.parentKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get parent %s: %w", current.parentKey, err)
+		}
+		visited[parent.ref.Key] = true
+		hierarchy.Parents = append(hierarchy.Parents, parent.ref)
+		current = parent
+	}
+
+	// Children of the ticket itself (subtasks, or issues in an epic)
+	hierarchy.Children, err = c.searchIssueRefs(c.childrenJQL(ticketID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get children: %w", err)
+	}
+
+	// Siblings share the direct parent
+	if len(hierarchy.Parents) > 0 {
+		parentKey := hierarchy.Parents[0].Key
+		siblings, err := c.searchIssueRefs(c.childrenJQL(parentKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get siblings: %w", err)
+		}
+		for _, sibling := range siblings {
+			if !strings.EqualFold(sibling.Key, ticketID) {
+				hierarchy.Siblings = append(hierarchy.Siblings, sibling)
+			}
+		}
+	}
+
+	return hierarchy, nil
+}
+
+// issueRefWithParent pairs an issue reference with the key of its parent
+type issueRefWithParent struct {
+	ref       ClientJiraIssueRef
+	parentKey string
+}
+
+// getIssueRef fetches the fields needed to place a single issue in the hierarchy
+func (c *Client) getIssueRef(ticketID string) (*issueRefWithParent, error) {
+	fields := []string{"summary", "status", "issuetype", "parent"}
+	if c.Config.JiraEpicLinkField != "" {
+		fields = append(fields, c.Config.JiraEpicLinkField)
+	}
+
+	var issue struct {
+		Key    string                 `json:"key"`
+		Fields map[string]interface{} `json:"fields"`
+	}
+	endpoint := fmt.Sprintf("rest/api/2/issue/%s?fields=%s", url.PathEscape(ticketID), url.QueryEscape(strings.Join(fields, ",")))
+	if err := c.getJSON(endpoint, &issue); err != nil {
+		return nil, err
+	}
+
+	result := &issueRefWithParent{ref: issueRefFromFields(issue.Key, issue.Fields)}
+	if parent, ok := issue.Fields["parent"].(map[string]interface{}); ok {
+		result.parentKey, _ = parent["key"].(string)
+	}
+	if result.parentKey == "" && c.Config.JiraEpicLinkField != "" {
+		result.parentKey, _ = issue.Fields[c.Config.JiraEpicLinkField].(string)
+	}
+	return result, nil
+}
+
+// childrenJQL selects the children of an issue: issues with it as parent and, when an epic
+// link field is configured, issues linked to it as their epic
+func (c *Client) childrenJQL(key string) string {
+	quoted := quoteJQL(key)
+	if c.Config.JiraEpicLinkField != "" {
+		return fmt.Sprintf("parent = %s OR %s = %s ORDER BY key", quoted, jqlField(c.Config.JiraEpicLinkField), quoted)
+	}
+	return fmt.Sprintf("parent = %s ORDER BY key", quoted)
+}
+
+// jqlField returns the JQL reference to a field ID: cf[10014] for customfield_10014,
+// which unlike the field name does not depend on the Jira language or renames
+func jqlField(id string) string {
+	if n, ok := strings.CutPrefix(id, "customfield_"); ok {
+		return "cf[" + n + "]"
+	}
+	return quoteJQL(id)
+}
+
+// quoteJQL quotes a value for use in a JQL query
+func quoteJQL(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+// searchIssueRefs runs a JQL query and returns references to the matching issues
+func (c *Client) searchIssueRefs(jql string) ([]ClientJiraIssueRef, error) {
+	result, response, err := c.JiraClient.Issue.Search.SearchJQL(c.Ctx, jql, []string{"summary", "status", "issuetype"}, nil, maxHierarchyIssues, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to search issues: %w", err)
+	}
+
+	if response.StatusCode != 200 {
+		return nil, fmt.Errorf("failed to search issues, status: %d", response.StatusCode)
+	}
+
+	refs := make([]ClientJiraIssueRef, 0, len(result.Issues))
+	for _, issue := range result.Issues {
+		if issue == nil {
+			continue
+		}
+		ref := ClientJiraIssueRef{Key: issue.Key}
+		if issue.Fields != nil {
+			ref.Summary = issue.Fields.Summary
+			if issue.Fields.Status != nil {
+				ref.Status = issue.Fields.Status.Name
+			}
+			if issue.Fields.IssueType != nil {
+				ref.IssueType = issue.Fields.IssueType.Name
+			}
+		}
+		refs = append(refs, ref)
+	}
+	return refs, nil
+}
+
+// getJSON performs an authenticated GET against the Jira REST API and decodes the response.
+// It is used for endpoints or custom fields that the typed go-atlassian models do not cover.
+func (c *Client) getJSON(endpoint string, out interface{}) error {
+	return c.doJSON(http.MethodGet, endpoint, nil, out)
+}
+
+// doJSON sends an authenticated request with an optional JSON body and decodes the response
+// into out when it is not nil. Any 2xx status is treated as success.
+func (c *Client) doJSON(method, endpoint string, body, out interface{}) error {
+	request, err := c.JiraClient.NewRequest(c.Ctx, method, endpoint, "", body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	response, err := c.JiraClient.Call(request, out)
+	if err != nil {
+		if response != nil && response.StatusCode == http.StatusForbidden {
+			err = ErrForbidden
+		}
+		if response != nil && response.Bytes.Len() > 0 {
+			return fmt.Errorf("request to %s failed: %w: %s", endpoint, err, strings.TrimSpace(response.Bytes.String()))
+		}
+		return fmt.Errorf("request to %s failed: %w", endpoint, err)
+	}
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("request to %s failed, status: %d", endpoint, response.StatusCode)
+	}
+	return nil
+}
+
+// issueRefFromFields builds an issue reference from a raw fields map
+func issueRefFromFields(key string, fields map[string]interface{}) ClientJiraIssueRef {
+	ref := ClientJiraIssueRef{Key: key}
+	ref.Summary, _ = fields["summary"].(string)
+	if status, ok := fields["status"].(map[string]interface{}); ok {
+		ref.Status, _ = status["name"].(string)
+	}
+	if issueType, ok := fields["issuetype"].(map[string]interface{}); ok {
+		ref.IssueType, _ = issueType["name"].(string)
+	}
+	return ref
+}
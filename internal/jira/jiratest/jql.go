@@ -0,0 +1,193 @@
+package jiratest
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// The fake supports a small JQL subset: clauses of the form
+// `field = value`, `field != value`, `field in (a, b)` and `field not in (a, b)`
+// joined with AND and OR (AND binding tighter, no parentheses), optionally followed
+// by ORDER BY (which is ignored; results are always ordered by key). Supported fields
+// are listed in jqlFieldValues; custom fields are referenced by their fixture name or
+// as cf[10014].
+
+var (
+	orderByPattern = regexp.MustCompile(`(?i)\s+order\s+by\s+.*$`)
+	andPattern     = regexp.MustCompile(`(?i)\s+and\s+`)
+	orPattern      = regexp.MustCompile(`(?i)\s+or\s+`)
+	clausePattern  = regexp.MustCompile(`(?i)^\s*("[^"]+"|cf\[\d+\]|[\w.]+)\s*(!=|=|not\s+in|in)\s*(.+?)\s*$`)
+	cfPattern      = regexp.MustCompile(`(?i)^cf\[(\d+)\]$`)
+)
+
+// jqlClause is a single field condition
+type jqlClause struct {
+	actual func(issue map[string]interface{}) []string
+	negate bool
+	values []string
+}
+
+// jqlQuery is a disjunction of conjunctions of clauses
+type jqlQuery struct {
+	alternatives [][]jqlClause
+}
+
+// parseJQL parses the supported JQL subset. customField returns the ID of the
+// custom field with the given name, or "" when there is none.
+func parseJQL(jql string, customField func(name string) string) (*jqlQuery, error) {
+	jql = strings.TrimSpace(orderByPattern.ReplaceAllString(" "+jql, ""))
+	if strings.HasPrefix(strings.ToLower(jql), "order by") {
+		jql = ""
+	}
+	query := &jqlQuery{}
+	if jql == "" {
+		return query, nil
+	}
+	for _, alternative := range orPattern.Split(jql, -1) {
+		var clauses []jqlClause
+		for _, part := range andPattern.Split(alternative, -1) {
+			clause, err := parseClause(part, customField)
+			if err != nil {
+				return nil, err
+			}
+			clauses = append(clauses, clause)
+		}
+		query.alternatives = append(query.alternatives, clauses)
+	}
+	return query, nil
+}
+
+// parseClause parses a single field condition
+func parseClause(part string, customField func(name string) string) (jqlClause, error) {
+	m := clausePattern.FindStringSubmatch(part)
+	if m == nil {
+		return jqlClause{}, fmt.Errorf("unsupported JQL clause: %q", part)
+	}
+	name := strings.Trim(m[1], `"`)
+	actual, ok := jqlFieldValues[strings.ToLower(name)]
+	if !ok {
+		id := customField(name)
+		if m := cfPattern.FindStringSubmatch(name); m != nil {
+			id = "customfield_" + m[1]
+		}
+		if id == "" {
+			return jqlClause{}, fmt.Errorf("field '%s' is not supported by the fake Jira server", name)
+		}
+		actual = func(issue map[string]interface{}) []string { return customFieldValues(issue, id) }
+	}
+	op := strings.ToLower(strings.Join(strings.Fields(m[2]), " "))
+	clause := jqlClause{actual: actual, negate: op == "!=" || op == "not in"}
+	if op == "in" || op == "not in" {
+		list := strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(m[3]), "("), ")")
+		for _, v := range strings.Split(list, ",") {
+			clause.values = append(clause.values, unquote(v))
+		}
+	} else {
+		clause.values = []string{unquote(m[3])}
+	}
+	return clause, nil
+}
+
+// matches reports whether an issue satisfies every clause of any alternative
+func (q *jqlQuery) matches(issue map[string]interface{}) bool {
+	if len(q.alternatives) == 0 {
+		return true
+	}
+	for _, clauses := range q.alternatives {
+		if matchesAll(clauses, issue) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAll reports whether an issue satisfies every clause
+func matchesAll(clauses []jqlClause, issue map[string]interface{}) bool {
+	for _, clause := range clauses {
+		actual := clause.actual(issue)
+		found := false
+		for _, want := range clause.values {
+			for _, have := range actual {
+				if strings.EqualFold(want, have) {
+					found = true
+				}
+			}
+		}
+		if found == clause.negate {
+			return false
+		}
+	}
+	return true
+}
+
+// jqlFieldValues extracts the comparable values of a JQL field from an issue
+var jqlFieldValues = map[string]func(issue map[string]interface{}) []string{
+	"key":      func(issue map[string]interface{}) []string { return []string{str(issue["key"])} },
+	"issuekey": func(issue map[string]interface{}) []string { return []string{str(issue["key"])} },
+	"id":       func(issue map[string]interface{}) []string { return []string{str(issue["id"])} },
+	"project":  func(issue map[string]interface{}) []string { return nested(issue, "project", "key", "name", "id") },
+	"status":   func(issue map[string]interface{}) []string { return nested(issue, "status", "name", "id") },
+	"statuscategory": func(issue map[string]interface{}) []string {
+		status, _ := issueFields(issue)["status"].(map[string]interface{})
+		category, _ := status["statusCategory"].(map[string]interface{})
+		return []string{str(category["key"]), str(category["name"]), str(category["id"])}
+	},
+	"priority":  func(issue map[string]interface{}) []string { return nested(issue, "priority", "name", "id") },
+	"issuetype": func(issue map[string]interface{}) []string { return nested(issue, "issuetype", "name", "id") },
+	"type":      func(issue map[string]interface{}) []string { return nested(issue, "issuetype", "name", "id") },
+	"parent":    func(issue map[string]interface{}) []string { return nested(issue, "parent", "key", "id") },
+	"assignee": func(issue map[string]interface{}) []string {
+		return nested(issue, "assignee", "accountId", "displayName")
+	},
+	"reporter": func(issue map[string]interface{}) []string {
+		return nested(issue, "reporter", "accountId", "displayName")
+	},
+	"labels": func(issue map[string]interface{}) []string {
+		labels, _ := issueFields(issue)["labels"].([]interface{})
+		out := make([]string, 0, len(labels))
+		for _, l := range labels {
+			out = append(out, str(l))
+		}
+		return out
+	},
+}
+
+// customFieldValues returns the value of a custom field, or the key and value of an object-valued one
+func customFieldValues(issue map[string]interface{}, id string) []string {
+	switch v := issueFields(issue)[id].(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		return []string{str(v["key"]), str(v["value"])}
+	default:
+		return []string{str(v)}
+	}
+}
+
+// nested returns the given attributes of an object-valued field
+func nested(issue map[string]interface{}, field string, attrs ...string) []string {
+	obj, ok := issueFields(issue)[field].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	var out []string
+	for _, attr := range attrs {
+		if v := str(obj[attr]); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func str(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+func unquote(s string) string {
+	s = strings.TrimSpace(s)
+	return strings.Trim(s, `"'`)
+}
@@ -0,0 +1,879 @@
+// Package jiratest provides an in-memory fake of the Jira REST API.
+//
+// The fake implements the endpoints used by jira.Client, is seeded from fixture
+// JSON and records every write (comments, field edits, transitions) so callers can
+// assert on what was sent to Jira. It can be started on an httptest listener for
+// tests or served on a fixed address by cmd/fakejira for local development.
+package jiratest
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+//go:embed fixtures/default.json
+var defaultFixtures embed.FS
+
+// jiraTimeFormat is the timestamp format used by the Jira REST API
+const jiraTimeFormat = "2006-01-02T15:04:05.000-0700"
+
+// Fixtures is the data a fake server is seeded with.
+// Issues use the raw Jira REST representation ({"id", "key", "fields"}).
+type Fixtures struct {
+	Myself      map[string]interface{}   `json:"myself"`
+	Users       []map[string]interface{} `json:"users"`
+	Fields      []map[string]interface{} `json:"fields"`
+	Transitions []map[string]interface{} `json:"transitions"` // Transitions offered for every issue
+	Boards      []map[string]interface{} `json:"boards"`
+	Sprints     []map[string]interface{} `json:"sprints"` // Issues join sprints through the "Sprint" custom field
+	Issues      []map[string]interface{} `json:"issues"`
+	// RemoteLinks holds the remote links of each issue, keyed by issue key
+	RemoteLinks map[string][]map[string]interface{} `json:"remoteLinks"`
+	// Development holds the development panel of each issue, keyed by issue key
+	Development map[string]*Development `json:"development"`
+	// AttachmentContent holds the content of attachments listed in issue "attachment" fields, keyed by attachment ID
+	AttachmentContent map[string]string `json:"attachmentContent"`
+}
+
+// Development is the fixture for an issue's development panel
+type Development struct {
+	Summary      map[string]interface{}              `json:"summary"`      // Body of the dev-status summary "summary" field
+	PullRequests map[string][]map[string]interface{} `json:"pullRequests"` // Pull requests by application type, e.g. "GitHub"
+}
+
+// Write is a mutating request received by the fake server
+type Write struct {
+	Method   string          `json:"method"`
+	Path     string          `json:"path"`
+	IssueKey string          `json:"issueKey,omitempty"`
+	Body     json.RawMessage `json:"body,omitempty"`
+	Time     time.Time       `json:"time"`
+}
+
+// Server is an in-memory fake Jira site
+type Server struct {
+	mux *http.ServeMux
+	ts  *httptest.Server
+
+	mu            sync.Mutex
+	fixtures      *Fixtures
+	issues        map[string]map[string]interface{} // keyed by issue key
+	writes        []Write
+	nextCommentID int
+	webhooks      []Webhook
+	properties    map[string]map[string]json.RawMessage // issue key -> property key -> value
+	nextWebhookID int
+	// OnWrite, when set, is called for every recorded write
+	OnWrite func(Write)
+}
+
+// DefaultFixtures returns the fixtures bundled with the package
+func DefaultFixtures() *Fixtures {
+	data, err := defaultFixtures.ReadFile("fixtures/default.json")
+	if err != nil {
+		panic(fmt.Sprintf("jiratest: missing embedded fixtures: %v", err))
+	}
+	fixtures, err := ParseFixtures(data)
+	if err != nil {
+		panic(fmt.Sprintf("jiratest: invalid embedded fixtures: %v", err))
+	}
+	return fixtures
+}
+
+// LoadFixtures reads fixtures from a JSON file
+func LoadFixtures(path string) (*Fixtures, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixtures: %w", err)
+	}
+	return ParseFixtures(data)
+}
+
+// ParseFixtures decodes fixtures from JSON
+func ParseFixtures(data []byte) (*Fixtures, error) {
+	var fixtures Fixtures
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		return nil, fmt.Errorf("failed to parse fixtures: %w", err)
+	}
+	return &fixtures, nil
+}
+
+// NewServer creates a fake Jira server seeded with the given fixtures.
+// Passing nil uses DefaultFixtures.
+func NewServer(fixtures *Fixtures) *Server {
+	if fixtures == nil {
+		fixtures = DefaultFixtures()
+	}
+	s := &Server{
+		mux:           http.NewServeMux(),
+		fixtures:      fixtures,
+		issues:        make(map[string]map[string]interface{}),
+		nextCommentID: 30000,
+		nextWebhookID: 1000,
+		properties:    make(map[string]map[string]json.RawMessage),
+	}
+	for _, issue := range fixtures.Issues {
+		if key, ok := issue["key"].(string); ok {
+			s.issues[key] = deepCopy(issue)
+		}
+	}
+	s.routes()
+	return s
+}
+
+// Start serves the fake on a local httptest listener and returns its base URL
+func (s *Server) Start() string {
+	s.ts = httptest.NewServer(s)
+	return s.ts.URL
+}
+
+// URL returns the base URL of a started server
+func (s *Server) URL() string {
+	if s.ts == nil {
+		return ""
+	}
+	return s.ts.URL
+}
+
+// Close stops a started server
+func (s *Server) Close() {
+	if s.ts != nil {
+		s.ts.Close()
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Handle registers an additional handler, e.g. to simulate an endpoint failure
+func (s *Server) Handle(pattern string, handler http.HandlerFunc) {
+	s.mux.HandleFunc(pattern, handler)
+}
+
+// Writes returns all mutating requests received so far
+func (s *Server) Writes() []Write {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Write(nil), s.writes...)
+}
+
+// Issue returns a copy of the current state of an issue
+func (s *Server) Issue(key string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issue, ok := s.issues[key]
+	if !ok {
+		return nil, false
+	}
+	return deepCopy(issue), true
+}
+
+// PutIssue adds or replaces an issue
+func (s *Server) PutIssue(issue map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if key, ok := issue["key"].(string); ok {
+		s.issues[key] = deepCopy(issue)
+	}
+}
+
+// Property returns the raw value of an issue entity property
+func (s *Server) Property(key, property string) (json.RawMessage, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.properties[key][property]
+	return value, ok
+}
+
+// Comments returns the comments currently stored on an issue
+func (s *Server) Comments(key string) []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issue, ok := s.issues[key]
+	if !ok {
+		return nil
+	}
+	var out []map[string]interface{}
+	for _, c := range commentList(issue) {
+		out = append(out, deepCopy(c))
+	}
+	return out
+}
+
+// routes registers the supported Jira endpoints
+func (s *Server) routes() {
+	s.mux.HandleFunc("GET /rest/api/2/myself", s.handleMyself)
+	s.mux.HandleFunc("GET /rest/api/2/field", s.handleFields)
+	s.mux.HandleFunc("GET /rest/api/2/user", s.handleGetUser)
+	s.mux.HandleFunc("GET /rest/api/2/user/search", s.handleSearchUsers)
+	s.mux.HandleFunc("GET /rest/api/2/search", s.handleSearch)
+	s.mux.HandleFunc("POST /rest/api/2/search", s.handleSearch)
+	s.mux.HandleFunc("GET /rest/api/2/search/jql", s.handleSearch)
+	s.mux.HandleFunc("POST /rest/api/2/search/jql", s.handleSearch)
+	s.mux.HandleFunc("GET /rest/api/2/issue/{key}", s.handleGetIssue)
+	s.mux.HandleFunc("PUT /rest/api/2/issue/{key}", s.handleEditIssue)
+	s.mux.HandleFunc("GET /rest/api/2/issue/{key}/comment", s.handleGetComments)
+	s.mux.HandleFunc("POST /rest/api/2/issue/{key}/comment", s.handleAddComment)
+	s.mux.HandleFunc("GET /rest/api/2/issue/{key}/transitions", s.handleGetTransitions)
+	s.mux.HandleFunc("POST /rest/api/2/issue/{key}/transitions", s.handleDoTransition)
+	s.mux.HandleFunc("GET /rest/api/2/issue/{key}/remotelink", s.handleGetRemoteLinks)
+	s.mux.HandleFunc("GET /rest/api/2/attachment/content/{id}", s.handleGetAttachmentContent)
+	s.mux.HandleFunc("GET /rest/api/2/issue/{key}/properties/{property}", s.handleGetProperty)
+	s.mux.HandleFunc("PUT /rest/api/2/issue/{key}/properties/{property}", s.handleSetProperty)
+	s.mux.HandleFunc("GET /rest/dev-status/latest/issue/summary", s.handleDevStatusSummary)
+	s.mux.HandleFunc("GET /rest/dev-status/latest/issue/detail", s.handleDevStatusDetail)
+	s.mux.HandleFunc("GET /rest/api/2/webhook", s.handleListWebhooks)
+	s.mux.HandleFunc("POST /rest/api/2/webhook", s.handleRegisterWebhooks)
+	s.mux.HandleFunc("DELETE /rest/api/2/webhook", s.handleDeleteWebhooks)
+	s.mux.HandleFunc("PUT /rest/api/2/webhook/refresh", s.handleRefreshWebhooks)
+	s.mux.HandleFunc("GET /rest/agile/1.0/issue/{key}", s.handleGetAgileIssue)
+	s.mux.HandleFunc("GET /rest/agile/1.0/board/{id}", s.handleGetBoard)
+	s.mux.HandleFunc("GET /rest/agile/1.0/sprint/{id}", s.handleGetSprint)
+}
+
+func (s *Server) handleMyself(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.fixtures.Myself)
+}
+
+func (s *Server) handleFields(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.fixtures.Fields)
+}
+
+func (s *Server) handleGetUser(w http.ResponseWriter, r *http.Request) {
+	accountID := r.URL.Query().Get("accountId")
+	for _, user := range s.fixtures.Users {
+		if user["accountId"] == accountID {
+			writeJSON(w, http.StatusOK, user)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, fmt.Sprintf("Specified user does not exist or you do not have required permissions: %s", accountID))
+}
+
+func (s *Server) handleSearchUsers(w http.ResponseWriter, r *http.Request) {
+	query := strings.ToLower(r.URL.Query().Get("query"))
+	matches := []map[string]interface{}{}
+	for _, user := range s.fixtures.Users {
+		name, _ := user["displayName"].(string)
+		email, _ := user["emailAddress"].(string)
+		if query == "" || strings.Contains(strings.ToLower(name), query) || strings.Contains(strings.ToLower(email), query) {
+			matches = append(matches, user)
+		}
+	}
+	writeJSON(w, http.StatusOK, matches)
+}
+
+func (s *Server) handleGetIssue(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issue, ok := s.lookupIssue(r.PathValue("key"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Issue does not exist or you do not have permission to see it.")
+		return
+	}
+	out := selectFields(issue, splitList(r.URL.Query().Get("fields")))
+	if !strings.Contains(r.URL.Query().Get("expand"), "changelog") {
+		delete(out, "changelog")
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) handleGetRemoteLinks(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issue, ok := s.lookupIssue(r.PathValue("key"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Issue does not exist or you do not have permission to see it.")
+		return
+	}
+	links := s.fixtures.RemoteLinks[issue["key"].(string)]
+	if links == nil {
+		links = []map[string]interface{}{}
+	}
+	writeJSON(w, http.StatusOK, links)
+}
+
+func (s *Server) handleGetAttachmentContent(w http.ResponseWriter, r *http.Request) {
+	content, ok := s.fixtures.AttachmentContent[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "The attachment does not exist.")
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	io.WriteString(w, content)
+}
+
+func (s *Server) handleGetProperty(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issue, ok := s.lookupIssue(r.PathValue("key"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Issue does not exist or you do not have permission to see it.")
+		return
+	}
+	property := r.PathValue("property")
+	value, ok := s.properties[issue["key"].(string)][property]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("The property with key '%s' does not exist.", property))
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"key": property, "value": value})
+}
+
+func (s *Server) handleSetProperty(w http.ResponseWriter, r *http.Request) {
+	var value json.RawMessage
+	body, ok := s.readBody(w, r, &value)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issue, found := s.lookupIssue(r.PathValue("key"))
+	if !found {
+		writeError(w, http.StatusNotFound, "Issue does not exist or you do not have permission to see it.")
+		return
+	}
+	key := issue["key"].(string)
+	status := http.StatusOK
+	if s.properties[key] == nil {
+		s.properties[key] = make(map[string]json.RawMessage)
+	}
+	if _, exists := s.properties[key][r.PathValue("property")]; !exists {
+		status = http.StatusCreated
+	}
+	s.properties[key][r.PathValue("property")] = value
+	s.recordWrite(r, key, body)
+	w.WriteHeader(status)
+}
+
+func (s *Server) handleDevStatusSummary(w http.ResponseWriter, r *http.Request) {
+	summary := map[string]interface{}{}
+	if dev := s.development(r.URL.Query().Get("issueId")); dev != nil && dev.Summary != nil {
+		summary = dev.Summary
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"errors": []interface{}{}, "summary": summary})
+}
+
+// handleDevStatusDetail serves pull request details; other data types are reported as empty
+func (s *Server) handleDevStatusDetail(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	pullRequests := []map[string]interface{}{}
+	if dev := s.development(query.Get("issueId")); dev != nil && query.Get("dataType") == "pullrequest" {
+		if prs, ok := dev.PullRequests[query.Get("applicationType")]; ok {
+			pullRequests = prs
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"errors": []interface{}{},
+		"detail": []interface{}{map[string]interface{}{"pullRequests": pullRequests}},
+	})
+}
+
+// development returns the development panel fixture for an issue ID
+func (s *Server) development(issueID string) *Development {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issue, ok := s.lookupIssue(issueID)
+	if !ok {
+		return nil
+	}
+	return s.fixtures.Development[issue["key"].(string)]
+}
+
+// handleGetAgileIssue serves the Agile API view of an issue, which adds the
+// sprint, closedSprints and project fields derived from the Sprint custom field
+func (s *Server) handleGetAgileIssue(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issue, ok := s.lookupIssue(r.PathValue("key"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Issue does not exist or you do not have permission to see it.")
+		return
+	}
+
+	agileIssue := deepCopy(issue)
+	delete(agileIssue, "changelog")
+	fields := issueFields(agileIssue)
+	var closed []interface{}
+	if field := s.fieldID("Sprint"); field != "" {
+		refs, _ := fields[field].([]interface{})
+		for _, ref := range refs {
+			sprint := s.sprint(ref)
+			if sprint == nil {
+				continue
+			}
+			if sprint["state"] == "closed" {
+				closed = append(closed, sprint)
+			} else {
+				fields["sprint"] = sprint
+			}
+		}
+	}
+	if closed != nil {
+		fields["closedSprints"] = closed
+	}
+	writeJSON(w, http.StatusOK, selectFields(agileIssue, splitList(r.URL.Query().Get("fields"))))
+}
+
+func (s *Server) handleGetBoard(w http.ResponseWriter, r *http.Request) {
+	if board := findByID(s.fixtures.Boards, r.PathValue("id")); board != nil {
+		writeJSON(w, http.StatusOK, board)
+		return
+	}
+	writeError(w, http.StatusNotFound, "Board does not exist or you do not have permission to see it.")
+}
+
+func (s *Server) handleGetSprint(w http.ResponseWriter, r *http.Request) {
+	if sprint := findByID(s.fixtures.Sprints, r.PathValue("id")); sprint != nil {
+		writeJSON(w, http.StatusOK, sprint)
+		return
+	}
+	writeError(w, http.StatusNotFound, "Sprint does not exist or you do not have permission to see it.")
+}
+
+func (s *Server) handleEditIssue(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Fields map[string]interface{} `json:"fields"`
+	}
+	body, ok := s.readBody(w, r, &payload)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issue, found := s.lookupIssue(r.PathValue("key"))
+	if !found {
+		writeError(w, http.StatusNotFound, "Issue does not exist or you do not have permission to see it.")
+		return
+	}
+	fields := issueFields(issue)
+	for name, value := range payload.Fields {
+		fields[name] = value
+	}
+	s.recordWrite(r, issue["key"].(string), body)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleGetComments(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issue, ok := s.lookupIssue(r.PathValue("key"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Issue does not exist or you do not have permission to see it.")
+		return
+	}
+	comments := commentList(issue)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"startAt":    0,
+		"maxResults": len(comments),
+		"total":      len(comments),
+		"comments":   comments,
+	})
+}
+
+func (s *Server) handleAddComment(w http.ResponseWriter, r *http.Request) {
+	var payload map[string]interface{}
+	body, ok := s.readBody(w, r, &payload)
+	if !ok {
+		return
+	}
+	if text, _ := payload["body"].(string); text == "" {
+		writeError(w, http.StatusBadRequest, "Comment body can not be empty!")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issue, found := s.lookupIssue(r.PathValue("key"))
+	if !found {
+		writeError(w, http.StatusNotFound, "Issue does not exist or you do not have permission to see it.")
+		return
+	}
+	comment := s.addComment(issue, payload)
+	s.recordWrite(r, issue["key"].(string), body)
+	writeJSON(w, http.StatusCreated, comment)
+}
+
+func (s *Server) handleGetTransitions(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.lookupIssue(r.PathValue("key")); !ok {
+		writeError(w, http.StatusNotFound, "Issue does not exist or you do not have permission to see it.")
+		return
+	}
+	// Like Jira, transition screen fields are only included when expanded
+	expandFields := strings.Contains(r.URL.Query().Get("expand"), "transitions.fields")
+	transitions := make([]map[string]interface{}, 0, len(s.fixtures.Transitions))
+	for _, t := range s.fixtures.Transitions {
+		transition := deepCopy(t)
+		if !expandFields {
+			delete(transition, "fields")
+		}
+		transitions = append(transitions, transition)
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"transitions": transitions})
+}
+
+func (s *Server) handleDoTransition(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Transition struct {
+			ID string `json:"id"`
+		} `json:"transition"`
+		Fields map[string]interface{}              `json:"fields"`
+		Update map[string][]map[string]interface{} `json:"update"`
+	}
+	body, ok := s.readBody(w, r, &payload)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issue, found := s.lookupIssue(r.PathValue("key"))
+	if !found {
+		writeError(w, http.StatusNotFound, "Issue does not exist or you do not have permission to see it.")
+		return
+	}
+
+	var transition map[string]interface{}
+	for _, t := range s.fixtures.Transitions {
+		if fmt.Sprintf("%v", t["id"]) == payload.Transition.ID {
+			transition = t
+			break
+		}
+	}
+	if transition == nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Transition id '%s' is not valid for this issue.", payload.Transition.ID))
+		return
+	}
+
+	screen, _ := transition["fields"].(map[string]interface{})
+	for name, meta := range screen {
+		if m, _ := meta.(map[string]interface{}); m["required"] == true && payload.Fields[name] == nil {
+			writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+				"errorMessages": []string{},
+				"errors":        map[string]string{name: fmt.Sprintf("%v is required.", m["name"])},
+			})
+			return
+		}
+	}
+
+	fields := issueFields(issue)
+	if to, ok := transition["to"]; ok {
+		fields["status"] = deepCopyValue(to)
+	}
+	for name, value := range payload.Fields {
+		fields[name] = value
+	}
+	for _, op := range payload.Update["comment"] {
+		if add, ok := op["add"].(map[string]interface{}); ok {
+			s.addComment(issue, add)
+		}
+	}
+	s.recordWrite(r, issue["key"].(string), body)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	params := struct {
+		JQL           string      `json:"jql"`
+		Fields        []string    `json:"fields"`
+		MaxResults    int         `json:"maxResults"`
+		StartAt       int         `json:"startAt"`
+		NextPageToken string      `json:"nextPageToken"`
+		Expand        interface{} `json:"expand"`
+	}{MaxResults: 50}
+
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid request payload")
+			return
+		}
+	} else {
+		q := r.URL.Query()
+		params.JQL = q.Get("jql")
+		params.Fields = splitList(q.Get("fields"))
+		params.NextPageToken = q.Get("nextPageToken")
+		if v, err := strconv.Atoi(q.Get("maxResults")); err == nil {
+			params.MaxResults = v
+		}
+		if v, err := strconv.Atoi(q.Get("startAt")); err == nil {
+			params.StartAt = v
+		}
+	}
+	if params.MaxResults <= 0 {
+		params.MaxResults = 50
+	}
+
+	query, err := parseJQL(params.JQL, s.fieldID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := make([]string, 0, len(s.issues))
+	for key := range s.issues {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return lessIssueKey(keys[i], keys[j]) })
+
+	var matched []map[string]interface{}
+	for _, key := range keys {
+		if query.matches(s.issues[key]) {
+			matched = append(matched, s.issues[key])
+		}
+	}
+
+	start := params.StartAt
+	if params.NextPageToken != "" {
+		start, _ = strconv.Atoi(params.NextPageToken)
+	}
+	if start > len(matched) {
+		start = len(matched)
+	}
+	end := start + params.MaxResults
+	if end > len(matched) {
+		end = len(matched)
+	}
+
+	issues := make([]map[string]interface{}, 0, end-start)
+	for _, issue := range matched[start:end] {
+		issues = append(issues, selectFields(issue, params.Fields))
+	}
+	result := map[string]interface{}{
+		"startAt":    start,
+		"maxResults": params.MaxResults,
+		"total":      len(matched),
+		"issues":     issues,
+	}
+	if end < len(matched) {
+		result["nextPageToken"] = strconv.Itoa(end)
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// lookupIssue finds an issue by key or ID; the caller must hold s.mu
+func (s *Server) lookupIssue(keyOrID string) (map[string]interface{}, bool) {
+	if issue, ok := s.issues[keyOrID]; ok {
+		return issue, true
+	}
+	for _, issue := range s.issues {
+		if issue["id"] == keyOrID {
+			return issue, true
+		}
+	}
+	return nil, false
+}
+
+// fieldID returns the ID of the fixture field with the given name
+func (s *Server) fieldID(name string) string {
+	for _, field := range s.fixtures.Fields {
+		if field["name"] == name {
+			id, _ := field["id"].(string)
+			return id
+		}
+	}
+	return ""
+}
+
+// sprint resolves a Sprint custom field value to the full fixture sprint,
+// falling back to the value itself when the sprint is not in the fixtures
+func (s *Server) sprint(ref interface{}) map[string]interface{} {
+	value, ok := ref.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if sprint := findByID(s.fixtures.Sprints, fmt.Sprint(value["id"])); sprint != nil {
+		return deepCopy(sprint)
+	}
+	return deepCopy(value)
+}
+
+// addComment appends a comment to an issue; the caller must hold s.mu
+func (s *Server) addComment(issue map[string]interface{}, payload map[string]interface{}) map[string]interface{} {
+	s.nextCommentID++
+	comment := deepCopy(payload)
+	comment["id"] = strconv.Itoa(s.nextCommentID)
+	comment["author"] = s.fixtures.Myself
+	comment["created"] = time.Now().Format(jiraTimeFormat)
+	comment["updated"] = comment["created"]
+
+	fields := issueFields(issue)
+	page, _ := fields["comment"].(map[string]interface{})
+	if page == nil {
+		page = map[string]interface{}{"startAt": 0}
+		fields["comment"] = page
+	}
+	comments, _ := page["comments"].([]interface{})
+	comments = append(comments, comment)
+	page["comments"] = comments
+	page["total"] = len(comments)
+	page["maxResults"] = len(comments)
+	return comment
+}
+
+// recordWrite stores a mutating request; the caller must hold s.mu
+func (s *Server) recordWrite(r *http.Request, issueKey string, body []byte) {
+	write := Write{
+		Method:   r.Method,
+		Path:     r.URL.Path,
+		IssueKey: issueKey,
+		Body:     json.RawMessage(body),
+		Time:     time.Now(),
+	}
+	s.writes = append(s.writes, write)
+	if s.OnWrite != nil {
+		s.OnWrite(write)
+	}
+}
+
+// readBody reads and decodes a JSON request body, writing an error response on failure
+func (s *Server) readBody(w http.ResponseWriter, r *http.Request, out interface{}) ([]byte, bool) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Failed to read request body")
+		return nil, false
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request payload")
+		return nil, false
+	}
+	return body, true
+}
+
+// issueFields returns the fields map of an issue, creating it if needed
+func issueFields(issue map[string]interface{}) map[string]interface{} {
+	fields, ok := issue["fields"].(map[string]interface{})
+	if !ok {
+		fields = map[string]interface{}{}
+		issue["fields"] = fields
+	}
+	return fields
+}
+
+// commentList returns the comments stored on an issue
+func commentList(issue map[string]interface{}) []map[string]interface{} {
+	page, _ := issueFields(issue)["comment"].(map[string]interface{})
+	raw, _ := page["comments"].([]interface{})
+	comments := make([]map[string]interface{}, 0, len(raw))
+	for _, c := range raw {
+		if comment, ok := c.(map[string]interface{}); ok {
+			comments = append(comments, comment)
+		}
+	}
+	return comments
+}
+
+// selectFields returns a copy of the issue restricted to the requested fields, like Jira does
+func selectFields(issue map[string]interface{}, fields []string) map[string]interface{} {
+	out := deepCopy(issue)
+	if len(fields) == 0 {
+		return out
+	}
+	all := false
+	wanted := map[string]bool{}
+	for _, f := range fields {
+		if f == "*all" || f == "*navigable" {
+			all = true
+		}
+		wanted[f] = true
+	}
+	if all {
+		return out
+	}
+	selected := map[string]interface{}{}
+	for name, value := range issueFields(out) {
+		if wanted[name] {
+			selected[name] = value
+		}
+	}
+	out["fields"] = selected
+	return out
+}
+
+// findByID returns the fixture object whose numeric or string "id" matches
+func findByID(items []map[string]interface{}, id string) map[string]interface{} {
+	for _, item := range items {
+		if fmt.Sprint(item["id"]) == id {
+			return item
+		}
+	}
+	return nil
+}
+
+// lessIssueKey orders issue keys by project, then numerically by issue number
+func lessIssueKey(a, b string) bool {
+	pa, na := splitIssueKey(a)
+	pb, nb := splitIssueKey(b)
+	if pa != pb {
+		return pa < pb
+	}
+	return na < nb
+}
+
+func splitIssueKey(key string) (string, int) {
+	idx := strings.LastIndex(key, "-")
+	if idx < 0 {
+		return key, 0
+	}
+	n, _ := strconv.Atoi(key[idx+1:])
+	return key[:idx], n
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error in the Jira REST error format
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]interface{}{
+		"errorMessages": []string{message},
+		"errors":        map[string]string{},
+	})
+}
+
+func deepCopy(m map[string]interface{}) map[string]interface{} {
+	copied, _ := deepCopyValue(m).(map[string]interface{})
+	return copied
+}
+
+// deepCopyValue copies a decoded JSON value so stored fixtures are never aliased by callers
+func deepCopyValue(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return v
+	}
+	return out
+}
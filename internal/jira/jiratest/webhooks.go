@@ -0,0 +1,156 @@
+package jiratest
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// webhookLifetime is how long Jira keeps a dynamic webhook before it expires
+const webhookLifetime = 30 * 24 * time.Hour
+
+// Webhook is a dynamic webhook registered with the fake server
+type Webhook struct {
+	ID             int       `json:"id"`
+	URL            string    `json:"url"`
+	JQLFilter      string    `json:"jqlFilter"`
+	Events         []string  `json:"events"`
+	ExpirationDate time.Time `json:"expirationDate"`
+}
+
+// Webhooks returns the currently registered dynamic webhooks
+func (s *Server) Webhooks() []Webhook {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Webhook(nil), s.webhooks...)
+}
+
+// AddWebhook registers a webhook directly, e.g. to simulate one left over from a previous run
+func (s *Server) AddWebhook(hook Webhook) Webhook {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if hook.ID == 0 {
+		s.nextWebhookID++
+		hook.ID = s.nextWebhookID
+	}
+	if hook.ExpirationDate.IsZero() {
+		hook.ExpirationDate = time.Now().Add(webhookLifetime)
+	}
+	s.webhooks = append(s.webhooks, hook)
+	return hook
+}
+
+func (s *Server) handleListWebhooks(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	values := make([]map[string]interface{}, 0, len(s.webhooks))
+	for _, hook := range s.webhooks {
+		values = append(values, map[string]interface{}{
+			"id":             hook.ID,
+			"url":            hook.URL,
+			"jqlFilter":      hook.JQLFilter,
+			"events":         hook.Events,
+			"expirationDate": hook.ExpirationDate.Format(jiraTimeFormat),
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"startAt":    0,
+		"maxResults": 100,
+		"total":      len(values),
+		"isLast":     true,
+		"values":     values,
+	})
+}
+
+func (s *Server) handleRegisterWebhooks(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		URL      string `json:"url"`
+		Webhooks []struct {
+			JQLFilter string   `json:"jqlFilter"`
+			Events    []string `json:"events"`
+		} `json:"webhooks"`
+	}
+	body, ok := s.readBody(w, r, &payload)
+	if !ok {
+		return
+	}
+	if payload.URL == "" {
+		writeError(w, http.StatusBadRequest, "The webhook URL is required.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	results := make([]map[string]interface{}, 0, len(payload.Webhooks))
+	for _, spec := range payload.Webhooks {
+		if spec.JQLFilter == "" || len(spec.Events) == 0 {
+			results = append(results, map[string]interface{}{"errors": []string{"A JQL filter and at least one event are required."}})
+			continue
+		}
+		s.nextWebhookID++
+		s.webhooks = append(s.webhooks, Webhook{
+			ID:             s.nextWebhookID,
+			URL:            payload.URL,
+			JQLFilter:      spec.JQLFilter,
+			Events:         spec.Events,
+			ExpirationDate: time.Now().Add(webhookLifetime),
+		})
+		results = append(results, map[string]interface{}{"createdWebhookId": s.nextWebhookID})
+	}
+	s.recordWrite(r, "", body)
+	writeJSON(w, http.StatusOK, map[string]interface{}{"webhookRegistrationResult": results})
+}
+
+func (s *Server) handleRefreshWebhooks(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		WebhookIDs []int `json:"webhookIds"`
+	}
+	body, ok := s.readBody(w, r, &payload)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expiry := time.Now().Add(webhookLifetime)
+	for _, id := range payload.WebhookIDs {
+		i := s.webhookIndex(id)
+		if i < 0 {
+			writeError(w, http.StatusNotFound, "Webhook "+strconv.Itoa(id)+" does not exist.")
+			return
+		}
+		s.webhooks[i].ExpirationDate = expiry
+	}
+	s.recordWrite(r, "", body)
+	writeJSON(w, http.StatusOK, map[string]interface{}{"expirationDate": expiry.Format(jiraTimeFormat)})
+}
+
+func (s *Server) handleDeleteWebhooks(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		WebhookIDs []int `json:"webhookIds"`
+	}
+	body, ok := s.readBody(w, r, &payload)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range payload.WebhookIDs {
+		if i := s.webhookIndex(id); i >= 0 {
+			s.webhooks = append(s.webhooks[:i], s.webhooks[i+1:]...)
+		}
+	}
+	s.recordWrite(r, "", body)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// webhookIndex returns the position of a webhook, or -1; the caller must hold s.mu
+func (s *Server) webhookIndex(id int) int {
+	for i, hook := range s.webhooks {
+		if hook.ID == id {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,64 @@
+package jira
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// MaxIssuePropertyBytes is the largest JSON value Jira accepts for an issue entity property.
+const MaxIssuePropertyBytes = 32 * 1024
+
+// ErrPropertyTooLarge is returned when a property value exceeds MaxIssuePropertyBytes
+var ErrPropertyTooLarge = errors.New("issue property value too large")
+
+// SetIssueProperty stores a JSON value as an issue entity property.
+// Values larger than MaxIssuePropertyBytes are rejected with ErrPropertyTooLarge.
+func (c *Client) SetIssueProperty(ticketID, propertyKey string, value interface{}) error {
+	if c.JiraClient == nil {
+		return fmt.Errorf("jira client not initialized")
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode issue property: %w", err)
+	}
+	if len(data) > MaxIssuePropertyBytes {
+		return fmt.Errorf("%w: %d bytes, limit %d", ErrPropertyTooLarge, len(data), MaxIssuePropertyBytes)
+	}
+
+	response, err := c.JiraClient.Issue.Property.Set(c.Ctx, ticketID, propertyKey, json.RawMessage(data))
+	if err != nil {
+		return fmt.Errorf("failed to set issue property: %w", err)
+	}
+
+	if response.StatusCode != 200 && response.StatusCode != 201 {
+		return fmt.Errorf("failed to set issue property, status: %d", response.StatusCode)
+	}
+	return nil
+}
+
+// GetIssueProperty reads an issue entity property into value.
+// It reports false when the issue has no such property.
+func (c *Client) GetIssueProperty(ticketID, propertyKey string, value interface{}) (bool, error) {
+	if c.JiraClient == nil {
+		return false, fmt.Errorf("jira client not initialized")
+	}
+
+	property, response, err := c.JiraClient.Issue.Property.Get(c.Ctx, ticketID, propertyKey)
+	if response != nil && response.Code == http.StatusNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get issue property: %w", err)
+	}
+
+	data, err := json.Marshal(property.Value)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode issue property: %w", err)
+	}
+	if err := json.Unmarshal(data, value); err != nil {
+		return false, fmt.Errorf("failed to decode issue property %s: %w", propertyKey, err)
+	}
+	return true, nil
+}
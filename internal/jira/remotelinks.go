@@ -0,0 +1,157 @@
+package jira
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// ClientJiraRemoteLink represents a link from an issue to an external resource,
+// such as a pull request, Confluence page or incident record
+type ClientJiraRemoteLink struct {
+	Title        string `json:"title"`
+	URL          string `json:"url"`
+	Summary      string `json:"summary,omitempty"`
+	Relationship string `json:"relationship,omitempty"` // e.g. "mentioned in", "Wiki Page"
+	Application  string `json:"application,omitempty"`  // Name or type of the linking application
+	Resolved     bool   `json:"resolved,omitempty"`     // The remote object is marked as done/closed
+}
+
+// ClientJiraPullRequest represents a pull request shown in the development panel
+type ClientJiraPullRequest struct {
+	ID         string `json:"id"`
+	Title      string `json:"title"`
+	URL        string `json:"url"`
+	Status     string `json:"status"` // "OPEN", "MERGED" or "DECLINED"
+	Repository string `json:"repository,omitempty"`
+}
+
+// ClientJiraDevelopmentInfo summarizes the development panel of an issue
+type ClientJiraDevelopmentInfo struct {
+	Branches         int                     `json:"branches"`
+	Commits          int                     `json:"commits"`
+	PullRequestCount int                     `json:"pullRequestCount"`
+	PullRequestState string                  `json:"pullRequestState,omitempty"` // Overall state reported by Jira
+	PullRequests     []ClientJiraPullRequest `json:"pullRequests,omitempty"`
+}
+
+// GetRemoteLinks fetches the remote links of a ticket
+func (c *Client) GetRemoteLinks(ticketID string) ([]ClientJiraRemoteLink, error) {
+	if c.JiraClient == nil {
+		return nil, fmt.Errorf("jira client not initialized")
+	}
+
+	links, response, err := c.JiraClient.Issue.Link.Remote.Gets(c.Ctx, ticketID, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get remote links: %w", err)
+	}
+
+	if response.StatusCode != 200 {
+		return nil, fmt.Errorf("failed to get remote links, status: %d", response.StatusCode)
+	}
+
+	result := make([]ClientJiraRemoteLink, 0, len(links))
+	for _, link := range links {
+		if link == nil || link.Object == nil {
+			continue
+		}
+		remote := ClientJiraRemoteLink{
+			Title:        link.Object.Title,
+			URL:          link.Object.URL,
+			Summary:      link.Object.Summary,
+			Relationship: link.Relationship,
+		}
+		if link.Application != nil {
+			remote.Application = link.Application.Name
+			if remote.Application == "" {
+				remote.Application = link.Application.Type
+			}
+		}
+		if link.Object.Status != nil {
+			remote.Resolved = link.Object.Status.Resolved
+		}
+		result = append(result, remote)
+	}
+	return result, nil
+}
+
+// GetDevelopmentInfo fetches branch, commit and pull request information from the
+// development panel. The dev-status API is keyed by the numeric issue ID, not the key.
+func (c *Client) GetDevelopmentInfo(issueID string) (*ClientJiraDevelopmentInfo, error) {
+	if c.JiraClient == nil {
+		return nil, fmt.Errorf("jira client not initialized")
+	}
+
+	type overall struct {
+		Overall struct {
+			Count int    `json:"count"`
+			State string `json:"state"`
+		} `json:"overall"`
+		ByInstanceType map[string]struct {
+			Count int    `json:"count"`
+			Name  string `json:"name"`
+		} `json:"byInstanceType"`
+	}
+	var summary struct {
+		Summary struct {
+			PullRequest overall `json:"pullrequest"`
+			Branch      overall `json:"branch"`
+			Repository  overall `json:"repository"` // Counts commits across repositories
+		} `json:"summary"`
+	}
+	endpoint := fmt.Sprintf("rest/dev-status/latest/issue/summary?issueId=%s", url.QueryEscape(issueID))
+	if err := c.getJSON(endpoint, &summary); err != nil {
+		return nil, fmt.Errorf("failed to get development summary: %w", err)
+	}
+
+	info := &ClientJiraDevelopmentInfo{
+		Branches:         summary.Summary.Branch.Overall.Count,
+		Commits:          summary.Summary.Repository.Overall.Count,
+		PullRequestCount: summary.Summary.PullRequest.Overall.Count,
+		PullRequestState: summary.Summary.PullRequest.Overall.State,
+	}
+	if info.PullRequestCount == 0 {
+		return info, nil
+	}
+
+	// Pull request details are reported per integration, e.g. "GitHub" or "bitbucket"
+	instanceTypes := make([]string, 0, len(summary.Summary.PullRequest.ByInstanceType))
+	for instanceType, instance := range summary.Summary.PullRequest.ByInstanceType {
+		if instance.Count > 0 {
+			instanceTypes = append(instanceTypes, instanceType)
+		}
+	}
+	sort.Strings(instanceTypes)
+
+	for _, instanceType := range instanceTypes {
+		var detail struct {
+			Detail []struct {
+				PullRequests []struct {
+					ID             string `json:"id"`
+					Name           string `json:"name"`
+					URL            string `json:"url"`
+					Status         string `json:"status"`
+					RepositoryName string `json:"repositoryName"`
+				} `json:"pullRequests"`
+			} `json:"detail"`
+		}
+		endpoint := fmt.Sprintf("rest/dev-status/latest/issue/detail?issueId=%s&applicationType=%s&dataType=pullrequest",
+			url.QueryEscape(issueID), url.QueryEscape(instanceType))
+		if err := c.getJSON(endpoint, &detail); err != nil {
+			return nil, fmt.Errorf("failed to get pull requests from %s: %w", instanceType, err)
+		}
+		for _, d := range detail.Detail {
+			for _, pr := range d.PullRequests {
+				info.PullRequests = append(info.PullRequests, ClientJiraPullRequest{
+					ID:         pr.ID,
+					Title:      pr.Name,
+					URL:        pr.URL,
+					Status:     strings.ToUpper(pr.Status),
+					Repository: pr.RepositoryName,
+				})
+			}
+		}
+	}
+	return info, nil
+}
@@ -0,0 +1,80 @@
+package jira
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+)
+
+// ClientJiraTransition represents a workflow transition available on an issue
+type ClientJiraTransition struct {
+	ID             string   `json:"id"`
+	Name           string   `json:"name"`
+	ToStatus       string   `json:"toStatus"`
+	RequiredFields []string `json:"requiredFields,omitempty"` // Fields the transition screen requires
+}
+
+// GetTransitions lists the transitions available on a ticket from its current status
+func (c *Client) GetTransitions(ticketID string) ([]ClientJiraTransition, error) {
+	if c.JiraClient == nil {
+		return nil, fmt.Errorf("jira client not initialized")
+	}
+
+	var result struct {
+		Transitions []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+			To   struct {
+				Name string `json:"name"`
+			} `json:"to"`
+			Fields map[string]struct {
+				Required bool `json:"required"`
+			} `json:"fields"`
+		} `json:"transitions"`
+	}
+	endpoint := fmt.Sprintf("rest/api/2/issue/%s/transitions?expand=transitions.fields", url.PathEscape(ticketID))
+	if err := c.getJSON(endpoint, &result); err != nil {
+		return nil, fmt.Errorf("failed to get transitions: %w", err)
+	}
+
+	transitions := make([]ClientJiraTransition, 0, len(result.Transitions))
+	for _, t := range result.Transitions {
+		transition := ClientJiraTransition{ID: t.ID, Name: t.Name, ToStatus: t.To.Name}
+		for field, meta := range t.Fields {
+			if meta.Required {
+				transition.RequiredFields = append(transition.RequiredFields, field)
+			}
+		}
+		sort.Strings(transition.RequiredFields)
+		transitions = append(transitions, transition)
+	}
+	return transitions, nil
+}
+
+// DoTransition moves a ticket through a workflow transition, setting the given fields
+// and optionally adding a comment with the given visibility as part of the same request
+func (c *Client) DoTransition(ticketID, transitionID string, fields map[string]interface{}, comment string,
+	visibility *ClientJiraCommentVisibility) error {
+	if c.JiraClient == nil {
+		return fmt.Errorf("jira client not initialized")
+	}
+
+	payload := map[string]interface{}{
+		"transition": map[string]interface{}{"id": transitionID},
+	}
+	if len(fields) > 0 {
+		payload["fields"] = fields
+	}
+	if comment != "" {
+		payload["update"] = map[string]interface{}{
+			"comment": []interface{}{map[string]interface{}{"add": commentPayload(comment, visibility)}},
+		}
+	}
+
+	endpoint := fmt.Sprintf("rest/api/2/issue/%s/transitions", url.PathEscape(ticketID))
+	if err := c.doJSON(http.MethodPost, endpoint, payload, nil); err != nil {
+		return fmt.Errorf("failed to transition issue: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,243 @@
+package jira
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ctreminiom/go-atlassian/v2/pkg/infra/models"
+)
+
+// mentionPattern matches Jira Cloud user mentions in wiki markup, e.g. [~accountid:5d1234567890abcdef123456]
+var mentionPattern = regexp.MustCompile(`\[~accountid:([A-Za-z0-9:_\-]+)\]`)
+
+// ClientJiraUser represents a Jira user in the client
+type ClientJiraUser struct {
+	AccountID    string `json:"accountId"`
+	DisplayName  string `json:"displayName"`
+	EmailAddress string `json:"emailAddress,omitempty"`
+	Active       bool   `json:"active"`
+}
+
+// GetUser fetches a Jira user by account ID
+func (c *Client) GetUser(accountID string) (*ClientJiraUser, error) {
+	if c.JiraClient == nil {
+		return nil, fmt.Errorf("jira client not initialized")
+	}
+
+	user, response, err := c.JiraClient.User.Get(c.Ctx, accountID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if response.StatusCode != 200 {
+		return nil, fmt.Errorf("failed to get user, status: %d", response.StatusCode)
+	}
+
+	return &ClientJiraUser{
+		AccountID:    user.AccountID,
+		DisplayName:  user.DisplayName,
+		EmailAddress: user.EmailAddress,
+		Active:       user.Active,
+	}, nil
+}
+
+// GetMyself fetches the user the client is authenticated as
+func (c *Client) GetMyself() (*ClientJiraUser, error) {
+	if c.JiraClient == nil {
+		return nil, fmt.Errorf("jira client not initialized")
+	}
+
+	user, response, err := c.JiraClient.MySelf.Details(c.Ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current user: %w", err)
+	}
+
+	if response.StatusCode != 200 {
+		return nil, fmt.Errorf("failed to get current user, status: %d", response.StatusCode)
+	}
+
+	return &ClientJiraUser{
+		AccountID:    user.AccountID,
+		DisplayName:  user.DisplayName,
+		EmailAddress: user.EmailAddress,
+		Active:       user.Active,
+	}, nil
+}
+
+// SearchUsers finds Jira users whose name or email matches the query
+func (c *Client) SearchUsers(query string) ([]ClientJiraUser, error) {
+	if c.JiraClient == nil {
+		return nil, fmt.Errorf("jira client not initialized")
+	}
+
+	users, response, err := c.JiraClient.User.Search.Do(c.Ctx, "", query, 0, 10)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search users: %w", err)
+	}
+
+	if response.StatusCode != 200 {
+		return nil, fmt.Errorf("failed to search users, status: %d", response.StatusCode)
+	}
+
+	result := make([]ClientJiraUser, 0, len(users))
+	for _, user := range users {
+		if user == nil {
+			continue
+		}
+		result = append(result, ClientJiraUser{
+			AccountID:    user.AccountID,
+			DisplayName:  user.DisplayName,
+			EmailAddress: user.EmailAddress,
+			Active:       user.Active,
+		})
+	}
+	return result, nil
+}
+
+// cachedUser is a resolver cache entry
+type cachedUser struct {
+	accountID   string
+	displayName string
+	expires     time.Time
+}
+
+// UserResolver converts between Jira Cloud account IDs and display names.
+// Lookups are cached for the configured TTL, including misses, so a mention of an
+// unknown account does not trigger an API call for every ticket. Other errors are not
+// cached, so a transient failure does not hide a user for the whole TTL.
+type UserResolver struct {
+	client *Client
+	ttl    time.Duration
+
+	mu          sync.Mutex
+	byAccountID map[string]cachedUser
+	byName      map[string]cachedUser // keyed by lower-cased display name
+}
+
+// NewUserResolver creates a user resolver backed by the given Jira client
+func NewUserResolver(client *Client, ttl time.Duration) *UserResolver {
+	return &UserResolver{
+		client:      client,
+		ttl:         ttl,
+		byAccountID: make(map[string]cachedUser),
+		byName:      make(map[string]cachedUser),
+	}
+}
+
+// Remember seeds the cache with a known account, e.g. the assignee of a fetched ticket
+func (r *UserResolver) Remember(accountID, displayName string) {
+	if accountID == "" || displayName == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.store(accountID, displayName)
+}
+
+// DisplayName returns the display name for an account ID
+func (r *UserResolver) DisplayName(accountID string) (string, error) {
+	r.mu.Lock()
+	entry, ok := r.byAccountID[accountID]
+	r.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		if entry.displayName == "" {
+			return "", fmt.Errorf("user %s not found", accountID)
+		}
+		return entry.displayName, nil
+	}
+
+	user, err := r.client.GetUser(accountID)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			r.mu.Lock()
+			r.byAccountID[accountID] = cachedUser{accountID: accountID, expires: time.Now().Add(r.ttl)}
+			r.mu.Unlock()
+		}
+		return "", err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.store(user.AccountID, user.DisplayName)
+	return user.DisplayName, nil
+}
+
+// AccountID returns the account ID of the user with the given display name.
+// Only an unambiguous, case-insensitive exact match is accepted.
+func (r *UserResolver) AccountID(name string) (string, error) {
+	name = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(name), "@"))
+	if name == "" {
+		return "", fmt.Errorf("empty user name")
+	}
+	key := strings.ToLower(name)
+
+	r.mu.Lock()
+	entry, ok := r.byName[key]
+	r.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		if entry.accountID == "" {
+			return "", fmt.Errorf("no unique user named %q", name)
+		}
+		return entry.accountID, nil
+	}
+
+	users, err := r.client.SearchUsers(name)
+	if err != nil {
+		return "", err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var match *ClientJiraUser
+	for i := range users {
+		if !strings.EqualFold(users[i].DisplayName, name) {
+			continue
+		}
+		if match != nil {
+			match = nil
+			break
+		}
+		match = &users[i]
+	}
+	if match == nil {
+		r.byName[key] = cachedUser{displayName: name, expires: time.Now().Add(r.ttl)}
+		return "", fmt.Errorf("no unique user named %q", name)
+	}
+	r.store(match.AccountID, match.DisplayName)
+	return match.AccountID, nil
+}
+
+// ResolveMentions replaces [~accountid:...] mentions with "@Display Name".
+// Mentions that cannot be resolved are left untouched.
+func (r *UserResolver) ResolveMentions(text string) string {
+	if !strings.Contains(text, "[~accountid:") {
+		return text
+	}
+	return mentionPattern.ReplaceAllStringFunc(text, func(mention string) string {
+		accountID := mentionPattern.FindStringSubmatch(mention)[1]
+		name, err := r.DisplayName(accountID)
+		if err != nil {
+			return mention
+		}
+		return "@" + name
+	})
+}
+
+// Mention returns a Jira mention for the named user, or the name unchanged when it cannot be resolved
+func (r *UserResolver) Mention(name string) string {
+	accountID, err := r.AccountID(name)
+	if err != nil {
+		return name
+	}
+	return fmt.Sprintf("[~accountid:%s]", accountID)
+}
+
+// store caches an account in both directions; the caller must hold r.mu
+func (r *UserResolver) store(accountID, displayName string) {
+	entry := cachedUser{accountID: accountID, displayName: displayName, expires: time.Now().Add(r.ttl)}
+	r.byAccountID[accountID] = entry
+	r.byName[strings.ToLower(displayName)] = entry
+}
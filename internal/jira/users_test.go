@@ -0,0 +1,47 @@
+package jira
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tuannvm/jira-a2a/internal/config"
+)
+
+func TestUserResolverCachesOnlyMisses(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.URL.Query().Get("accountId") == "deleted" {
+			http.Error(w, `{"errorMessages":["User does not exist"]}`, http.StatusNotFound)
+			return
+		}
+		http.Error(w, `{"errorMessages":["Internal server error"]}`, http.StatusInternalServerError)
+	}))
+	t.Cleanup(server.Close)
+	resolver := NewUserResolver(NewClient(&config.Config{JiraBaseURL: server.URL}), time.Minute)
+
+	tests := []struct {
+		name      string
+		accountID string
+		wantCalls int32 // Requests made by two lookups
+	}{
+		{name: "unknown account", accountID: "deleted", wantCalls: 1},
+		{name: "server error", accountID: "5b10ac8d82e05b22cc7d4ef5", wantCalls: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls.Store(0)
+			for i := 0; i < 2; i++ {
+				if _, err := resolver.DisplayName(tt.accountID); err == nil {
+					t.Fatal("DisplayName succeeded")
+				}
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("got %d requests, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
@@ -31,6 +31,7 @@ type JiraUser struct {
 	Self         string            `json:"self"`
 	Name         string            `json:"name"`
 	Key          string            `json:"key"`
+	AccountID    string            `json:"accountId"`
 	EmailAddress string            `json:"emailAddress"`
 	AvatarURLs   map[string]string `json:"avatarUrls"`
 	DisplayName  string            `json:"displayName"`
@@ -73,10 +74,16 @@ func TransformJiraWebhook(payload []byte) (*WebhookRequest, error) {
 
 	// Create the WebhookRequest
 	webhookReq := &WebhookRequest{
-		TicketID:  jiraWebhook.Issue.Key,
-		Event:     getEventTypeFromWebhookEvent(jiraWebhook.WebhookEvent),
-		UserName:  jiraWebhook.User.Name,
-		UserEmail: jiraWebhook.User.EmailAddress,
+		TicketID:      jiraWebhook.Issue.Key,
+		Event:         getEventTypeFromWebhookEvent(jiraWebhook.WebhookEvent),
+		UserName:      jiraWebhook.User.Name,
+		UserEmail:     jiraWebhook.User.EmailAddress,
+		UserAccountID: jiraWebhook.User.AccountID,
+	}
+
+	// Comment events are triggered by the comment author, who is not always sent as the user
+	if jiraWebhook.Comment != nil && jiraWebhook.Comment.Author.AccountID != "" {
+		webhookReq.UserAccountID = jiraWebhook.Comment.Author.AccountID
 	}
 
 	// Extract project key from ticket key (e.g., "JRA" from "JRA-20002")
@@ -99,6 +106,11 @@ func TransformJiraWebhook(payload []byte) (*WebhookRequest, error) {
 		webhookReq.Changes = make(map[string]string)
 		for _, item := range jiraWebhook.Changelog.Items {
 			webhookReq.Changes[item.Field] = item.ToString
+			webhookReq.FieldChanges = append(webhookReq.FieldChanges, FieldChange{
+				Field: item.Field,
+				From:  item.FromString,
+				To:    item.ToString,
+			})
 		}
 	}
 
@@ -147,13 +159,22 @@ func getEventTypeFromWebhookEvent(webhookEvent string) string {
 // WebhookRequest represents the application's internal webhook request format
 // This is the format used throughout the application for webhook processing
 type WebhookRequest struct {
-	TicketID     string            `json:"ticketId"`
-	Event        string            `json:"event"`                  // "created", "updated", "commented", etc.
-	UserName     string            `json:"userName"`               // The user who triggered the event
-	UserEmail    string            `json:"userEmail"`              // The email of the user who triggered the event
-	ProjectKey   string            `json:"projectKey"`             // The key of the project containing the issue
-	Changes      map[string]string `json:"changes"`                // Map of fields that were changed and their new values
-	WebhookName  string            `json:"webhookName"`            // Name of the webhook that was triggered
-	Timestamp    string            `json:"timestamp"`              // When the webhook was triggered
-	CustomFields map[string]string `json:"customFields,omitempty"` // Any custom fields from Jira
-}
\ No newline at end of file
+	TicketID      string            `json:"ticketId"`
+	Event         string            `json:"event"`                   // "created", "updated", "commented", etc.
+	UserName      string            `json:"userName"`                // The user who triggered the event
+	UserEmail     string            `json:"userEmail"`               // The email of the user who triggered the event
+	UserAccountID string            `json:"userAccountId,omitempty"` // The account ID of the user who triggered the event
+	ProjectKey    string            `json:"projectKey"`              // The key of the project containing the issue
+	Changes       map[string]string `json:"changes"`                 // Map of fields that were changed and their new values
+	FieldChanges  []FieldChange     `json:"fieldChanges,omitempty"`  // Changed fields with their old and new values
+	WebhookName   string            `json:"webhookName"`             // Name of the webhook that was triggered
+	Timestamp     string            `json:"timestamp"`               // When the webhook was triggered
+	CustomFields  map[string]string `json:"customFields,omitempty"`  // Any custom fields from Jira
+}
+
+// FieldChange is a field changed by an update event, with its old and new values as displayed in Jira.
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from,omitempty"`
+	To    string `json:"to,omitempty"`
+}
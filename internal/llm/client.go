@@ -2,61 +2,139 @@ package llm
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
+	"regexp"
 	"time"
 
 	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/anthropic"
 	"github.com/tmc/langchaingo/llms/openai"
 	log "github.com/tuannvm/jira-a2a/internal/logging"
 	"github.com/tuannvm/jira-a2a/internal/config"
 )
 
+// ErrStructuredOutputUnsupported is returned by CompleteStructured when the provider
+// cannot produce structured output, so callers can fall back to Complete.
+var ErrStructuredOutputUnsupported = errors.New("structured output not supported")
+
+// rejectedRequestPattern matches the errors langchaingo returns when the provider rejects
+// a request as invalid, e.g. a model or deployment that does not accept tools
+var rejectedRequestPattern = regexp.MustCompile(`status code: (400|422)\b`)
+
 // LLMClient defines the interface for interacting with LLM services
 type LLMClient interface {
 	// Complete sends a prompt to the LLM and returns the completion
 	Complete(ctx context.Context, prompt string) (string, error)
+	// CompleteStream is like Complete but also passes the completion to onChunk
+	// piece by piece as it is generated
+	CompleteStream(ctx context.Context, prompt string, onChunk func(chunk string)) (string, error)
+	// CompleteStructured sends a prompt to the LLM and returns its answer as JSON
+	// matching the given JSON Schema, using the provider's tool calling
+	CompleteStructured(ctx context.Context, prompt, name string, schema json.RawMessage) (json.RawMessage, error)
 }
 
 // Client implements the LLMClient interface using langchain-go
 type Client struct {
-	llm       llms.Model
-	maxTokens int
-	timeout   time.Duration
+	llm        llms.Model
+	provider   string
+	model      string
+	apiKey     string // For requests langchain-go does not support, such as log probabilities
+	baseURL    string
+	maxTokens  int
+	timeout    time.Duration
+	structured bool // Whether to use tool calling for structured output
 }
 
-// NewClient creates a new LLM client based on the provided configuration
+// NewClient creates a new LLM client based on the provided configuration. When an LLM
+// routing file is configured, the client is a Router over all configured providers.
 func NewClient(cfg *config.Config) (LLMClient, error) {
+	if cfg.LLMRoutingFile != "" {
+		routing, err := config.LoadLLMRouting(cfg.LLMRoutingFile)
+		if err != nil {
+			return nil, err
+		}
+		return NewRouter(cfg, routing)
+	}
+	return newProviderClient(cfg, defaultProvider(cfg))
+}
+
+// defaultProvider returns the provider configured with the LLM_* settings.
+func defaultProvider(cfg *config.Config) config.LLMProvider {
+	return config.LLMProvider{
+		Name:       defaultProviderName,
+		Provider:   cfg.LLMProvider,
+		Model:      cfg.LLMModel,
+		ServiceURL: cfg.LLMServiceURL,
+	}
+}
+
+// newProviderClient creates a client for one provider, taking unset settings from the configuration.
+func newProviderClient(cfg *config.Config, p config.LLMProvider) (*Client, error) {
 	var llmModel llms.Model
 	var err error
 
+	apiKey := cfg.LLMAPIKey
+	if p.APIKeyEnv != "" {
+		apiKey = os.Getenv(p.APIKeyEnv)
+	}
+	serviceURL := p.ServiceURL
+
 	// Select LLM provider based on configuration
-	switch cfg.LLMProvider {
+	switch p.Provider {
 	case "openai":
 		// Initialize OpenAI
-		llmModel, err = openai.New(
-			openai.WithToken(cfg.LLMAPIKey),
-			openai.WithModel(cfg.LLMModel),
-		)
+		opts := []openai.Option{
+			openai.WithToken(apiKey),
+			openai.WithModel(p.Model),
+		}
+		if serviceURL != "" {
+			opts = append(opts, openai.WithBaseURL(serviceURL))
+		}
+		llmModel, err = openai.New(opts...)
 	case "azure":
 		// Initialize Azure OpenAI
 		llmModel, err = openai.New(
-			openai.WithToken(cfg.LLMAPIKey),
-			openai.WithModel(cfg.LLMModel),
-			openai.WithBaseURL(cfg.LLMServiceURL),
+			openai.WithToken(apiKey),
+			openai.WithModel(p.Model),
+			openai.WithBaseURL(serviceURL),
 		)
+	case "anthropic":
+		// Initialize Anthropic
+		opts := []anthropic.Option{
+			anthropic.WithToken(apiKey),
+			anthropic.WithModel(p.Model),
+		}
+		if serviceURL != "" {
+			opts = append(opts, anthropic.WithBaseURL(serviceURL))
+		}
+		llmModel, err = anthropic.New(opts...)
 	default:
-		return nil, fmt.Errorf("unsupported LLM provider: %s", cfg.LLMProvider)
+		return nil, fmt.Errorf("unsupported LLM provider: %s", p.Provider)
 	}
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize LLM: %w", err)
+		return nil, fmt.Errorf("failed to initialize LLM %s: %w", p.Name, err)
 	}
 
+	maxTokens, timeout := cfg.LLMMaxTokens, cfg.LLMTimeout
+	if p.MaxTokens > 0 {
+		maxTokens = p.MaxTokens
+	}
+	if p.Timeout > 0 {
+		timeout = p.Timeout
+	}
 	return &Client{
-		llm:       llmModel,
-		maxTokens: cfg.LLMMaxTokens,
-		timeout:   time.Duration(cfg.LLMTimeout) * time.Second,
+		llm:        llmModel,
+		provider:   p.Provider,
+		model:      p.Model,
+		apiKey:     apiKey,
+		baseURL:    serviceURL,
+		maxTokens:  maxTokens,
+		timeout:    time.Duration(timeout) * time.Second,
+		structured: cfg.LLMStructuredOutput,
 	}, nil
 }
 
@@ -74,17 +152,111 @@ func (c *Client) Complete(ctx context.Context, prompt string) (string, error) {
 	defer cancel()
 
 	// Call the LLM with the non-deprecated method
-	completion, err := llms.GenerateFromSinglePrompt(ctx, c.llm, prompt, llms.WithMaxTokens(c.maxTokens))
+	completion, err := llms.GenerateFromSinglePrompt(ctx, c.llm, prompt, llms.WithMaxTokens(c.maxTokens), samplingOption(ctx))
 	if err != nil {
 		return "", fmt.Errorf("LLM generation failed: %w", err)
 	}
 
 	// Log the response for debugging
 	log.Infof("Received response from LLM: %s", truncateForLogging(completion))
+	recordModel(ctx, c.model)
 
 	return completion, nil
 }
 
+// CompleteStream sends a prompt to the LLM, streaming the completion to onChunk as tokens
+// arrive, and returns the full completion
+func (c *Client) CompleteStream(ctx context.Context, prompt string, onChunk func(chunk string)) (string, error) {
+	if c.llm == nil {
+		return "", errors.New("LLM client not initialized")
+	}
+
+	log.Infof("Sending streaming prompt to LLM: %s", truncateForLogging(prompt))
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	completion, err := llms.GenerateFromSinglePrompt(ctx, c.llm, prompt,
+		llms.WithMaxTokens(c.maxTokens),
+		samplingOption(ctx),
+		llms.WithStreamingFunc(func(_ context.Context, chunk []byte) error {
+			onChunk(string(chunk))
+			return nil
+		}),
+	)
+	if err != nil {
+		return "", fmt.Errorf("LLM generation failed: %w", err)
+	}
+
+	log.Infof("Received streamed response from LLM: %s", truncateForLogging(completion))
+	recordModel(ctx, c.model)
+
+	return completion, nil
+}
+
+// CompleteStructured asks the LLM to call a single tool whose parameters are the given
+// JSON Schema and returns the tool call arguments. OpenAI and Azure are forced to call
+// the tool; Anthropic is instructed to. ErrStructuredOutputUnsupported is returned when
+// structured output is disabled, the provider rejects the request as invalid or the
+// model answered with text instead.
+func (c *Client) CompleteStructured(ctx context.Context, prompt, name string, schema json.RawMessage) (json.RawMessage, error) {
+	if c.llm == nil {
+		return nil, errors.New("LLM client not initialized")
+	}
+	if !c.structured {
+		return nil, ErrStructuredOutputUnsupported
+	}
+
+	log.Infof("Sending structured prompt to LLM (tool %s): %s", name, truncateForLogging(prompt))
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	tool := llms.Tool{
+		Type: "function",
+		Function: &llms.FunctionDefinition{
+			Name:        name,
+			Description: "Record the result. Always call this tool with the complete answer.",
+			Parameters:  schema,
+		},
+	}
+	messages := []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, prompt+"\n\nCall the "+name+" tool with your answer."),
+	}
+	resp, err := c.llm.GenerateContent(ctx, messages,
+		llms.WithMaxTokens(c.maxTokens),
+		samplingOption(ctx),
+		llms.WithTools([]llms.Tool{tool}),
+		llms.WithToolChoice(llms.ToolChoice{Type: "function", Function: &llms.FunctionReference{Name: name}}),
+	)
+	if err != nil && ctx.Err() == nil && rejectedRequestPattern.MatchString(err.Error()) {
+		// The provider rejected the tool or tool choice; a plain completion may still work
+		log.Warnf("LLM %s rejected the structured request: %v", c.model, err)
+		return nil, fmt.Errorf("%w: %w", ErrStructuredOutputUnsupported, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("LLM generation failed: %w", err)
+	}
+
+	for _, choice := range resp.Choices {
+		if choice == nil {
+			continue
+		}
+		for _, call := range choice.ToolCalls {
+			if call.FunctionCall == nil || call.FunctionCall.Name != name {
+				continue
+			}
+			log.Infof("Received structured response from LLM: %s", truncateForLogging(call.FunctionCall.Arguments))
+			if !json.Valid([]byte(call.FunctionCall.Arguments)) {
+				return nil, fmt.Errorf("LLM returned invalid JSON arguments for tool %s", name)
+			}
+			recordModel(ctx, c.model)
+			return json.RawMessage(call.FunctionCall.Arguments), nil
+		}
+	}
+	return nil, fmt.Errorf("%w: model did not call tool %s", ErrStructuredOutputUnsupported, name)
+}
+
 // truncateForLogging truncates a string to a reasonable length for logging
 func truncateForLogging(s string) string {
 	const maxLength = 500
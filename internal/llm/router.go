@@ -0,0 +1,339 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tuannvm/jira-a2a/internal/config"
+	log "github.com/tuannvm/jira-a2a/internal/logging"
+)
+
+// defaultProviderName is the name of the provider configured with the LLM_* settings.
+const defaultProviderName = "default"
+
+// Circuit breaker defaults.
+const (
+	defaultFailureThreshold = 3
+	defaultCooldown         = 60 * time.Second
+)
+
+// errStreamInterrupted marks a streaming completion that failed after text was passed on.
+// It is not retried with another provider, as the text already streamed cannot be taken back.
+var errStreamInterrupted = errors.New("stream interrupted")
+
+// statusCodePattern matches the HTTP status in the errors langchaingo returns for failed
+// requests, e.g. "API returned unexpected status code: 400: ..."
+var statusCodePattern = regexp.MustCompile(`status code: (\d{3})\b`)
+
+// Ticket describes the ticket completions are made for, so a Router can pick the model.
+type Ticket struct {
+	Project   string
+	IssueType string
+	Priority  string
+	Comments  int // Number of comments
+	Tokens    int // Size of the description and comments
+}
+
+type requestKey struct{}
+
+// request is attached to a context by WithTicket and records the models that answered.
+type request struct {
+	ticket Ticket
+	mu     sync.Mutex
+	models []string
+}
+
+// WithTicket returns a context for the completions made for a ticket. A Router picks its
+// providers by the ticket, and the models that answer are recorded for ModelsUsed.
+func WithTicket(ctx context.Context, ticket Ticket) context.Context {
+	return context.WithValue(ctx, requestKey{}, &request{ticket: ticket})
+}
+
+// ModelsUsed returns the models that answered completions made with a context from
+// WithTicket, in the order they were first used.
+func ModelsUsed(ctx context.Context) []string {
+	req, ok := ctx.Value(requestKey{}).(*request)
+	if !ok {
+		return nil
+	}
+	req.mu.Lock()
+	defer req.mu.Unlock()
+	return slices.Clone(req.models)
+}
+
+func recordModel(ctx context.Context, model string) {
+	req, ok := ctx.Value(requestKey{}).(*request)
+	if !ok {
+		return
+	}
+	req.mu.Lock()
+	defer req.mu.Unlock()
+	if !slices.Contains(req.models, model) {
+		req.models = append(req.models, model)
+	}
+}
+
+// Router implements the LLMClient interface over several providers. A completion is sent
+// to the providers of the first route matching the ticket and then to the fallback
+// providers, in order, until one answers. Providers that keep failing are skipped for a
+// cooldown period (circuit breaking) and then tried again with a single request. Requests
+// a provider rejects as invalid (4xx other than 429) are not sent to the other providers
+// and do not count as failures.
+type Router struct {
+	routes   []config.LLMRoute
+	chains   [][]*provider // Providers tried for each route, followed by the fallback ones
+	fallback []*provider
+}
+
+// provider is an LLM client with its health.
+type provider struct {
+	name      string
+	model     string
+	client    LLMClient
+	threshold int
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	failures  int       // Consecutive failures
+	openUntil time.Time // Skipped until then once failures reach the threshold
+}
+
+// NewRouter creates a Router over the default provider and those of the routing configuration.
+func NewRouter(cfg *config.Config, routing *config.LLMRouting) (*Router, error) {
+	threshold := routing.FailureThreshold
+	if threshold <= 0 {
+		threshold = defaultFailureThreshold
+	}
+	cooldown := time.Duration(routing.Cooldown) * time.Second
+	if cooldown <= 0 {
+		cooldown = defaultCooldown
+	}
+
+	providers := make(map[string]*provider)
+	names := []string{defaultProviderName}
+	for _, p := range append([]config.LLMProvider{defaultProvider(cfg)}, routing.Providers...) {
+		client, err := newProviderClient(cfg, p)
+		if err != nil {
+			return nil, err
+		}
+		providers[p.Name] = &provider{name: p.Name, model: p.Model, client: client, threshold: threshold, cooldown: cooldown}
+		if p.Name != defaultProviderName {
+			names = append(names, p.Name)
+		}
+	}
+	lookup := func(names []string) []*provider {
+		list := make([]*provider, 0, len(names))
+		for _, name := range names {
+			list = append(list, providers[name])
+		}
+		return list
+	}
+
+	r := &Router{routes: routing.Routes}
+	if len(routing.Fallback) > 0 {
+		names = routing.Fallback
+	}
+	r.fallback = lookup(names)
+	for _, route := range routing.Routes {
+		chain := lookup(route.Providers)
+		for _, p := range r.fallback {
+			if !slices.Contains(chain, p) {
+				chain = append(chain, p)
+			}
+		}
+		r.chains = append(r.chains, chain)
+	}
+	log.Infof("LLM routing: %d providers, %d routes, fallback %s", len(providers), len(r.routes), strings.Join(names, " → "))
+	return r, nil
+}
+
+// Models returns the models of all providers the router may use.
+func (r *Router) Models() []string {
+	var models []string
+	add := func(chain []*provider) {
+		for _, p := range chain {
+			if !slices.Contains(models, p.model) {
+				models = append(models, p.model)
+			}
+		}
+	}
+	add(r.fallback)
+	for _, chain := range r.chains {
+		add(chain)
+	}
+	return models
+}
+
+// ModelFor returns the model a ticket's completions are sent to first.
+func (r *Router) ModelFor(ticket Ticket) string {
+	_, chain := r.chain(ticket)
+	return chain[0].model
+}
+
+// chain returns the name of the route matching a ticket and the providers to try for it.
+func (r *Router) chain(ticket Ticket) (string, []*provider) {
+	for i, route := range r.routes {
+		if routeMatches(route, ticket) {
+			return route.Name, r.chains[i]
+		}
+	}
+	return "fallback", r.fallback
+}
+
+// routeMatches reports whether a ticket meets every condition of a route.
+func routeMatches(route config.LLMRoute, ticket Ticket) bool {
+	return matchesAny(route.Projects, ticket.Project) &&
+		matchesAny(route.IssueTypes, ticket.IssueType) &&
+		matchesAny(route.Priorities, ticket.Priority) &&
+		(route.MinTokens == 0 || ticket.Tokens >= route.MinTokens) &&
+		(route.MaxTokens == 0 || ticket.Tokens <= route.MaxTokens) &&
+		(route.MinComments == 0 || ticket.Comments >= route.MinComments)
+}
+
+// matchesAny reports whether value equals one of values, case-insensitively. No values match anything.
+func matchesAny(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Complete sends the prompt to the first available provider that answers
+func (r *Router) Complete(ctx context.Context, prompt string) (string, error) {
+	var completion string
+	err := r.do(ctx, func(c LLMClient) error {
+		var err error
+		completion, err = c.Complete(ctx, prompt)
+		return err
+	})
+	return completion, err
+}
+
+// CompleteStream streams the completion of the first available provider that answers.
+// A provider failing after it streamed part of its completion is not replaced by the next one.
+func (r *Router) CompleteStream(ctx context.Context, prompt string, onChunk func(chunk string)) (string, error) {
+	var completion string
+	err := r.do(ctx, func(c LLMClient) error {
+		streamed := false
+		var err error
+		completion, err = c.CompleteStream(ctx, prompt, func(chunk string) {
+			streamed = true
+			onChunk(chunk)
+		})
+		if err != nil && streamed {
+			return fmt.Errorf("%w: %w", errStreamInterrupted, err)
+		}
+		return err
+	})
+	return completion, err
+}
+
+// CompleteStructured returns the structured answer of the first available provider that
+// answers. ErrStructuredOutputUnsupported is returned as is, without trying other providers,
+// so the caller falls back to Complete.
+func (r *Router) CompleteStructured(ctx context.Context, prompt, name string, schema json.RawMessage) (json.RawMessage, error) {
+	var data json.RawMessage
+	err := r.do(ctx, func(c LLMClient) error {
+		var err error
+		data, err = c.CompleteStructured(ctx, prompt, name, schema)
+		return err
+	})
+	return data, err
+}
+
+// do calls the providers for the context's ticket in order until one succeeds.
+func (r *Router) do(ctx context.Context, call func(c LLMClient) error) error {
+	var ticket Ticket
+	if req, ok := ctx.Value(requestKey{}).(*request); ok {
+		ticket = req.ticket
+	}
+	route, chain := r.chain(ticket)
+	log.Debugf("Routing completion for a %s %s ticket via %s", ticket.Priority, ticket.IssueType, route)
+
+	var errs []error
+	for _, p := range chain {
+		if !p.available(time.Now()) {
+			continue
+		}
+		err := call(p.client)
+		if errors.Is(err, ErrStructuredOutputUnsupported) || errors.Is(err, ErrLogProbsUnsupported) || errors.Is(err, errLogProbsFailed) {
+			return err
+		}
+		if err == nil {
+			p.succeeded()
+			return nil
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+		if !providerFailure(err) {
+			// The request itself was refused; other providers would refuse it as well
+			return fmt.Errorf("%s: %w", p.name, err)
+		}
+		p.failed(err)
+		errs = append(errs, fmt.Errorf("%s: %w", p.name, err))
+		if errors.Is(err, errStreamInterrupted) {
+			return err
+		}
+		log.Warnf("LLM provider %s failed, trying the next one: %v", p.name, err)
+	}
+	if len(errs) == 0 {
+		return errors.New("no LLM provider available: all are cooling down after failures")
+	}
+	return fmt.Errorf("all LLM providers failed: %w", errors.Join(errs...))
+}
+
+// providerFailure reports whether an error shows the provider is unhealthy: transport
+// errors, rate limiting (429) and server errors (5xx). Other 4xx errors reject the request.
+func providerFailure(err error) bool {
+	m := statusCodePattern.FindStringSubmatch(err.Error())
+	return m == nil || m[1] == "429" || m[1][0] != '4'
+}
+
+// available reports whether the provider may be called. Once the cooldown of an open
+// circuit has passed, one request is let through and the circuit stays open meanwhile.
+func (p *provider) available(now time.Time) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.failures < p.threshold {
+		return true
+	}
+	if now.Before(p.openUntil) {
+		return false
+	}
+	p.openUntil = now.Add(p.cooldown)
+	return true
+}
+
+func (p *provider) succeeded() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.failures >= p.threshold {
+		log.Infof("LLM provider %s recovered", p.name)
+	}
+	p.failures = 0
+}
+
+func (p *provider) failed(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.failures++
+	if p.failures >= p.threshold {
+		if p.failures == p.threshold {
+			log.Warnf("LLM provider %s failed %d times in a row, skipping it for %s: %v", p.name, p.failures, p.cooldown, err)
+		}
+		p.openUntil = time.Now().Add(p.cooldown)
+	}
+}
@@ -0,0 +1,125 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeClient is an LLM client answering with its model name or failing with err
+type fakeClient struct {
+	model string
+	err   error
+	calls int
+}
+
+func (c *fakeClient) Complete(ctx context.Context, prompt string) (string, error) {
+	c.calls++
+	if c.err != nil {
+		return "", c.err
+	}
+	return c.model, nil
+}
+
+func (c *fakeClient) CompleteStream(ctx context.Context, prompt string, onChunk func(chunk string)) (string, error) {
+	return c.Complete(ctx, prompt)
+}
+
+func (c *fakeClient) CompleteStructured(ctx context.Context, prompt, name string, schema json.RawMessage) (json.RawMessage, error) {
+	c.calls++
+	return nil, ErrStructuredOutputUnsupported
+}
+
+// newTestRouter returns a router falling back through the given clients in order
+func newTestRouter(clients ...*fakeClient) *Router {
+	r := &Router{}
+	for _, c := range clients {
+		r.fallback = append(r.fallback, &provider{name: c.model, model: c.model, client: c, threshold: 2, cooldown: time.Minute})
+	}
+	return r
+}
+
+func TestRouterFallback(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		want      string
+		wantErr   bool
+		wantCalls int // Calls to the second provider
+	}{
+		{name: "transport error", err: errors.New("dial tcp: connection refused"), want: "backup", wantCalls: 1},
+		{name: "rate limited", err: errors.New("API returned unexpected status code: 429: slow down"), want: "backup", wantCalls: 1},
+		{name: "server error", err: errors.New("API returned unexpected status code: 503: overloaded"), want: "backup", wantCalls: 1},
+		{name: "invalid request", err: errors.New("API returned unexpected status code: 400: bad prompt"), wantErr: true},
+		{name: "unauthorized", err: errors.New("API returned unexpected status code: 401: invalid key"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary, backup := &fakeClient{model: "primary", err: tt.err}, &fakeClient{model: "backup"}
+			r := newTestRouter(primary, backup)
+
+			got, err := r.Complete(WithTicket(context.Background(), Ticket{}), "prompt")
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("Complete = %q, %v; want %q", got, err, tt.want)
+			}
+			if backup.calls != tt.wantCalls {
+				t.Errorf("backup provider called %d times, want %d", backup.calls, tt.wantCalls)
+			}
+			if failures := r.fallback[0].failures; failures != tt.wantCalls {
+				t.Errorf("primary provider failures = %d, want %d", failures, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRouterCircuitBreaker(t *testing.T) {
+	primary, backup := &fakeClient{model: "primary", err: errors.New("timeout")}, &fakeClient{model: "backup"}
+	r := newTestRouter(primary, backup)
+	ctx := context.Background()
+
+	// Two failures open the circuit, so the third completion skips the primary provider
+	for i := 0; i < 3; i++ {
+		if _, err := r.Complete(ctx, "prompt"); err != nil {
+			t.Fatalf("Complete %d: %v", i, err)
+		}
+	}
+	if primary.calls != 2 {
+		t.Errorf("primary provider called %d times, want 2 before its circuit opened", primary.calls)
+	}
+
+	// After the cooldown a single request is let through, and a success closes the circuit
+	r.fallback[0].openUntil = time.Now().Add(-time.Second)
+	primary.err = nil
+	if got, err := r.Complete(ctx, "prompt"); err != nil || got != "primary" {
+		t.Errorf("Complete after cooldown = %q, %v; want the primary provider", got, err)
+	}
+	if r.fallback[0].failures != 0 {
+		t.Errorf("failures = %d after a success, want 0", r.fallback[0].failures)
+	}
+}
+
+func TestRouterAllFailing(t *testing.T) {
+	r := newTestRouter(&fakeClient{model: "primary", err: errors.New("timeout")})
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, err := r.Complete(ctx, "prompt"); err == nil {
+			t.Fatal("Complete succeeded with a failing provider")
+		}
+	}
+	if _, err := r.Complete(ctx, "prompt"); err == nil || err.Error() != "no LLM provider available: all are cooling down after failures" {
+		t.Errorf("Complete with an open circuit = %v", err)
+	}
+}
+
+func TestRouterStructuredOutputUnsupported(t *testing.T) {
+	primary, backup := &fakeClient{model: "primary"}, &fakeClient{model: "backup"}
+	r := newTestRouter(primary, backup)
+	if _, err := r.CompleteStructured(context.Background(), "prompt", "tool", nil); !errors.Is(err, ErrStructuredOutputUnsupported) {
+		t.Errorf("CompleteStructured = %v, want ErrStructuredOutputUnsupported", err)
+	}
+	if backup.calls != 0 || r.fallback[0].failures != 0 {
+		t.Errorf("unsupported structured output fell through (backup calls %d) or counted as a failure", backup.calls)
+	}
+}
@@ -0,0 +1,200 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+	log "github.com/tuannvm/jira-a2a/internal/logging"
+)
+
+// defaultOpenAIURL is the OpenAI API base URL used when no service URL is configured.
+const defaultOpenAIURL = "https://api.openai.com/v1"
+
+// azureAPIVersion is the Azure OpenAI API version used to request log probabilities,
+// the first GA version supporting both logprobs and max_completion_tokens.
+const azureAPIVersion = "2024-10-21"
+
+// ErrLogProbsUnsupported is returned by CompleteWithLogProbs when the provider cannot
+// return token log probabilities.
+var ErrLogProbsUnsupported = errors.New("token log probabilities not supported")
+
+// errLogProbsFailed marks a failed log probability request. Such requests are optional
+// extras, so the router does not hold their failure against the provider.
+var errLogProbsFailed = errors.New("token log probability request failed")
+
+// TokenLogProb is a generated token with its log probability.
+type TokenLogProb struct {
+	Token   string
+	LogProb float64
+}
+
+// LogProbCompleter is implemented by clients that can return the log probability of
+// each generated token, as OpenAI-compatible chat completion APIs do.
+type LogProbCompleter interface {
+	// CompleteWithLogProbs sends a prompt to the LLM and returns the completion with
+	// the log probabilities of its tokens
+	CompleteWithLogProbs(ctx context.Context, prompt string) (string, []TokenLogProb, error)
+}
+
+type temperatureKey struct{}
+
+// WithTemperature returns a context whose completions are sampled at the given temperature,
+// e.g. to draw several different answers to the same prompt.
+func WithTemperature(ctx context.Context, temperature float64) context.Context {
+	return context.WithValue(ctx, temperatureKey{}, temperature)
+}
+
+// samplingOption applies the temperature set with WithTemperature, if any.
+func samplingOption(ctx context.Context) llms.CallOption {
+	return func(o *llms.CallOptions) {
+		if temperature, ok := ctx.Value(temperatureKey{}).(float64); ok {
+			o.Temperature = temperature
+		}
+	}
+}
+
+// chatRequest and chatResponse are the parts of the OpenAI chat completion API used to
+// request log probabilities, which langchain-go does not expose.
+type chatRequest struct {
+	Model               string        `json:"model"`
+	Messages            []chatMessage `json:"messages"`
+	MaxCompletionTokens int           `json:"max_completion_tokens,omitempty"`
+	Temperature         *float64      `json:"temperature,omitempty"`
+	LogProbs            bool          `json:"logprobs"`
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message  chatMessage `json:"message"`
+		LogProbs *struct {
+			Content []struct {
+				Token   string  `json:"token"`
+				LogProb float64 `json:"logprob"`
+			} `json:"content"`
+		} `json:"logprobs"`
+	} `json:"choices"`
+}
+
+// CompleteWithLogProbs sends a prompt to the OpenAI or Azure OpenAI chat completion API and
+// returns the completion with its token log probabilities. Other providers return
+// ErrLogProbsUnsupported.
+func (c *Client) CompleteWithLogProbs(ctx context.Context, prompt string) (string, []TokenLogProb, error) {
+	if c.provider != "openai" && c.provider != "azure" {
+		return "", nil, ErrLogProbsUnsupported
+	}
+	if c.provider == "azure" && c.baseURL == "" {
+		return "", nil, fmt.Errorf("%w: azure requires a service URL", ErrLogProbsUnsupported)
+	}
+
+	log.Infof("Sending prompt to LLM with log probabilities: %s", truncateForLogging(prompt))
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	req := chatRequest{
+		Model:               c.model,
+		Messages:            []chatMessage{{Role: "user", Content: prompt}},
+		MaxCompletionTokens: c.maxTokens,
+		LogProbs:            true,
+	}
+	if temperature, ok := ctx.Value(temperatureKey{}).(float64); ok {
+		req.Temperature = &temperature
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to encode LLM request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.chatCompletionsURL(), bytes.NewReader(body))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create LLM request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.provider == "azure" {
+		httpReq.Header.Set("api-key", c.apiKey)
+	} else {
+		httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return "", nil, fmt.Errorf("LLM generation failed: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read LLM response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", nil, fmt.Errorf("LLM generation failed: %s: %s", resp.Status, truncateForLogging(string(data)))
+	}
+
+	var chat chatResponse
+	if err := json.Unmarshal(data, &chat); err != nil {
+		return "", nil, fmt.Errorf("failed to parse LLM response: %w", err)
+	}
+	if len(chat.Choices) == 0 {
+		return "", nil, errors.New("LLM returned no choices")
+	}
+	choice := chat.Choices[0]
+	if choice.LogProbs == nil {
+		return "", nil, fmt.Errorf("%w: model %s returned none", ErrLogProbsUnsupported, c.model)
+	}
+	tokens := make([]TokenLogProb, 0, len(choice.LogProbs.Content))
+	for _, t := range choice.LogProbs.Content {
+		tokens = append(tokens, TokenLogProb{Token: t.Token, LogProb: t.LogProb})
+	}
+
+	log.Infof("Received response from LLM with %d token log probabilities: %s", len(tokens), truncateForLogging(choice.Message.Content))
+	recordModel(ctx, c.model)
+
+	return choice.Message.Content, tokens, nil
+}
+
+// chatCompletionsURL returns the chat completion endpoint of the provider. Azure addresses
+// the model as a deployment of the configured resource.
+func (c *Client) chatCompletionsURL() string {
+	if c.provider == "azure" {
+		return fmt.Sprintf("%s/openai/deployments/%s/chat/completions?api-version=%s",
+			strings.TrimSuffix(c.baseURL, "/"), url.PathEscape(c.model), azureAPIVersion)
+	}
+	baseURL := c.baseURL
+	if baseURL == "" {
+		baseURL = defaultOpenAIURL
+	}
+	return strings.TrimSuffix(baseURL, "/") + "/chat/completions"
+}
+
+// CompleteWithLogProbs returns the completion and token log probabilities of the first
+// available provider. ErrLogProbsUnsupported is returned as is when that provider cannot
+// return them; other failures are returned without trying further providers and do not
+// count towards opening the provider's circuit.
+func (r *Router) CompleteWithLogProbs(ctx context.Context, prompt string) (string, []TokenLogProb, error) {
+	var completion string
+	var tokens []TokenLogProb
+	err := r.do(ctx, func(c LLMClient) error {
+		completer, ok := c.(LogProbCompleter)
+		if !ok {
+			return ErrLogProbsUnsupported
+		}
+		var err error
+		completion, tokens, err = completer.CompleteWithLogProbs(ctx, prompt)
+		if err != nil && !errors.Is(err, ErrLogProbsUnsupported) {
+			return fmt.Errorf("%w: %w", errLogProbsFailed, err)
+		}
+		return err
+	})
+	return completion, tokens, err
+}
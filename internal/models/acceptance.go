@@ -0,0 +1,33 @@
+package models
+
+// AcceptanceCriteria is the acceptance criteria and test checklist drafted for a ticket.
+type AcceptanceCriteria struct {
+	TicketID    string                `json:"ticketId"`
+	Criteria    []AcceptanceCriterion `json:"criteria"`
+	TestCases   []TestCase            `json:"testCases"`
+	Assumptions []string              `json:"assumptions,omitempty"` // What the draft assumes the ticket leaves open
+	Model       string                `json:"model,omitempty"`       // Models that drafted the criteria
+	Prompts     []PromptRef           `json:"prompts,omitempty"`     // Prompt templates used
+	DraftedAt   string                `json:"draftedAt"`             // RFC 3339
+}
+
+// AcceptanceCriterion is one testable condition in Given/When/Then form.
+type AcceptanceCriterion struct {
+	Title string `json:"title"`
+	Given string `json:"given"`
+	When  string `json:"when"`
+	Then  string `json:"then"`
+}
+
+// TestCase is an item of a test checklist.
+type TestCase struct {
+	Title string `json:"title"`
+	Kind  string `json:"kind"` // One of the TestCase kinds
+}
+
+// Test case kinds.
+const (
+	TestCasePositive = "positive"
+	TestCaseNegative = "negative"
+	TestCaseEdge     = "edge"
+)
@@ -0,0 +1,188 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// AnalysisSchemaVersion is the version of the Analysis layout and of the JSON Schema
+// the LLM output is validated against. Increment it when fields are renamed or change meaning.
+const AnalysisSchemaVersion = 1
+
+// Sentiment is the tone of a ticket.
+type Sentiment string
+
+// Sentiment values.
+const (
+	SentimentPositive Sentiment = "Positive"
+	SentimentNegative Sentiment = "Negative"
+	SentimentNeutral  Sentiment = "Neutral"
+)
+
+// Urgency is how soon a ticket needs attention.
+type Urgency string
+
+// Urgency values, from least to most urgent.
+const (
+	UrgencyLow      Urgency = "Low"
+	UrgencyMedium   Urgency = "Medium"
+	UrgencyHigh     Urgency = "High"
+	UrgencyCritical Urgency = "Critical"
+)
+
+// Effort is a t-shirt size estimate of the work a ticket needs.
+type Effort string
+
+// Effort values, from smallest to largest.
+const (
+	EffortSmall  Effort = "Small"
+	EffortMedium Effort = "Medium"
+	EffortLarge  Effort = "Large"
+	EffortXLarge Effort = "X-Large"
+)
+
+// Analysis sources.
+const (
+	AnalysisSourceLLM       = "llm"
+	AnalysisSourceHeuristic = "heuristic"
+	AnalysisSourceMerged    = "llm+heuristic"
+)
+
+// Entity is something notable mentioned in a ticket, such as a product, user or error code.
+type Entity struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// Analysis is the structured analysis of a ticket produced by InformationGatheringAgent.
+// Field names match the JSON the LLM is asked to produce.
+type Analysis struct {
+	SchemaVersion         int       `json:"schemaVersion"`
+	Sentiment             Sentiment `json:"Sentiment,omitempty"`
+	Urgency               Urgency   `json:"Urgency,omitempty"`
+	KeyInformation        []string  `json:"KeyInformation,omitempty"`
+	DetectedEntities      []Entity  `json:"DetectedEntities,omitempty"`
+	SuggestedAction       string    `json:"SuggestedAction,omitempty"`
+	SuggestedAssignee     string    `json:"SuggestedAssignee,omitempty"`
+	EstimatedEffort       Effort    `json:"EstimatedEffort,omitempty"`
+	RelatedTickets        []string  `json:"RelatedTickets,omitempty"`
+	RequiresClarification bool      `json:"RequiresClarification"`
+	RecommendedLabels     []string  `json:"RecommendedLabels,omitempty"`
+	ThreatensSprintGoal   bool      `json:"ThreatensSprintGoal"`
+
+	// Set by the agent rather than the LLM
+	Source         string `json:"Source,omitempty"` // One of the AnalysisSource values
+	AddedMidSprint *bool  `json:"AddedMidSprint,omitempty"`
+	SprintWarning  string `json:"SprintWarning,omitempty"`
+	Status         string `json:"Status,omitempty"` // Why the analysis is limited, e.g. LLM unavailable
+
+	// Additional fields the LLM chose to include, rendered as text
+	Additional map[string]string `json:"Additional,omitempty"`
+}
+
+// AnalysisDelta describes how a revised analysis differs from the previous one.
+type AnalysisDelta struct {
+	Since   string           `json:"since,omitempty"`   // When the previous analysis was made, ISO 8601 format string
+	Summary string           `json:"summary,omitempty"` // Concise explanation of what changed and why
+	Changes []AnalysisChange `json:"changes,omitempty"` // Empty when the update did not change the analysis
+}
+
+// AnalysisChange is an analysis field whose value changed.
+type AnalysisChange struct {
+	Field  string `json:"field"`
+	From   string `json:"from,omitempty"`
+	To     string `json:"to,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// FieldConfidence is how sure the LLM is of an analysis field, from 0 (a guess) to 1 (certain).
+// Score averages the signals that were measured.
+type FieldConfidence struct {
+	Score        float64  `json:"score"`
+	SelfAssessed *float64 `json:"selfAssessed,omitempty"` // The model's own assessment
+	TokenProb    *float64 `json:"tokenProb,omitempty"`    // Geometric mean probability of the field's tokens in samples
+	Agreement    *float64 `json:"agreement,omitempty"`    // Similarity of the value across repeated samples
+}
+
+// AnalysisField is a named analysis value rendered as text.
+type AnalysisField struct {
+	Name  string
+	Value string
+}
+
+// Fields returns the populated analysis values as text in display order,
+// followed by any additional fields sorted by name.
+func (a *Analysis) Fields() []AnalysisField {
+	if a == nil {
+		return nil
+	}
+	var fields []AnalysisField
+	add := func(name, value string) {
+		if value != "" {
+			fields = append(fields, AnalysisField{Name: name, Value: value})
+		}
+	}
+	add("Status", a.Status)
+	add("Sentiment", string(a.Sentiment))
+	add("Urgency", string(a.Urgency))
+	add("KeyInformation", strings.Join(a.KeyInformation, "; "))
+	add("DetectedEntities", formatEntities(a.DetectedEntities))
+	add("SuggestedAction", a.SuggestedAction)
+	add("SuggestedAssignee", a.SuggestedAssignee)
+	add("EstimatedEffort", string(a.EstimatedEffort))
+	add("RelatedTickets", strings.Join(a.RelatedTickets, ", "))
+	add("RequiresClarification", fmt.Sprintf("%v", a.RequiresClarification))
+	add("RecommendedLabels", strings.Join(a.RecommendedLabels, ", "))
+	if a.ThreatensSprintGoal {
+		add("ThreatensSprintGoal", "true")
+	}
+	if a.AddedMidSprint != nil {
+		add("AddedMidSprint", fmt.Sprintf("%v", *a.AddedMidSprint))
+	}
+	add("SprintWarning", a.SprintWarning)
+
+	names := make([]string, 0, len(a.Additional))
+	for name := range a.Additional {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		add(name, a.Additional[name])
+	}
+	return fields
+}
+
+// Field returns an analysis value as text by field name, ignoring case.
+// Fields that are not set report false.
+func (a *Analysis) Field(name string) (string, bool) {
+	if a == nil {
+		return "", false
+	}
+	// Booleans are always meaningful, even when false
+	switch strings.ToLower(name) {
+	case "requiresclarification":
+		return fmt.Sprintf("%v", a.RequiresClarification), true
+	case "threatenssprintgoal":
+		return fmt.Sprintf("%v", a.ThreatensSprintGoal), true
+	}
+	for _, f := range a.Fields() {
+		if strings.EqualFold(f.Name, name) {
+			return f.Value, true
+		}
+	}
+	return "", false
+}
+
+// formatEntities renders entities as "Type: Value" pairs.
+func formatEntities(entities []Entity) string {
+	parts := make([]string, 0, len(entities))
+	for _, e := range entities {
+		if e.Type == "" {
+			parts = append(parts, e.Value)
+			continue
+		}
+		parts = append(parts, e.Type+": "+e.Value)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,44 @@
+package models
+
+// Diagnostics are the stack traces and error log lines found in a ticket's description,
+// comments and attachments.
+type Diagnostics struct {
+	StackTraces []StackTrace `json:"stackTraces,omitempty"`
+	LogLines    []LogLine    `json:"logLines,omitempty"`
+}
+
+// StackTrace is a parsed exception, panic or traceback.
+type StackTrace struct {
+	Language      string       `json:"language"`      // "java", "python", "go" or "javascript"
+	ExceptionType string       `json:"exceptionType"` // e.g. "java.lang.NullPointerException" or "panic"
+	Message       string       `json:"message,omitempty"`
+	RootCause     string       `json:"rootCause,omitempty"` // Innermost "Caused by" exception, with its message
+	Frames        []StackFrame `json:"frames,omitempty"`    // Top frames, innermost call first
+	Timestamp     string       `json:"timestamp,omitempty"` // Time of the log line reporting it, as written
+	Source        string       `json:"source"`              // Where it was found, e.g. "description" or "attachment app.log"
+	Occurrences   int          `json:"occurrences"`         // Times the same trace was found
+}
+
+// StackFrame is one call in a stack trace.
+type StackFrame struct {
+	Function string `json:"function,omitempty"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+}
+
+// LogLine is an error or warning log line, with repeats of the same message counted.
+type LogLine struct {
+	Level       string `json:"level"` // e.g. "ERROR" or "WARN"
+	Message     string `json:"message"`
+	Timestamp   string `json:"timestamp,omitempty"` // Of the first occurrence, as written
+	Source      string `json:"source"`
+	Occurrences int    `json:"occurrences"`
+}
+
+// Headline returns the exception type and message, e.g. "java.lang.IllegalStateException: boom".
+func (t StackTrace) Headline() string {
+	if t.Message == "" {
+		return t.ExceptionType
+	}
+	return t.ExceptionType + ": " + t.Message
+}
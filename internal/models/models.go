@@ -7,23 +7,194 @@ type TicketAvailableTask struct {
 	Summary     string            `json:"summary"`
 	Description string            `json:"description"`
 	Status      string            `json:"status"`
+	IssueType   string            `json:"issueType,omitempty"`
 	Reporter    string            `json:"reporter"`
 	Assignee    string            `json:"assignee"` // Assuming string for simplicity, might be complex type
 	Priority    string            `json:"priority"`
 	Labels      []string          `json:"labels"`
-	Created     string            `json:"created"` // ISO 8601 format string
-	Updated     string            `json:"updated"` // ISO 8601 format string
-	Changes     string            `json:"changes"` // Description of recent changes
-	Metadata    map[string]string `json:"metadata,omitempty"` // Optional additional fields
+	Created     string            `json:"created"`               // ISO 8601 format string
+	Updated     string            `json:"updated"`               // ISO 8601 format string
+	DueDate     string            `json:"dueDate,omitempty"`     // YYYY-MM-DD
+	Changes     string            `json:"changes"`               // Description of recent changes
+	Event       string            `json:"event,omitempty"`       // Webhook event, e.g. "created" or "updated"
+	Metadata    map[string]string `json:"metadata,omitempty"`    // Optional additional fields
+	Hierarchy   *TicketHierarchy  `json:"hierarchy,omitempty"`   // Parent chain, siblings and children
+	Comments    []JiraComment     `json:"comments,omitempty"`    // Existing comments, oldest first
+	Agile       *AgileContext     `json:"agile,omitempty"`       // Sprint, board and story points
+	RemoteLinks []RemoteLink      `json:"remoteLinks,omitempty"` // Links to PRs, Confluence pages, incidents
+	Development *DevelopmentInfo  `json:"development,omitempty"` // Branches, commits and pull requests
+	Attachments []Attachment      `json:"attachments,omitempty"` // Text attachments such as logs
+
+	// Set on update events to have the previous analysis revised rather than redone
+	FieldChanges []FieldChange     `json:"fieldChanges,omitempty"` // Changed fields with their old and new values
+	Previous     *InfoGatheredTask `json:"previous,omitempty"`     // Latest analysis of the ticket, if known
+}
+
+// HumanComments returns the comments of the ticket that JiraRetrievalAgent did not post itself.
+// The agent's own comments repeat its analysis and checklist questions, so they are not
+// evidence about the ticket.
+func (t *TicketAvailableTask) HumanComments() []JiraComment {
+	var out []JiraComment
+	for _, c := range t.Comments {
+		if !c.ByAgent {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// FieldChange is a ticket field changed by an update, with its old and new values as displayed in Jira.
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from,omitempty"`
+	To    string `json:"to,omitempty"`
+}
+
+// Attachment is a text file attached to a ticket.
+type Attachment struct {
+	Filename string `json:"filename"`
+	MimeType string `json:"mimeType,omitempty"`
+	Author   string `json:"author,omitempty"`
+	Created  string `json:"created,omitempty"` // ISO 8601 format string
+	Content  string `json:"content"`
+}
+
+// RemoteLink is a link from a ticket to an external resource.
+type RemoteLink struct {
+	Title        string `json:"title"`
+	URL          string `json:"url"`
+	Summary      string `json:"summary,omitempty"`
+	Relationship string `json:"relationship,omitempty"`
+	Application  string `json:"application,omitempty"`
+	Resolved     bool   `json:"resolved,omitempty"`
+}
+
+// PullRequest is a pull request linked to a ticket through the development panel.
+type PullRequest struct {
+	Title      string `json:"title"`
+	URL        string `json:"url"`
+	Status     string `json:"status"` // "OPEN", "MERGED" or "DECLINED"
+	Repository string `json:"repository,omitempty"`
+}
+
+// DevelopmentInfo summarizes the development work linked to a ticket.
+type DevelopmentInfo struct {
+	Branches     int           `json:"branches,omitempty"`
+	Commits      int           `json:"commits,omitempty"`
+	PullRequests []PullRequest `json:"pullRequests,omitempty"`
+}
+
+// Sprint describes an agile sprint.
+type Sprint struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	State     string `json:"state"` // "active", "future" or "closed"
+	Goal      string `json:"goal,omitempty"`
+	StartDate string `json:"startDate,omitempty"` // ISO 8601 format string
+	EndDate   string `json:"endDate,omitempty"`   // ISO 8601 format string
+}
+
+// AgileContext describes the sprint and board a ticket belongs to.
+type AgileContext struct {
+	Board           string   `json:"board,omitempty"`
+	Sprint          *Sprint  `json:"sprint,omitempty"`        // Active or future sprint, nil when in the backlog
+	ClosedSprints   int      `json:"closedSprints,omitempty"` // Number of sprints the ticket was carried over from
+	StoryPoints     *float64 `json:"storyPoints,omitempty"`
+	AddedToSprintAt string   `json:"addedToSprintAt,omitempty"` // ISO 8601 format string
+	AddedMidSprint  bool     `json:"addedMidSprint,omitempty"`  // Added after the active sprint started
+}
+
+// IssueRef is a lightweight reference to a related Jira issue.
+type IssueRef struct {
+	Key       string `json:"key"`
+	Summary   string `json:"summary,omitempty"`
+	Status    string `json:"status,omitempty"`
+	IssueType string `json:"issueType,omitempty"`
+}
+
+// TicketHierarchy describes where a ticket sits in the Jira issue hierarchy.
+type TicketHierarchy struct {
+	Parents  []IssueRef `json:"parents,omitempty"`  // Nearest parent first, up to the epic/initiative
+	Siblings []IssueRef `json:"siblings,omitempty"` // Other children of the direct parent
+	Children []IssueRef `json:"children,omitempty"` // Subtasks or issues in the epic
 }
 
 // InfoGatheredTask represents the result sent back from InformationGatheringAgent
 // after processing a TicketAvailableTask.
 type InfoGatheredTask struct {
-	TaskID         string            `json:"taskId"`         // Original task ID
-	TicketID       string            `json:"ticketId"`       // Jira Ticket ID
-	AnalysisResult map[string]string `json:"analysisResult"` // Structured analysis from LLM or rules
-	Summary        string            `json:"summary"`        // Human-readable summary
+	TaskID         string          `json:"taskId"`                   // Original task ID
+	TicketID       string          `json:"ticketId"`                 // Jira Ticket ID
+	AnalysisResult *Analysis       `json:"analysisResult"`           // Structured analysis from LLM or rules
+	Summary        string          `json:"summary"`                  // Human-readable summary
+	Model          string          `json:"model,omitempty"`          // LLM model that produced the analysis
+	Prompts        []PromptRef     `json:"prompts,omitempty"`        // Prompt templates used for the analysis and summary
+	SimilarTickets []SimilarTicket `json:"similarTickets,omitempty"` // Previously seen tickets most alike, best first
+	Redactions     map[string]int  `json:"redactions,omitempty"`     // Values redacted from LLM prompts per category
+	Diagnostics    *Diagnostics    `json:"diagnostics,omitempty"`    // Stack traces and error log lines found in the ticket
+	AnalyzedAt     string          `json:"analyzedAt,omitempty"`     // ISO 8601 format string
+	Cached         bool            `json:"cached,omitempty"`         // Reused from an earlier analysis of unchanged content
+	Delta          *AnalysisDelta  `json:"delta,omitempty"`          // How the analysis changed, when it revised a previous one
+	// How sure the LLM is of each analysis field, by field name
+	Confidence map[string]FieldConfidence `json:"confidence,omitempty"`
+	// How well the ticket meets its Definition of Ready, when quality assessment is enabled
+	Quality *QualityAssessment `json:"quality,omitempty"`
+}
+
+// SimilarTicket is a previously analyzed ticket similar to the current one.
+type SimilarTicket struct {
+	Key               string  `json:"key"`
+	Summary           string  `json:"summary"`
+	Score             float64 `json:"score"`                       // Cosine similarity, 1 for identical text
+	ProbableDuplicate bool    `json:"probableDuplicate,omitempty"` // Score is above the duplicate threshold
+}
+
+// PromptRef identifies the prompt template version that produced an LLM output.
+type PromptRef struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// AnalysisPropertySchemaVersion is the version of the AnalysisProperty layout.
+// Increment it when fields are renamed or change meaning so consumers can adapt.
+const AnalysisPropertySchemaVersion = 3
+
+// AnalysisProperty is the issue entity property JiraRetrievalAgent writes with each analysis,
+// letting dashboards and JQL read the result without parsing comments. It holds the analysis
+// but not the ticket context it was made from, as Jira limits property values to 32 KB.
+type AnalysisProperty struct {
+	SchemaVersion  int                        `json:"schemaVersion"`
+	AnalysisResult *Analysis                  `json:"analysisResult"`
+	Summary        string                     `json:"summary,omitempty"` // Left out when the property would exceed the size limit
+	Model          string                     `json:"model,omitempty"`
+	Prompts        []PromptRef                `json:"prompts,omitempty"`
+	Confidence     map[string]FieldConfidence `json:"confidence,omitempty"`
+	AnalyzedAt     string                     `json:"analyzedAt,omitempty"`
+}
+
+// NewAnalysisProperty returns the issue property recording an analysis.
+func NewAnalysisProperty(task *InfoGatheredTask) AnalysisProperty {
+	return AnalysisProperty{
+		SchemaVersion:  AnalysisPropertySchemaVersion,
+		AnalysisResult: task.AnalysisResult,
+		Summary:        task.Summary,
+		Model:          task.Model,
+		Prompts:        task.Prompts,
+		Confidence:     task.Confidence,
+		AnalyzedAt:     task.AnalyzedAt,
+	}
+}
+
+// Analysis returns the recorded analysis of the given ticket.
+func (p *AnalysisProperty) Analysis(ticketID string) *InfoGatheredTask {
+	return &InfoGatheredTask{
+		TicketID:       ticketID,
+		AnalysisResult: p.AnalysisResult,
+		Summary:        p.Summary,
+		Model:          p.Model,
+		Prompts:        p.Prompts,
+		Confidence:     p.Confidence,
+		AnalyzedAt:     p.AnalyzedAt,
+	}
 }
 
 // JiraTicket represents a Jira issue fetched from Jira API
@@ -51,4 +222,5 @@ type JiraComment struct {
 	Created string `json:"created"`
 	Author  string `json:"author,omitempty"`
 	URL     string `json:"url,omitempty"`
+	ByAgent bool   `json:"byAgent,omitempty"` // Posted by JiraRetrievalAgent itself
 }
@@ -0,0 +1,20 @@
+package models
+
+// QualityAssessment is how well a ticket meets the Definition-of-Ready checklist of its issue type.
+type QualityAssessment struct {
+	Score     int                  `json:"score"`               // 0-100, the weighted share of checklist items present
+	Ready     bool                 `json:"ready"`               // Every checklist item is present
+	Checks    []QualityCheckResult `json:"checks"`              // In checklist order
+	Missing   []string             `json:"missing,omitempty"`   // Names of the missing items
+	Questions []string             `json:"questions,omitempty"` // Follow-up questions for the reporter about the missing items
+	Reporter  string               `json:"reporter,omitempty"`  // Who the questions are for
+	Source    string               `json:"source"`              // One of the AnalysisSource values
+}
+
+// QualityCheckResult is whether a ticket contains one checklist item.
+type QualityCheckResult struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Present  bool   `json:"present"`
+	Evidence string `json:"evidence,omitempty"` // What satisfied the item, or why it is missing
+}
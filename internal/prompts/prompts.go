@@ -0,0 +1,284 @@
+// Package prompts loads the text/template prompts used for ticket analysis.
+//
+// Built-in templates are embedded in the binary. A prompt directory may override them
+// with files named {kind}.tmpl, {kind}.{PROJECT}.tmpl or {kind}.{PROJECT}.{IssueType}.tmpl,
+// where kind is "analysis", "summary", "condense", "delta", "quality" or "acceptance";
+// the most specific match wins.
+package prompts
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/tuannvm/jira-a2a/internal/config"
+	log "github.com/tuannvm/jira-a2a/internal/logging"
+	"github.com/tuannvm/jira-a2a/internal/models"
+)
+
+// Prompt kinds.
+const (
+	KindAnalysis   = "analysis"
+	KindSummary    = "summary"
+	KindCondense   = "condense"   // Shortens a prompt section too large for the model's context
+	KindDelta      = "delta"      // Revises a previous analysis after a ticket update
+	KindQuality    = "quality"    // Checks a ticket against its Definition-of-Ready checklist
+	KindAcceptance = "acceptance" // Drafts acceptance criteria and test cases
+)
+
+// kinds lists every prompt kind, each of which has a built-in template.
+var kinds = []string{KindAnalysis, KindSummary, KindCondense, KindDelta, KindQuality, KindAcceptance}
+
+// reloadDelay groups the burst of events editors produce when saving a file.
+const reloadDelay = 250 * time.Millisecond
+
+//go:embed templates/*.tmpl
+var builtin embed.FS
+
+// AnalysisData is the data passed to analysis templates.
+// The sections are pre-rendered as text, "None" when empty.
+type AnalysisData struct {
+	Ticket      *models.TicketAvailableTask
+	Comments    string
+	Hierarchy   string
+	Sprint      string
+	RemoteLinks string
+	Development string
+	Diagnostics string // Stack traces and error log lines parsed from the ticket and its attachments
+	Attachments string // Text attachments such as logs, one per file
+	Schema      string // JSON Schema the analysis must match
+}
+
+// SummaryData is the data passed to summary templates.
+type SummaryData struct {
+	Ticket   *models.TicketAvailableTask
+	Analysis []models.AnalysisField
+}
+
+// CondenseData is the data passed to condense templates, once per chunk of an oversized section.
+type CondenseData struct {
+	Ticket   *models.TicketAvailableTask
+	Section  string // Section name, e.g. "Description"
+	Part     int    // 1-based chunk number
+	Parts    int    // Number of chunks the section was split into
+	Text     string
+	MaxWords int // Length the condensed chunk should stay within
+}
+
+// DeltaData is the data passed to delta templates, which revise a previous analysis after an update.
+type DeltaData struct {
+	Ticket     *models.TicketAvailableTask
+	Comments   string                 // Latest comments, "None" when empty
+	Previous   []models.AnalysisField // Previous analysis
+	PreviousAt string                 // When the previous analysis was made
+	Changes    []models.FieldChange   // Fields changed by the update
+	Schema     string                 // JSON Schema the changes must match
+}
+
+// QualityData is the data passed to quality templates, which check a ticket against the
+// Definition-of-Ready checklist of its issue type.
+type QualityData struct {
+	Ticket   *models.TicketAvailableTask
+	Comments string                // "None" when empty
+	Checks   []config.QualityCheck // Checklist items to look for
+	Schema   string                // JSON Schema the assessment must match
+}
+
+// AcceptanceData is the data passed to acceptance templates, which draft acceptance criteria
+// and a test checklist. The sections are pre-rendered as text, "None" when empty.
+type AcceptanceData struct {
+	Ticket      *models.TicketAvailableTask
+	Comments    string
+	Hierarchy   string // Parent, sibling and child issues
+	RemoteLinks string // Linked documents, e.g. specifications
+	Schema      string // JSON Schema the criteria must match
+}
+
+// Template is a parsed prompt template.
+type Template struct {
+	Name    string // File name, prefixed with "builtin/" for embedded templates
+	Version string // Content hash, changing whenever the template text changes
+	tmpl    *template.Template
+}
+
+// Ref identifies the template for recording alongside its output.
+func (t *Template) Ref() models.PromptRef {
+	return models.PromptRef{Name: t.Name, Version: t.Version}
+}
+
+// Execute renders the template with data.
+func (t *Template) Execute(data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := t.tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render prompt template %s: %w", t.Name, err)
+	}
+	return buf.String(), nil
+}
+
+// Store holds the prompt templates and reloads them when the prompt directory changes.
+type Store struct {
+	dir       string
+	mu        sync.RWMutex
+	templates map[string]*Template // Keyed by lower-cased selector, e.g. "analysis.proj.bug"
+}
+
+// NewStore loads the built-in templates and any overrides from dir (which may be empty).
+// Every template is rendered against sample data, so errors surface at startup.
+func NewStore(dir string) (*Store, error) {
+	s := &Store{dir: dir}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Select returns the most specific template of the given kind for a project and issue type.
+func (s *Store) Select(kind, project, issueType string) *Template {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, key := range []string{
+		strings.Join([]string{kind, project, issueType}, "."),
+		strings.Join([]string{kind, project}, "."),
+		kind,
+	} {
+		if t, ok := s.templates[strings.ToLower(key)]; ok {
+			return t
+		}
+	}
+	return nil
+}
+
+// Reload reads all templates again. On error the previously loaded templates are kept.
+func (s *Store) Reload() error {
+	templates := make(map[string]*Template)
+	for _, kind := range kinds {
+		name := kind + ".tmpl"
+		text, err := builtin.ReadFile("templates/" + name)
+		if err != nil {
+			return fmt.Errorf("failed to read built-in prompt %s: %w", name, err)
+		}
+		t, err := parse("builtin/"+name, kind, string(text))
+		if err != nil {
+			return err
+		}
+		templates[kind] = t
+	}
+
+	if s.dir != "" {
+		paths, err := filepath.Glob(filepath.Join(s.dir, "*.tmpl"))
+		if err != nil {
+			return fmt.Errorf("failed to list prompt templates: %w", err)
+		}
+		for _, path := range paths {
+			name := filepath.Base(path)
+			key := strings.TrimSuffix(name, ".tmpl")
+			kind := strings.SplitN(key, ".", 2)[0]
+			if !slices.Contains(kinds, kind) {
+				return fmt.Errorf("prompt template %s: unknown kind %q (expected one of %s)", name, kind, strings.Join(kinds, ", "))
+			}
+			text, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to read prompt template %s: %w", name, err)
+			}
+			t, err := parse(name, kind, string(text))
+			if err != nil {
+				return err
+			}
+			templates[strings.ToLower(key)] = t
+		}
+	}
+
+	s.mu.Lock()
+	s.templates = templates
+	s.mu.Unlock()
+	return nil
+}
+
+// Watch reloads the templates whenever a file in the prompt directory changes, until ctx is canceled.
+// Templates that fail to load are reported and the previous set stays in use.
+func (s *Store) Watch(ctx context.Context) error {
+	if s.dir == "" {
+		return nil
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create prompt watcher: %w", err)
+	}
+	defer watcher.Close()
+	if err := watcher.Add(s.dir); err != nil {
+		return fmt.Errorf("failed to watch prompt directory %s: %w", s.dir, err)
+	}
+
+	var reload <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if strings.HasSuffix(event.Name, ".tmpl") {
+				reload = time.After(reloadDelay)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Warnf("Prompt watcher error: %v", err)
+		case <-reload:
+			reload = nil
+			if err := s.Reload(); err != nil {
+				log.Errorf("Failed to reload prompt templates, keeping previous ones: %v", err)
+				continue
+			}
+			log.Infof("Reloaded prompt templates from %s", s.dir)
+		}
+	}
+}
+
+// parse parses a template and validates it by rendering it against sample data for its kind.
+func parse(name, kind, text string) (*Template, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Funcs(template.FuncMap{
+		"join": strings.Join,
+	}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse prompt template %s: %w", name, err)
+	}
+	sum := sha256.Sum256([]byte(text))
+	t := &Template{Name: name, Version: hex.EncodeToString(sum[:6]), tmpl: tmpl}
+
+	ticket := &models.TicketAvailableTask{TicketID: "PROJ-1", Labels: []string{"sample"}}
+	var sample interface{} = AnalysisData{Ticket: ticket}
+	switch kind {
+	case KindSummary:
+		sample = SummaryData{Ticket: ticket, Analysis: []models.AnalysisField{{Name: "Urgency", Value: "High"}}}
+	case KindCondense:
+		sample = CondenseData{Ticket: ticket, Section: "Description", Part: 1, Parts: 2, Text: "sample", MaxWords: 100}
+	case KindDelta:
+		sample = DeltaData{
+			Ticket:   ticket,
+			Previous: []models.AnalysisField{{Name: "Urgency", Value: "Medium"}},
+			Changes:  []models.FieldChange{{Field: "priority", From: "Medium", To: "High"}},
+		}
+	case KindQuality:
+		sample = QualityData{Ticket: ticket, Checks: []config.QualityCheck{{ID: "repro-steps", Name: "Steps to reproduce"}}}
+	case KindAcceptance:
+		sample = AcceptanceData{Ticket: ticket}
+	}
+	if _, err := t.Execute(sample); err != nil {
+		return nil, fmt.Errorf("invalid prompt template: %w", err)
+	}
+	return t, nil
+}
@@ -0,0 +1,179 @@
+// Package quality scores tickets against the Definition-of-Ready checklist of their issue type,
+// such as steps to reproduce for bugs or acceptance criteria for stories. Checklist items are
+// found with patterns here and judged by the LLM in InformationGatheringAgent when it is enabled.
+package quality
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/tuannvm/jira-a2a/internal/config"
+	"github.com/tuannvm/jira-a2a/internal/models"
+)
+
+// maxEvidence bounds the length of matched text quoted as evidence.
+const maxEvidence = 80
+
+// DefaultChecklists are used when no checklist file is configured.
+var DefaultChecklists = config.QualityChecklists{
+	"Bug": {
+		{
+			ID:          "repro-steps",
+			Name:        "Steps to reproduce",
+			Description: "The steps that make the problem happen, in order",
+			Patterns:    []string{`(?i)(steps to reproduce|to reproduce|repro(?:duction)? steps|how to reproduce)`, `(?m)^\s*(?:step\s*)?1[.):]\s+\S`},
+			Question:    "What are the exact steps to reproduce the problem?",
+			Weight:      2,
+		},
+		{
+			ID:          "expected-actual",
+			Name:        "Expected vs actual behavior",
+			Description: "What should have happened and what happened instead",
+			Patterns:    []string{`(?is)\bexpected\b.{0,500}\b(actual|instead|but)\b`},
+			Question:    "What did you expect to happen, and what happened instead?",
+			Weight:      2,
+		},
+		{
+			ID:          "environment",
+			Name:        "Environment",
+			Description: "Where the problem happens, e.g. production or staging, browser, operating system or device",
+			Patterns:    []string{`(?im)^\s*[*-]?\s*(environment|env|os|browser|platform|device)\s*[:=]\s*\S`, `(?i)\b(production|staging|sandbox|windows|macos|linux|ios|android|chrome|firefox|safari|edge)\b`},
+			Question:    "In which environment does this happen (e.g. production or staging, browser, operating system)?",
+			Weight:      1,
+		},
+		{
+			ID:          "affected-version",
+			Name:        "Affected version",
+			Description: "The version, release or build in which the problem occurs",
+			Patterns:    []string{`(?i)\b(?:affects? versions?|version|ver\.?|release|build|v)\s*:?\s*\d+(?:\.\d+){1,3}\b`},
+			Question:    "Which version or release is affected?",
+			Weight:      1,
+		},
+	},
+	"Story": {
+		{
+			ID:          "acceptance-criteria",
+			Name:        "Acceptance criteria",
+			Description: "Testable conditions that tell when the story is done",
+			Patterns:    []string{`(?i)acceptance criteria`, `(?is)\bgiven\b.{0,300}\bwhen\b.{0,300}\bthen\b`},
+			Question:    "What are the acceptance criteria that tell us this story is done?",
+			Weight:      2,
+		},
+		{
+			ID:          "user-value",
+			Name:        "User and value",
+			Description: "Who the story is for and what they gain from it",
+			Patterns:    []string{`(?is)\bas an? \w+.{0,200}\bso that\b`},
+			Question:    "Who is this for, and what will they be able to do once it is done?",
+			Weight:      1,
+		},
+	},
+	"*": {
+		{
+			ID:          "description",
+			Name:        "Description",
+			Description: "A description of the problem or request beyond the summary",
+			Patterns:    []string{`\S(?:.|\n){40,}`},
+			Question:    "Could you describe the problem or request in more detail?",
+			Weight:      1,
+		},
+	},
+}
+
+var patterns sync.Map // Compiled checklist patterns by source
+
+// Checklist returns the checklist for an issue type, matched case-insensitively, or the "*"
+// checklist when the issue type has none.
+func Checklist(checklists config.QualityChecklists, issueType string) []config.QualityCheck {
+	for name, checks := range checklists {
+		if strings.EqualFold(name, issueType) {
+			return checks
+		}
+	}
+	return checklists["*"]
+}
+
+// Version returns a hash of a checklist, changing whenever the checklist changes.
+func Version(checks []config.QualityCheck) string {
+	data, _ := json.Marshal(checks)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:6])
+}
+
+// Evaluate looks for each checklist item in the ticket's description and comments with the
+// item's patterns. Items without patterns can only be judged by the LLM and count as missing.
+// The agent's own comments quote the checklist, so they are not searched.
+func Evaluate(task *models.TicketAvailableTask, checks []config.QualityCheck) *models.QualityAssessment {
+	texts := []string{task.Description}
+	for _, c := range task.HumanComments() {
+		texts = append(texts, c.Body)
+	}
+	text := strings.Join(texts, "\n")
+
+	results := make([]models.QualityCheckResult, len(checks))
+	for i, check := range checks {
+		results[i] = models.QualityCheckResult{ID: check.ID, Name: check.Name}
+		for _, p := range check.Patterns {
+			if match := compile(p).FindString(text); match != "" {
+				results[i].Present = true
+				results[i].Evidence = fmt.Sprintf("Found %q", truncate(strings.TrimSpace(match)))
+				break
+			}
+		}
+	}
+	return Assess(task, checks, results, nil, models.AnalysisSourceHeuristic)
+}
+
+// Assess scores the results of a checklist. Each missing item gets a follow-up question:
+// the one given for its ID in questions, or else the one configured for the item.
+func Assess(task *models.TicketAvailableTask, checks []config.QualityCheck, results []models.QualityCheckResult, questions map[string]string, source string) *models.QualityAssessment {
+	assessment := &models.QualityAssessment{Checks: results, Reporter: task.Reporter, Source: source}
+	total, present := 0, 0
+	for i, check := range checks {
+		weight := max(check.Weight, 1)
+		total += weight
+		if results[i].Present {
+			present += weight
+			continue
+		}
+		assessment.Missing = append(assessment.Missing, check.Name)
+		question := questions[check.ID]
+		if question == "" {
+			question = check.Question
+		}
+		if question == "" {
+			question = fmt.Sprintf("Could you add the %s?", strings.ToLower(check.Name))
+		}
+		assessment.Questions = append(assessment.Questions, question)
+	}
+	assessment.Score = 100
+	if total > 0 {
+		assessment.Score = int(math.Round(float64(present) * 100 / float64(total)))
+	}
+	assessment.Ready = len(assessment.Missing) == 0
+	return assessment
+}
+
+// compile returns the compiled form of a pattern validated when the checklist was loaded.
+func compile(pattern string) *regexp.Regexp {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp)
+	}
+	re := regexp.MustCompile(pattern)
+	patterns.Store(pattern, re)
+	return re
+}
+
+func truncate(s string) string {
+	runes := []rune(s)
+	if len(runes) <= maxEvidence {
+		return s
+	}
+	return string(runes[:maxEvidence]) + "…"
+}
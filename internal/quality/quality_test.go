@@ -0,0 +1,79 @@
+package quality
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tuannvm/jira-a2a/internal/models"
+)
+
+func TestEvaluate(t *testing.T) {
+	agentComment := models.JiraComment{
+		Body:    "*Definition of Ready*: missing: Acceptance criteria\n- What are the acceptance criteria that tell us this story is done?",
+		ByAgent: true,
+	}
+	tests := []struct {
+		name        string
+		task        models.TicketAvailableTask
+		wantMissing []string
+		wantScore   int
+	}{
+		{
+			name: "complete story",
+			task: models.TicketAvailableTask{
+				IssueType:   "Story",
+				Description: "As an admin I want to export users so that I can audit them.\n\nAcceptance criteria: the export is a CSV file.",
+			},
+			wantScore: 100,
+		},
+		{
+			name: "criteria in a reporter comment",
+			task: models.TicketAvailableTask{
+				IssueType:   "Story",
+				Description: "As an admin I want to export users so that I can audit them.",
+				Comments:    []models.JiraComment{{Body: "Given an admin, when they export, then a CSV is downloaded"}},
+			},
+			wantScore: 100,
+		},
+		{
+			name: "criteria only in the agent's own comment",
+			task: models.TicketAvailableTask{
+				IssueType:   "Story",
+				Description: "As an admin I want to export users so that I can audit them.",
+				Comments:    []models.JiraComment{agentComment},
+			},
+			wantMissing: []string{"Acceptance criteria"},
+			wantScore:   33,
+		},
+		{
+			name: "bug whose steps are only asked for by the agent",
+			task: models.TicketAvailableTask{
+				IssueType:   "Bug",
+				Description: "Expected the login to work but it fails on version 2.3.1 in production",
+				Comments:    []models.JiraComment{{Body: "What are the exact steps to reproduce the problem?", ByAgent: true}},
+			},
+			wantMissing: []string{"Steps to reproduce"},
+			wantScore:   67,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Evaluate(&tt.task, Checklist(DefaultChecklists, tt.task.IssueType))
+			if !reflect.DeepEqual(got.Missing, tt.wantMissing) {
+				t.Errorf("missing = %v, want %v", got.Missing, tt.wantMissing)
+			}
+			if got.Score != tt.wantScore || got.Ready != (len(tt.wantMissing) == 0) {
+				t.Errorf("score = %d, ready = %v; want %d", got.Score, got.Ready, tt.wantScore)
+			}
+		})
+	}
+}
+
+func TestChecklist(t *testing.T) {
+	if got := Checklist(DefaultChecklists, "story"); len(got) == 0 || got[0].ID != "acceptance-criteria" {
+		t.Errorf("Checklist(story) = %+v, want the Story checklist", got)
+	}
+	if got := Checklist(DefaultChecklists, "Task"); !reflect.DeepEqual(got, DefaultChecklists["*"]) {
+		t.Errorf("Checklist(Task) = %+v, want the default checklist", got)
+	}
+}
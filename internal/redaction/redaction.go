@@ -0,0 +1,257 @@
+// Package redaction replaces personal data and secrets in ticket content with placeholders
+// before it is sent to an external LLM, and restores them in the LLM's output.
+package redaction
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/tuannvm/jira-a2a/internal/config"
+)
+
+// detector finds values of one category in text.
+type detector struct {
+	category string
+	pattern  *regexp.Regexp
+	group    int                     // Submatch to redact, 0 for the whole match
+	valid    func(value string) bool // Optional check, such as a checksum, to reduce false positives
+}
+
+// builtinDetectors are applied in order, so secrets spanning several lines and values
+// containing other values (an email's domain) are redacted before their parts.
+func builtinDetectors() []detector {
+	return []detector{
+		{category: "private_key", pattern: regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`)},
+		{category: "jwt", pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`)},
+		{category: "token", pattern: regexp.MustCompile(`\b(?:AKIA|ASIA)[A-Z0-9]{16}\b|\bgh[pousr]_[A-Za-z0-9]{36,}\b|\bxox[abprs]-[A-Za-z0-9-]{10,}|\bsk-[A-Za-z0-9_-]{20,}`)},
+		{category: "token", pattern: regexp.MustCompile(`(?i)\bbearer\s+([A-Za-z0-9._~+/-]{16,}=*)`), group: 1},
+		{category: "secret", pattern: regexp.MustCompile(`(?i)\b(?:password|passwd|pwd|secret|token|api[_-]?key|access[_-]?key|client[_-]?secret)["']?\s*[:=]\s*["']?([^\s"',;]{6,})`), group: 1},
+		{category: "email", pattern: regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`)},
+		{category: "credit_card", pattern: regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`), valid: luhn},
+		{category: "iban", pattern: regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]{4}){2,7}(?: ?[A-Z0-9]{1,4})?\b`), valid: ibanChecksum},
+		{category: "ip_address", pattern: regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`), valid: ipv4},
+		{category: "phone", pattern: regexp.MustCompile(`(?:\+\d{1,3}[\s.-]?)?(?:\(\d{1,4}\)[\s.-]?)?\b\d{2,4}[\s.-]\d{3,4}(?:[\s.-]\d{2,4}){0,2}\b`), valid: phoneDigits},
+	}
+}
+
+// Redactor holds the detectors configured for the deployment.
+type Redactor struct {
+	detectors []detector
+}
+
+// New creates a redactor from the built-in detectors and the given rules.
+func New(rules *config.RedactionRules) (*Redactor, error) {
+	disabled := make(map[string]bool)
+	for _, name := range rules.Disable {
+		disabled[strings.ToLower(name)] = true
+	}
+	var detectors []detector
+	for _, d := range builtinDetectors() {
+		if !disabled[d.category] {
+			detectors = append(detectors, d)
+		}
+	}
+
+	if len(rules.Domains) > 0 {
+		quoted := make([]string, 0, len(rules.Domains))
+		for _, domain := range rules.Domains {
+			quoted = append(quoted, regexp.QuoteMeta(strings.TrimPrefix(domain, ".")))
+		}
+		detectors = append(detectors, detector{
+			category: "hostname",
+			pattern:  regexp.MustCompile(`(?i)\b(?:[a-z0-9-]+\.)*(?:` + strings.Join(quoted, "|") + `)\b`),
+		})
+	}
+	for _, p := range rules.Patterns {
+		re, err := regexp.Compile(p.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %s: %w", p.Name, err)
+		}
+		detectors = append(detectors, detector{category: p.Name, pattern: re})
+	}
+
+	categories := make([]string, 0, len(rules.Dictionary))
+	for category := range rules.Dictionary {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	for _, category := range categories {
+		terms := append([]string(nil), rules.Dictionary[category]...)
+		// Longer terms first so "Acme Corp" wins over "Acme"
+		sort.Slice(terms, func(i, j int) bool { return len(terms[i]) > len(terms[j]) })
+		quoted := make([]string, 0, len(terms))
+		for _, term := range terms {
+			if term = strings.TrimSpace(term); term != "" {
+				quoted = append(quoted, regexp.QuoteMeta(term))
+			}
+		}
+		if len(quoted) > 0 {
+			detectors = append(detectors, detector{
+				category: category,
+				pattern:  regexp.MustCompile(`(?i)\b(?:` + strings.Join(quoted, "|") + `)\b`),
+			})
+		}
+	}
+	return &Redactor{detectors: detectors}, nil
+}
+
+// NewSession starts redacting the content of one task. Placeholders are consistent within
+// the session, so the same value is always replaced by the same placeholder.
+func (r *Redactor) NewSession() *Session {
+	if r == nil {
+		return nil
+	}
+	return &Session{redactor: r, placeholders: make(map[string]string), originals: make(map[string]string), counts: make(map[string]int)}
+}
+
+// Session redacts and restores the content of one task. A nil session leaves text unchanged.
+type Session struct {
+	redactor     *Redactor
+	placeholders map[string]string // Original value -> placeholder
+	originals    map[string]string // Placeholder -> original value
+	counts       map[string]int    // Category -> number of distinct values redacted
+}
+
+// Redact replaces every detected value in text with a placeholder such as [EMAIL_1].
+func (s *Session) Redact(text string) string {
+	if s == nil || text == "" {
+		return text
+	}
+	for _, d := range s.redactor.detectors {
+		text = s.apply(d, text)
+	}
+	return text
+}
+
+func (s *Session) apply(d detector, text string) string {
+	matches := d.pattern.FindAllStringSubmatchIndex(text, -1)
+	if len(matches) == 0 {
+		return text
+	}
+	var sb strings.Builder
+	last := 0
+	for _, m := range matches {
+		start, end := m[2*d.group], m[2*d.group+1]
+		if start < 0 {
+			continue
+		}
+		value := text[start:end]
+		if d.valid != nil && !d.valid(value) {
+			continue
+		}
+		sb.WriteString(text[last:start])
+		sb.WriteString(s.placeholder(d.category, value))
+		last = end
+	}
+	sb.WriteString(text[last:])
+	return sb.String()
+}
+
+// placeholder returns the placeholder for a value, allocating a new one on first sight.
+func (s *Session) placeholder(category, value string) string {
+	if p, ok := s.placeholders[value]; ok {
+		return p
+	}
+	s.counts[category]++
+	p := fmt.Sprintf("[%s_%d]", strings.ToUpper(category), s.counts[category])
+	s.placeholders[value] = p
+	s.originals[p] = value
+	return p
+}
+
+// Restore puts the original values back in place of the placeholders in text.
+func (s *Session) Restore(text string) string {
+	if s == nil || len(s.originals) == 0 || text == "" {
+		return text
+	}
+	pairs := make([]string, 0, 2*len(s.originals))
+	for p, original := range s.originals {
+		pairs = append(pairs, p, original)
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+// Counts returns the number of distinct values redacted per category.
+func (s *Session) Counts() map[string]int {
+	if s == nil || len(s.counts) == 0 {
+		return nil
+	}
+	out := make(map[string]int, len(s.counts))
+	for k, v := range s.counts {
+		out[k] = v
+	}
+	return out
+}
+
+// luhn validates a credit card number with the Luhn checksum.
+func luhn(value string) bool {
+	digits := onlyDigits(value)
+	if len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// ibanChecksum validates an IBAN with the ISO 13616 mod-97 check.
+func ibanChecksum(value string) bool {
+	iban := strings.ReplaceAll(value, " ", "")
+	if len(iban) < 15 || len(iban) > 34 {
+		return false
+	}
+	rearranged := iban[4:] + iban[:4]
+	remainder := 0
+	for _, c := range rearranged {
+		switch {
+		case c >= '0' && c <= '9':
+			remainder = (remainder*10 + int(c-'0')) % 97
+		case c >= 'A' && c <= 'Z':
+			remainder = (remainder*100 + int(c-'A'+10)) % 97
+		default:
+			return false
+		}
+	}
+	return remainder == 1
+}
+
+// ipv4 checks that every octet of a dotted address is at most 255.
+func ipv4(value string) bool {
+	for _, octet := range strings.Split(value, ".") {
+		n := 0
+		fmt.Sscanf(octet, "%d", &n)
+		if n > 255 {
+			return false
+		}
+	}
+	return true
+}
+
+// phoneDigits accepts numbers with the length of a phone number, ruling out dates and versions.
+func phoneDigits(value string) bool {
+	digits := onlyDigits(value)
+	return len(digits) >= 9 && len(digits) <= 15 && !strings.Contains(value, ".")
+}
+
+func onlyDigits(value string) string {
+	var sb strings.Builder
+	for _, c := range value {
+		if c >= '0' && c <= '9' {
+			sb.WriteRune(c)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,133 @@
+package redaction
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tuannvm/jira-a2a/internal/config"
+)
+
+func TestValidators(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid func(string) bool
+		value string
+		want  bool
+	}{
+		{name: "luhn valid", valid: luhn, value: "4111 1111 1111 1111", want: true},
+		{name: "luhn dashes", valid: luhn, value: "5500-0000-0000-0004", want: true},
+		{name: "luhn wrong check digit", valid: luhn, value: "4111 1111 1111 1112"},
+		{name: "luhn too short", valid: luhn, value: "4111 1111 111"},
+		{name: "iban valid", valid: ibanChecksum, value: "DE89 3704 0044 0532 0130 00", want: true},
+		{name: "iban letters", valid: ibanChecksum, value: "GB82WEST12345698765432", want: true},
+		{name: "iban wrong checksum", valid: ibanChecksum, value: "DE89370400440532013001"},
+		{name: "iban too short", valid: ibanChecksum, value: "DE8937040044"},
+		{name: "ipv4 valid", valid: ipv4, value: "10.0.1.25", want: true},
+		{name: "ipv4 broadcast", valid: ipv4, value: "255.255.255.255", want: true},
+		{name: "ipv4 octet too large", valid: ipv4, value: "10.0.256.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.valid(tt.value); got != tt.want {
+				t.Errorf("%q valid = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "credit card", text: "Card 4111 1111 1111 1111 was declined", want: "Card [CREDIT_CARD_1] was declined"},
+		{name: "invalid card number", text: "Order 4111 1111 1111 1112 was declined", want: "Order 4111 1111 1111 1112 was declined"},
+		{name: "iban", text: "Refund to DE89370400440532013000 please", want: "Refund to [IBAN_1] please"},
+		{name: "invalid iban", text: "Refund to DE89370400440532013001 please", want: "Refund to DE89370400440532013001 please"},
+		{name: "ip address", text: "Seen from 10.0.1.25 and 999.0.0.1", want: "Seen from [IP_ADDRESS_1] and 999.0.0.1"},
+		{name: "repeated value", text: "jane@example.com wrote to bob@example.com, cc jane@example.com", want: "[EMAIL_1] wrote to [EMAIL_2], cc [EMAIL_1]"},
+		{name: "version is kept", text: "Broken since 2.3.1", want: "Broken since 2.3.1"},
+	}
+	redactor, err := New(&config.RedactionRules{})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := redactor.NewSession()
+			got := session.Redact(tt.text)
+			if got != tt.want {
+				t.Errorf("Redact = %q, want %q", got, tt.want)
+			}
+			if restored := session.Restore(got); restored != tt.text {
+				t.Errorf("Restore = %q, want %q", restored, tt.text)
+			}
+		})
+	}
+}
+
+func TestRedactRules(t *testing.T) {
+	redactor, err := New(&config.RedactionRules{
+		Disable:    []string{"EMAIL"},
+		Patterns:   []config.RedactionPattern{{Name: "employee_id", Pattern: `\bEMP-\d{5}\b`}},
+		Domains:    []string{".corp.example"},
+		Dictionary: map[string][]string{"customer": {"Acme", "Acme Corp"}},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	session := redactor.NewSession()
+	got := session.Redact("EMP-12345 at Acme Corp (jane@example.com) cannot reach db1.eu.corp.example")
+	want := "[EMPLOYEE_ID_1] at [CUSTOMER_1] (jane@example.com) cannot reach [HOSTNAME_1]"
+	if got != want {
+		t.Errorf("Redact = %q, want %q", got, want)
+	}
+	if counts := session.Counts(); !reflect.DeepEqual(counts, map[string]int{"employee_id": 1, "customer": 1, "hostname": 1}) {
+		t.Errorf("Counts = %v", counts)
+	}
+
+	if _, err := New(&config.RedactionRules{Patterns: []config.RedactionPattern{{Name: "bad", Pattern: `(`}}}); err == nil {
+		t.Error("New accepted an invalid pattern")
+	}
+}
+
+func TestRestore(t *testing.T) {
+	redactor, err := New(&config.RedactionRules{})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	session := redactor.NewSession()
+	emails := make([]string, 10)
+	for i := range emails {
+		emails[i] = fmt.Sprintf("user%d@example.com", i+1)
+	}
+	session.Redact(strings.Join(emails, " "))
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "embedded", text: "Ask [EMAIL_1].", want: "Ask user1@example.com."},
+		{name: "adjacent", text: "[EMAIL_2][EMAIL_1]", want: "user2@example.comuser1@example.com"},
+		{name: "common prefix", text: "[EMAIL_10] and [EMAIL_1]", want: "user10@example.com and user1@example.com"},
+		{name: "unknown placeholder", text: "[EMAIL_11] and [PHONE_1]", want: "[EMAIL_11] and [PHONE_1]"},
+		// A placeholder is only restored whole; streamed output must hold back an incomplete one
+		{name: "incomplete", text: "Ask [EMAIL_", want: "Ask [EMAIL_"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := session.Restore(tt.text); got != tt.want {
+				t.Errorf("Restore(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+
+	var nilSession *Session
+	if got := nilSession.Redact("jane@example.com"); got != "jane@example.com" {
+		t.Errorf("nil session Redact = %q", got)
+	}
+}
@@ -0,0 +1,93 @@
+package redaction
+
+import (
+	"encoding/json"
+
+	"github.com/tuannvm/jira-a2a/internal/models"
+)
+
+// RedactTask returns a copy of the task with all free text and names redacted.
+// The original task is left untouched for local processing.
+func (s *Session) RedactTask(task *models.TicketAvailableTask) *models.TicketAvailableTask {
+	if s == nil {
+		return task
+	}
+	out := &models.TicketAvailableTask{}
+	data, _ := json.Marshal(task)
+	_ = json.Unmarshal(data, out) // Deep copy
+
+	for _, field := range []*string{&out.Summary, &out.Description, &out.Changes, &out.Reporter, &out.Assignee} {
+		*field = s.Redact(*field)
+	}
+	for k, v := range out.Metadata {
+		out.Metadata[k] = s.Redact(v)
+	}
+	for i := range out.FieldChanges {
+		out.FieldChanges[i].From = s.Redact(out.FieldChanges[i].From)
+		out.FieldChanges[i].To = s.Redact(out.FieldChanges[i].To)
+	}
+	// The previous analysis is redacted with RedactFields by prompts that use it
+	out.Previous = nil
+	for i := range out.Comments {
+		out.Comments[i].Author = s.Redact(out.Comments[i].Author)
+		out.Comments[i].Body = s.Redact(out.Comments[i].Body)
+	}
+	if out.Hierarchy != nil {
+		for _, refs := range [][]models.IssueRef{out.Hierarchy.Parents, out.Hierarchy.Siblings, out.Hierarchy.Children} {
+			for i := range refs {
+				refs[i].Summary = s.Redact(refs[i].Summary)
+			}
+		}
+	}
+	if out.Agile != nil && out.Agile.Sprint != nil {
+		out.Agile.Sprint.Goal = s.Redact(out.Agile.Sprint.Goal)
+	}
+	for i := range out.RemoteLinks {
+		out.RemoteLinks[i].Title = s.Redact(out.RemoteLinks[i].Title)
+		out.RemoteLinks[i].URL = s.Redact(out.RemoteLinks[i].URL)
+		out.RemoteLinks[i].Summary = s.Redact(out.RemoteLinks[i].Summary)
+	}
+	for i := range out.Attachments {
+		out.Attachments[i].Author = s.Redact(out.Attachments[i].Author)
+		out.Attachments[i].Content = s.Redact(out.Attachments[i].Content)
+	}
+	if out.Development != nil {
+		for i := range out.Development.PullRequests {
+			out.Development.PullRequests[i].Title = s.Redact(out.Development.PullRequests[i].Title)
+			out.Development.PullRequests[i].URL = s.Redact(out.Development.PullRequests[i].URL)
+		}
+	}
+	return out
+}
+
+// RedactFields returns analysis fields with their values redacted, for prompts built from an analysis.
+func (s *Session) RedactFields(fields []models.AnalysisField) []models.AnalysisField {
+	if s == nil {
+		return fields
+	}
+	out := make([]models.AnalysisField, len(fields))
+	for i, f := range fields {
+		out[i] = models.AnalysisField{Name: f.Name, Value: s.Redact(f.Value)}
+	}
+	return out
+}
+
+// RestoreAnalysis puts the original values back into the text fields of an LLM analysis.
+func (s *Session) RestoreAnalysis(a *models.Analysis) {
+	if s == nil || a == nil {
+		return
+	}
+	for _, list := range [][]string{a.KeyInformation, a.RelatedTickets, a.RecommendedLabels} {
+		for i := range list {
+			list[i] = s.Restore(list[i])
+		}
+	}
+	for i := range a.DetectedEntities {
+		a.DetectedEntities[i].Value = s.Restore(a.DetectedEntities[i].Value)
+	}
+	a.SuggestedAction = s.Restore(a.SuggestedAction)
+	a.SuggestedAssignee = s.Restore(a.SuggestedAssignee)
+	for k, v := range a.Additional {
+		a.Additional[k] = s.Restore(v)
+	}
+}
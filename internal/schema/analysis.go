@@ -0,0 +1,80 @@
+package schema
+
+import (
+	"embed"
+	"sync"
+)
+
+//go:embed analysis.json delta.json quality.json acceptance.json
+var documents embed.FS
+
+// The embedded schemas, each parsed on first use
+var (
+	analysisSchema   = load("analysis.json")
+	deltaSchema      = load("delta.json")
+	qualitySchema    = load("quality.json")
+	acceptanceSchema = load("acceptance.json")
+)
+
+// AnalysisJSON returns the JSON Schema document describing models.Analysis as produced by the LLM.
+func AnalysisJSON() []byte {
+	return document("analysis.json")
+}
+
+// Analysis returns the parsed JSON Schema for models.Analysis.
+func Analysis() *Schema {
+	return analysisSchema()
+}
+
+// DeltaJSON returns the JSON Schema document describing the changes the LLM proposes
+// to a previous analysis after a ticket update.
+func DeltaJSON() []byte {
+	return document("delta.json")
+}
+
+// Delta returns the parsed JSON Schema for analysis changes.
+func Delta() *Schema {
+	return deltaSchema()
+}
+
+// QualityJSON returns the JSON Schema document describing the LLM's assessment of a ticket
+// against its Definition-of-Ready checklist.
+func QualityJSON() []byte {
+	return document("quality.json")
+}
+
+// Quality returns the parsed JSON Schema for ticket quality assessments.
+func Quality() *Schema {
+	return qualitySchema()
+}
+
+// AcceptanceJSON returns the JSON Schema document describing the acceptance criteria and
+// test checklist the LLM drafts for a ticket.
+func AcceptanceJSON() []byte {
+	return document("acceptance.json")
+}
+
+// Acceptance returns the parsed JSON Schema for drafted acceptance criteria.
+func Acceptance() *Schema {
+	return acceptanceSchema()
+}
+
+// load returns a function parsing the named embedded schema once.
+func load(name string) func() *Schema {
+	return sync.OnceValue(func() *Schema {
+		s, err := Parse(document(name))
+		if err != nil {
+			panic(err) // The embedded schema is part of the build
+		}
+		return s
+	})
+}
+
+// document returns the named embedded schema document.
+func document(name string) []byte {
+	data, err := documents.ReadFile(name)
+	if err != nil {
+		panic(err) // The embedded schema is part of the build
+	}
+	return data
+}
@@ -0,0 +1,232 @@
+// Package schema validates JSON documents against the subset of JSON Schema used
+// to describe LLM output: object, array, string, boolean, integer and number types,
+// enum, required, properties, items and additionalProperties.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Schema is a JSON Schema document or subschema.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+}
+
+// ValidationError describes where a document violates a schema.
+type ValidationError struct {
+	Path    string // JSON path of the offending value, e.g. $.KeyInformation[0]
+	Message string
+}
+
+// Error implements the error interface.
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// Parse decodes a JSON Schema document.
+func Parse(data []byte) (*Schema, error) {
+	var s Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON schema: %w", err)
+	}
+	return &s, nil
+}
+
+// Validate checks a decoded JSON value (as produced by encoding/json into interface{})
+// against the schema and returns every violation found, or nil if the value is valid.
+func (s *Schema) Validate(value interface{}) []ValidationError {
+	var errs []ValidationError
+	s.validate("$", value, &errs)
+	return errs
+}
+
+func (s *Schema) validate(path string, value interface{}, errs *[]ValidationError) {
+	fail := func(format string, args ...interface{}) {
+		*errs = append(*errs, ValidationError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if s.Type != "" && !hasType(value, s.Type) {
+		fail("expected %s, got %s", s.Type, typeOf(value))
+		return
+	}
+	if len(s.Enum) > 0 && !inEnum(s.Enum, value) {
+		fail("must be one of %s", formatEnum(s.Enum))
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				fail("missing required property %q", name)
+			}
+		}
+		for _, name := range sortedKeys(v) {
+			prop, ok := s.Properties[name]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					fail("unexpected property %q", name)
+				}
+				continue
+			}
+			prop.validate(path+"."+name, v[name], errs)
+		}
+	case []interface{}:
+		if s.Items != nil {
+			for i, item := range v {
+				s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, errs)
+			}
+		}
+	}
+}
+
+// Coerce repairs common near misses in LLM output so they need not be re-prompted:
+// enum values in the wrong case, booleans and numbers sent as strings, and a single
+// string where an array of strings is expected. Values that cannot be repaired are
+// returned unchanged for Validate to report.
+func (s *Schema) Coerce(value interface{}) interface{} {
+	switch s.Type {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return value
+		}
+		for name, v := range obj {
+			if prop, ok := s.Properties[name]; ok {
+				obj[name] = prop.Coerce(v)
+			}
+		}
+		return obj
+	case "array":
+		if str, ok := value.(string); ok && s.Items != nil && s.Items.Type == "string" {
+			var items []interface{}
+			for _, item := range strings.Split(str, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+			return items
+		}
+		arr, ok := value.([]interface{})
+		if !ok || s.Items == nil {
+			return value
+		}
+		for i, item := range arr {
+			arr[i] = s.Items.Coerce(item)
+		}
+		return arr
+	case "boolean":
+		if str, ok := value.(string); ok {
+			switch strings.ToLower(strings.TrimSpace(str)) {
+			case "true", "yes":
+				return true
+			case "false", "no":
+				return false
+			}
+		}
+		return value
+	case "integer", "number":
+		if str, ok := value.(string); ok {
+			var n float64
+			if _, err := fmt.Sscanf(strings.TrimSpace(str), "%g", &n); err == nil {
+				return n
+			}
+		}
+		return value
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return value
+		}
+		for _, allowed := range s.Enum {
+			if a, ok := allowed.(string); ok && strings.EqualFold(a, strings.TrimSpace(str)) {
+				return a
+			}
+		}
+		return str
+	}
+	return value
+}
+
+// hasType reports whether value is of the given JSON Schema type.
+func hasType(value interface{}, typ string) bool {
+	switch typ {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	case "null":
+		return value == nil
+	}
+	return true
+}
+
+// typeOf returns the JSON type name of a decoded value.
+func typeOf(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func inEnum(enum []interface{}, value interface{}) bool {
+	for _, allowed := range enum {
+		if allowed == value {
+			return true
+		}
+	}
+	return false
+}
+
+func formatEnum(enum []interface{}) string {
+	parts := make([]string, 0, len(enum))
+	for _, v := range enum {
+		parts = append(parts, fmt.Sprintf("%q", fmt.Sprint(v)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
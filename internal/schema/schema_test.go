@@ -0,0 +1,105 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// decode parses a JSON document as Validate and Coerce expect it
+func decode(t *testing.T, doc string) interface{} {
+	t.Helper()
+	var v interface{}
+	if err := json.Unmarshal([]byte(doc), &v); err != nil {
+		t.Fatalf("decoding %s: %v", doc, err)
+	}
+	return v
+}
+
+func TestValidate(t *testing.T) {
+	valid := `{"Sentiment": "Negative", "Urgency": "High", "KeyInformation": ["SSO login fails"],
+		"SuggestedAction": "Check the IdP certificate", "EstimatedEffort": "Small", "RequiresClarification": false}`
+	tests := []struct {
+		name string
+		doc  string
+		want []ValidationError
+	}{
+		{name: "valid", doc: valid},
+		{
+			name: "not an object",
+			doc:  `["High"]`,
+			want: []ValidationError{{Path: "$", Message: "expected object, got array"}},
+		},
+		{
+			name: "missing required",
+			doc:  `{"Sentiment": "Negative", "Urgency": "High", "KeyInformation": [], "SuggestedAction": "", "EstimatedEffort": "Small"}`,
+			want: []ValidationError{{Path: "$", Message: `missing required property "RequiresClarification"`}},
+		},
+		{
+			name: "enum and nested type",
+			doc: `{"Sentiment": "Angry", "Urgency": "High", "KeyInformation": ["a", 2], "SuggestedAction": "",
+				"EstimatedEffort": "Small", "RequiresClarification": true}`,
+			want: []ValidationError{
+				{Path: "$.KeyInformation[1]", Message: "expected string, got number"},
+				{Path: "$.Sentiment", Message: `must be one of "Positive", "Negative", "Neutral"`},
+			},
+		},
+		{
+			name: "entity missing value",
+			doc: `{"Sentiment": "Neutral", "Urgency": "Low", "KeyInformation": [], "SuggestedAction": "",
+				"EstimatedEffort": "Small", "RequiresClarification": false, "DetectedEntities": [{"type": "product"}]}`,
+			want: []ValidationError{{Path: "$.DetectedEntities[0]", Message: `missing required property "value"`}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Analysis().Validate(decode(t, tt.doc)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateAdditionalProperties(t *testing.T) {
+	closed := false
+	s := &Schema{Type: "object", Properties: map[string]*Schema{"a": {Type: "integer"}}, AdditionalProperties: &closed}
+	want := []ValidationError{
+		{Path: "$.a", Message: "expected integer, got number"},
+		{Path: "$", Message: `unexpected property "b"`},
+	}
+	if got := s.Validate(decode(t, `{"a": 1.5, "b": true}`)); !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate = %v, want %v", got, want)
+	}
+}
+
+func TestCoerce(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want string
+	}{
+		{name: "enum case", doc: `{"Urgency": " high"}`, want: `{"Urgency": "High"}`},
+		{name: "boolean strings", doc: `{"RequiresClarification": "Yes", "ThreatensSprintGoal": "false"}`, want: `{"RequiresClarification": true, "ThreatensSprintGoal": false}`},
+		{name: "number string", doc: `{"Confidence": {"Urgency": "0.8"}}`, want: `{"Confidence": {"Urgency": 0.8}}`},
+		{name: "comma separated list", doc: `{"RecommendedLabels": "sso, login,"}`, want: `{"RecommendedLabels": ["sso", "login"]}`},
+		{name: "unknown enum kept", doc: `{"Sentiment": "Angry"}`, want: `{"Sentiment": "Angry"}`},
+		{name: "unparseable boolean kept", doc: `{"RequiresClarification": "maybe"}`, want: `{"RequiresClarification": "maybe"}`},
+		{name: "non-object kept", doc: `"High"`, want: `"High"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Analysis().Coerce(decode(t, tt.doc))
+			if want := decode(t, tt.want); !reflect.DeepEqual(got, want) {
+				t.Errorf("Coerce = %#v, want %#v", got, want)
+			}
+		})
+	}
+}
+
+func TestEmbeddedSchemasParse(t *testing.T) {
+	for name, s := range map[string]*Schema{"analysis": Analysis(), "delta": Delta(), "quality": Quality(), "acceptance": Acceptance()} {
+		if s == nil || s.Type != "object" || len(s.Properties) == 0 {
+			t.Errorf("%s schema = %+v", name, s)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+// Package similarity finds tickets similar to a new one by comparing text embeddings
+// against an on-disk index of previously analyzed tickets.
+package similarity
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms/openai"
+	"github.com/tuannvm/jira-a2a/internal/config"
+)
+
+// Embedder turns texts into vectors whose cosine similarity reflects how alike the texts are.
+type Embedder interface {
+	// Name identifies the embedding model; vectors from different names are not comparable
+	Name() string
+	// Embed returns one vector per text
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// NewEmbedder creates the embedder selected by the configuration.
+func NewEmbedder(cfg *config.Config) (Embedder, error) {
+	switch cfg.EmbeddingProvider {
+	case "", "hash":
+		return NewHashEmbedder(hashDimensions), nil
+	case "openai":
+		opts := []openai.Option{
+			openai.WithToken(cfg.LLMAPIKey),
+			openai.WithEmbeddingModel(cfg.EmbeddingModel),
+		}
+		if cfg.LLMServiceURL != "" {
+			opts = append(opts, openai.WithBaseURL(cfg.LLMServiceURL))
+		}
+		client, err := openai.New(opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize embedding client: %w", err)
+		}
+		return &openAIEmbedder{client: client, model: cfg.EmbeddingModel}, nil
+	default:
+		return nil, fmt.Errorf("unsupported embedding provider: %s", cfg.EmbeddingProvider)
+	}
+}
+
+// openAIEmbedder embeds texts with the OpenAI embeddings API.
+type openAIEmbedder struct {
+	client *openai.LLM
+	model  string
+}
+
+func (e *openAIEmbedder) Name() string { return "openai/" + e.model }
+
+func (e *openAIEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors, err := e.client.CreateEmbedding(ctx, texts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create embeddings: %w", err)
+	}
+	for i := range vectors {
+		vectors[i] = normalize(vectors[i])
+	}
+	return vectors, nil
+}
+
+// Hash embedder tuning.
+const (
+	hashDimensions = 512 // Vector size of the default hash embedder
+	bigramWeight   = 0.5 // Weight of a word pair relative to a single word
+)
+
+var wordPattern = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+// stopWords are too common in tickets to say anything about similarity.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "but": true,
+	"by": true, "can": true, "for": true, "from": true, "has": true, "have": true, "i": true, "in": true,
+	"is": true, "it": true, "of": true, "on": true, "or": true, "please": true, "that": true, "the": true,
+	"this": true, "to": true, "was": true, "we": true, "when": true, "with": true,
+}
+
+// HashEmbedder is a local embedder based on feature hashing of words and word pairs.
+// It needs no external service and catches tickets that share wording, such as
+// the same bug filed several times.
+type HashEmbedder struct {
+	dimensions int
+}
+
+// NewHashEmbedder creates a hash embedder producing vectors of the given size.
+func NewHashEmbedder(dimensions int) *HashEmbedder {
+	return &HashEmbedder{dimensions: dimensions}
+}
+
+func (e *HashEmbedder) Name() string { return fmt.Sprintf("hash-%d", e.dimensions) }
+
+func (e *HashEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = e.embed(text)
+	}
+	return vectors, nil
+}
+
+func (e *HashEmbedder) embed(text string) []float32 {
+	var words []string
+	for _, w := range wordPattern.FindAllString(strings.ToLower(text), -1) {
+		if !stopWords[w] {
+			words = append(words, stem(w))
+		}
+	}
+
+	// Word pairs count for less than words so rephrasing does not hide a duplicate
+	counts := make(map[string]float64)
+	for i, w := range words {
+		counts[w]++
+		if i > 0 {
+			counts[words[i-1]+" "+w] += bigramWeight
+		}
+	}
+
+	vector := make([]float32, e.dimensions)
+	for feature, n := range counts {
+		h := fnv.New64a()
+		h.Write([]byte(feature))
+		sum := h.Sum64()
+		weight := float32(1 + math.Log(n))
+		// The top bit picks the sign so that collisions tend to cancel out
+		if sum>>63 == 1 {
+			weight = -weight
+		}
+		vector[sum%uint64(e.dimensions)] += weight
+	}
+	return normalize(vector)
+}
+
+// stem strips common English suffixes so "fails", "failed" and "failing" match.
+func stem(word string) string {
+	for _, suffix := range []string{"ing", "ed", "es", "s"} {
+		if len(word) > len(suffix)+2 && strings.HasSuffix(word, suffix) {
+			return strings.TrimSuffix(word, suffix)
+		}
+	}
+	return word
+}
+
+// normalize scales a vector to unit length so the dot product is the cosine similarity.
+func normalize(v []float32) []float32 {
+	var norm float64
+	for _, x := range v {
+		norm += float64(x) * float64(x)
+	}
+	if norm == 0 {
+		return v
+	}
+	scale := float32(1 / math.Sqrt(norm))
+	for i := range v {
+		v[i] *= scale
+	}
+	return v
+}
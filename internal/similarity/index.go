@@ -0,0 +1,182 @@
+package similarity
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/tuannvm/jira-a2a/internal/logging"
+)
+
+// Match is an indexed ticket similar to the one searched for.
+type Match struct {
+	Key     string
+	Summary string
+	Score   float64 // Cosine similarity, 1 for identical text
+}
+
+// Index is an on-disk vector index of previously seen tickets, searched by brute force.
+// It is safe for concurrent use.
+type Index struct {
+	path     string
+	embedder string
+	mu       sync.RWMutex
+	entries  map[string]*entry
+}
+
+type entry struct {
+	Key     string `json:"key"`
+	Summary string `json:"summary"`
+	Vector  vector `json:"vector"`
+	Updated string `json:"updated"` // ISO 8601 format string
+}
+
+// indexFile is the layout of the index file.
+type indexFile struct {
+	Embedder string   `json:"embedder"`
+	Entries  []*entry `json:"entries"`
+}
+
+// OpenIndex loads the index stored at path, or starts an empty one if the file does not exist.
+// An index built with a different embedder is discarded, as its vectors are not comparable.
+func OpenIndex(path, embedder string) (*Index, error) {
+	idx := &Index{path: path, embedder: embedder, entries: make(map[string]*entry)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return idx, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read similarity index: %w", err)
+	}
+	var file indexFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse similarity index %s: %w", path, err)
+	}
+	if file.Embedder != embedder {
+		log.Warnf("Similarity index %s was built with %s, not %s; starting a new index", path, file.Embedder, embedder)
+		return idx, nil
+	}
+	for _, e := range file.Entries {
+		idx.entries[e.Key] = e
+	}
+	return idx, nil
+}
+
+// Len returns the number of indexed tickets.
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.entries)
+}
+
+// Search returns up to k tickets most similar to vec, best first, excluding the ticket exclude.
+func (idx *Index) Search(vec []float32, k int, exclude string) []Match {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	matches := make([]Match, 0, len(idx.entries))
+	for key, e := range idx.entries {
+		if key == exclude || len(e.Vector) != len(vec) {
+			continue
+		}
+		matches = append(matches, Match{Key: key, Summary: e.Summary, Score: dot(vec, e.Vector)})
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Key < matches[j].Key
+	})
+	if len(matches) > k {
+		matches = matches[:k]
+	}
+	return matches
+}
+
+// Upsert adds a ticket to the index or replaces its previous vector.
+func (idx *Index) Upsert(key, summary string, vec []float32) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.entries[key] = &entry{Key: key, Summary: summary, Vector: vec, Updated: time.Now().UTC().Format(time.RFC3339)}
+}
+
+// Save writes the index to disk, replacing the file atomically.
+func (idx *Index) Save() error {
+	idx.mu.RLock()
+	file := indexFile{Embedder: idx.embedder, Entries: make([]*entry, 0, len(idx.entries))}
+	for _, e := range idx.entries {
+		file.Entries = append(file.Entries, e)
+	}
+	idx.mu.RUnlock()
+	sort.Slice(file.Entries, func(i, j int) bool { return file.Entries[i].Key < file.Entries[j].Key })
+
+	data, err := json.Marshal(file)
+	if err != nil {
+		return fmt.Errorf("failed to encode similarity index: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(idx.path), filepath.Base(idx.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write similarity index: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write similarity index: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write similarity index: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), idx.path); err != nil {
+		return fmt.Errorf("failed to replace similarity index: %w", err)
+	}
+	return nil
+}
+
+// dot returns the dot product of two unit vectors, their cosine similarity.
+func dot(a, b []float32) float64 {
+	var sum float64
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
+
+// vector is stored as base64 encoded little-endian float32 values to keep the index file small.
+type vector []float32
+
+func (v vector) MarshalJSON() ([]byte, error) {
+	buf := make([]byte, 4*len(v))
+	for i, x := range v {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(x))
+	}
+	return json.Marshal(base64.StdEncoding.EncodeToString(buf))
+}
+
+func (v *vector) UnmarshalJSON(data []byte) error {
+	var encoded string
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+	buf, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return err
+	}
+	if len(buf)%4 != 0 {
+		return fmt.Errorf("invalid vector length %d", len(buf))
+	}
+	out := make(vector, len(buf)/4)
+	for i := range out {
+		out[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf[4*i:]))
+	}
+	*v = out
+	return nil
+}
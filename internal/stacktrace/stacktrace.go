@@ -0,0 +1,394 @@
+// Package stacktrace finds stack traces and error log lines in ticket text and parses
+// them into exception types, top frames and timestamps, so the analysis does not depend
+// on the LLM reading raw traces correctly.
+package stacktrace
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/tuannvm/jira-a2a/internal/models"
+)
+
+// Limits keeping the extracted diagnostics small enough for a prompt.
+const (
+	maxTraces   = 5
+	maxFrames   = 5
+	maxLogLines = 10
+	// maxMessageLen caps exception and log messages, which may embed whole payloads
+	maxMessageLen = 300
+)
+
+var (
+	markupPattern    = regexp.MustCompile(`\{(?:code|noformat)(?::[^}]*)?\}`)
+	timestampPattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:[.,]\d+)?(?:Z|[+-]\d{2}:?\d{2})?`)
+	digitsPattern    = regexp.MustCompile(`\d+`)
+
+	// Java: "java.lang.IllegalStateException: message" followed by "at pkg.Class.method(File.java:12)"
+	javaHeader   = regexp.MustCompile(`^(?:Exception in thread "[^"]*"\s+)?((?:[a-zA-Z_$][\w$]*\.)+[A-Z][\w$]*)(?::\s*(.*))?$`)
+	javaFrame    = regexp.MustCompile(`^at\s+(?:[\w.-]+/)?([\w$.<>]+)\(([^)]*)\)(?:\s+~?\[.*\])?$`)
+	javaCausedBy = regexp.MustCompile(`^Caused by:\s*(.+)$`)
+	javaMore     = regexp.MustCompile(`^\.\.\. \d+ (?:more|common frames omitted)$`)
+
+	// Python: "Traceback (most recent call last):", "File "x.py", line 3, in f", then "ValueError: message"
+	pythonHeader    = regexp.MustCompile(`^Traceback \(most recent call last\):$`)
+	pythonFrame     = regexp.MustCompile(`^File "(.+)", line (\d+)(?:, in (.+))?$`)
+	pythonException = regexp.MustCompile(`^([A-Za-z_][\w.]*)(?::\s*(.*))?$`)
+
+	// Go: "panic: message", "goroutine 1 [running]:", then "pkg.Func(...)" and "\t/path/file.go:12 +0x1d"
+	goHeader   = regexp.MustCompile(`^(panic|fatal error): (.*)$`)
+	goFunction = regexp.MustCompile(`^(\S+?)\([^()]*\)$`)
+	goLocation = regexp.MustCompile(`^(\S+\.go):(\d+)(?: \+0x[0-9a-f]+)?$`)
+
+	// JavaScript: "TypeError: message" followed by "at fn (file.js:12:5)" or "at file.js:12:5"
+	jsHeader = regexp.MustCompile(`^(?:Uncaught\s+)?([A-Z]\w*(?:Error|Exception)|Error)(?::\s*(.*))?$`)
+	jsFrame  = regexp.MustCompile(`^at\s+(?:(.+?)\s+\()?(.+?):(\d+):\d+\)?$`)
+
+	// Log levels: plain "ERROR", logfmt "level=error" and JSON "level":"error"
+	levelPattern  = regexp.MustCompile(`\b(FATAL|CRITICAL|SEVERE|ERROR|WARN|WARNING)\b`)
+	logfmtPattern = regexp.MustCompile(`(?i)\blevel=(fatal|critical|error|warn|warning)\b`)
+	logfmtMessage = regexp.MustCompile(`\bmsg=(?:"((?:[^"\\]|\\.)*)"|(\S+))`)
+	loggerPrefix  = regexp.MustCompile(`^(?:\[[^\]]*\]\s*)*(?:[\w$]+(?:\.[\w$]+)+\s+-\s+)?`)
+)
+
+// Extract parses the stack traces and error log lines of a ticket's description, comments
+// and attachments. It returns nil when none are found.
+func Extract(task *models.TicketAvailableTask) *models.Diagnostics {
+	c := &collector{}
+	c.parse(task.Description, "description")
+	for i, comment := range task.Comments {
+		source := fmt.Sprintf("comment %d", i+1)
+		if comment.Author != "" {
+			source += " by " + comment.Author
+		}
+		c.parse(comment.Body, source)
+	}
+	for _, a := range task.Attachments {
+		c.parse(a.Content, "attachment "+a.Filename)
+	}
+	if len(c.traces) == 0 && len(c.logLines) == 0 {
+		return nil
+	}
+	return &models.Diagnostics{StackTraces: c.traces, LogLines: c.logLines}
+}
+
+// Parse returns the stack traces and error log lines in text, attributing them to source.
+func Parse(text, source string) ([]models.StackTrace, []models.LogLine) {
+	c := &collector{}
+	c.parse(text, source)
+	return c.traces, c.logLines
+}
+
+// collector accumulates traces and log lines across texts, counting repeats.
+type collector struct {
+	traces   []models.StackTrace
+	logLines []models.LogLine
+}
+
+func (c *collector) parse(text, source string) {
+	if text == "" {
+		return
+	}
+	lines := normalize(text)
+	for i := 0; i < len(lines); i++ {
+		if trace, end, ok := parseTrace(lines, i); ok {
+			trace.Source = source
+			trace.Timestamp = findTimestamp(lines, i)
+			c.addTrace(trace)
+			i = end - 1
+			continue
+		}
+		if line, ok := parseLogLine(lines[i]); ok {
+			line.Source = source
+			c.addLogLine(line)
+		}
+	}
+}
+
+// normalize splits text into trimmed lines, removing Jira code markup and quote markers.
+func normalize(text string) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		line = markupPattern.ReplaceAllString(line, "")
+		line = strings.TrimSpace(line)
+		line = strings.TrimSpace(strings.TrimLeft(line, ">"))
+		lines[i] = line
+	}
+	return lines
+}
+
+// parseTrace parses a stack trace starting at lines[i], returning the index after it.
+func parseTrace(lines []string, i int) (models.StackTrace, int, bool) {
+	line := lines[i]
+	if pythonHeader.MatchString(line) {
+		return parsePython(lines, i)
+	}
+	if m := goHeader.FindStringSubmatch(line); m != nil {
+		return parseGo(lines, i, m[1], m[2])
+	}
+	next := nextNonEmpty(lines, i+1)
+	if next < len(lines) {
+		if m := javaHeader.FindStringSubmatch(line); m != nil && javaFrame.MatchString(lines[next]) {
+			return parseJava(lines, next, m[1], m[2])
+		}
+		if m := jsHeader.FindStringSubmatch(line); m != nil && jsFrame.MatchString(lines[next]) {
+			return parseJS(lines, next, m[1], m[2])
+		}
+	}
+	return models.StackTrace{}, i, false
+}
+
+func parseJava(lines []string, i int, exceptionType, message string) (models.StackTrace, int, bool) {
+	trace := models.StackTrace{Language: "java", ExceptionType: exceptionType, Message: clip(message)}
+	inCause := false
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if m := javaFrame.FindStringSubmatch(line); m != nil {
+			if !inCause && len(trace.Frames) < maxFrames {
+				frame := models.StackFrame{Function: m[1]}
+				frame.File, frame.Line = splitLocation(m[2], ":")
+				trace.Frames = append(trace.Frames, frame)
+			}
+			continue
+		}
+		if m := javaCausedBy.FindStringSubmatch(line); m != nil {
+			trace.RootCause = clip(m[1])
+			inCause = true
+			continue
+		}
+		if javaMore.MatchString(line) || strings.HasPrefix(line, "Suppressed:") {
+			continue
+		}
+		break
+	}
+	return trace, i, true
+}
+
+func parsePython(lines []string, i int) (models.StackTrace, int, bool) {
+	trace := models.StackTrace{Language: "python"}
+	var frames []models.StackFrame
+	for i++; i < len(lines); i++ {
+		line := lines[i]
+		if m := pythonFrame.FindStringSubmatch(line); m != nil {
+			frame := models.StackFrame{File: m[1], Function: m[3]}
+			frame.Line, _ = strconv.Atoi(m[2])
+			frames = append(frames, frame)
+			continue
+		}
+		if m := pythonException.FindStringSubmatch(line); m != nil && len(frames) > 0 {
+			trace.ExceptionType, trace.Message = m[1], clip(m[2])
+			i++
+			break
+		}
+		// Source lines below the frames and the ^^^^ markers of Python 3.11+
+		if len(frames) > 0 && line != "" {
+			continue
+		}
+		break
+	}
+	if trace.ExceptionType == "" {
+		return trace, i, false
+	}
+	// Python prints the innermost call last
+	for j := len(frames) - 1; j >= 0 && len(trace.Frames) < maxFrames; j-- {
+		trace.Frames = append(trace.Frames, frames[j])
+	}
+	return trace, i, true
+}
+
+func parseGo(lines []string, i int, kind, message string) (models.StackTrace, int, bool) {
+	trace := models.StackTrace{Language: "go", ExceptionType: kind, Message: clip(message)}
+	function := ""
+	inGoroutine := false
+	for i++; i < len(lines); i++ {
+		line := lines[i]
+		switch {
+		case strings.HasPrefix(line, "goroutine ") && !inGoroutine:
+			inGoroutine = true
+		case !inGoroutine:
+			// Lines between the panic and the first goroutine, such as "[recovered]"
+			if line != "" && !strings.HasPrefix(line, "[") {
+				return trace, i, true
+			}
+		case line == "" || strings.HasPrefix(line, "goroutine "):
+			// Only the panicking goroutine is of interest
+			return trace, i, true
+		case goLocation.MatchString(line):
+			m := goLocation.FindStringSubmatch(line)
+			if function != "" && len(trace.Frames) < maxFrames {
+				n, _ := strconv.Atoi(m[2])
+				trace.Frames = append(trace.Frames, models.StackFrame{Function: function, File: m[1], Line: n})
+			}
+			function = ""
+		default:
+			if m := goFunction.FindStringSubmatch(line); m != nil {
+				function = m[1]
+			} else if !strings.HasPrefix(line, "created by ") {
+				return trace, i, true
+			}
+		}
+	}
+	return trace, i, true
+}
+
+func parseJS(lines []string, i int, exceptionType, message string) (models.StackTrace, int, bool) {
+	trace := models.StackTrace{Language: "javascript", ExceptionType: exceptionType, Message: clip(message)}
+	for ; i < len(lines); i++ {
+		m := jsFrame.FindStringSubmatch(lines[i])
+		if m == nil {
+			break
+		}
+		if len(trace.Frames) < maxFrames {
+			n, _ := strconv.Atoi(m[3])
+			trace.Frames = append(trace.Frames, models.StackFrame{Function: m[1], File: m[2], Line: n})
+		}
+	}
+	return trace, i, true
+}
+
+// parseLogLine recognizes an error or warning log line in plain, logfmt or JSON format.
+func parseLogLine(line string) (models.LogLine, bool) {
+	if strings.HasPrefix(line, "{") {
+		var entry map[string]interface{}
+		if json.Unmarshal([]byte(line), &entry) != nil {
+			return models.LogLine{}, false
+		}
+		level := normalizeLevel(firstString(entry, "level", "severity", "lvl"))
+		if level == "" {
+			return models.LogLine{}, false
+		}
+		return models.LogLine{
+			Level:     level,
+			Message:   clip(firstString(entry, "msg", "message", "error")),
+			Timestamp: firstString(entry, "ts", "time", "timestamp", "@timestamp"),
+		}, true
+	}
+
+	if m := logfmtPattern.FindStringSubmatch(line); m != nil {
+		message := line
+		if mm := logfmtMessage.FindStringSubmatch(line); mm != nil {
+			message = mm[1] + mm[2]
+		}
+		return models.LogLine{Level: normalizeLevel(m[1]), Message: clip(message), Timestamp: timestampPattern.FindString(line)}, true
+	}
+
+	loc := levelPattern.FindStringSubmatchIndex(line)
+	if loc == nil {
+		return models.LogLine{}, false
+	}
+	message := strings.TrimLeft(line[loc[1]:], " :]-")
+	message = loggerPrefix.ReplaceAllString(message, "")
+	if message == "" {
+		return models.LogLine{}, false
+	}
+	return models.LogLine{
+		Level:     normalizeLevel(line[loc[2]:loc[3]]),
+		Message:   clip(message),
+		Timestamp: timestampPattern.FindString(line),
+	}, true
+}
+
+// addTrace records a trace, counting it as a repeat when the same exception was thrown at the same place.
+func (c *collector) addTrace(trace models.StackTrace) {
+	for i, t := range c.traces {
+		if t.Language == trace.Language && t.ExceptionType == trace.ExceptionType &&
+			fingerprint(t.Message) == fingerprint(trace.Message) && topFrame(t) == topFrame(trace) {
+			c.traces[i].Occurrences++
+			return
+		}
+	}
+	if len(c.traces) < maxTraces {
+		trace.Occurrences = 1
+		c.traces = append(c.traces, trace)
+	}
+}
+
+// addLogLine records a log line, counting it as a repeat when only numbers differ.
+func (c *collector) addLogLine(line models.LogLine) {
+	for i, l := range c.logLines {
+		if l.Level == line.Level && fingerprint(l.Message) == fingerprint(line.Message) {
+			c.logLines[i].Occurrences++
+			return
+		}
+	}
+	if len(c.logLines) < maxLogLines {
+		line.Occurrences = 1
+		c.logLines = append(c.logLines, line)
+	}
+}
+
+// findTimestamp returns the timestamp on the trace's first line or the log line just before it.
+func findTimestamp(lines []string, i int) string {
+	for j := i; j >= 0 && j >= i-2; j-- {
+		if ts := timestampPattern.FindString(lines[j]); ts != "" {
+			return ts
+		}
+	}
+	return ""
+}
+
+func nextNonEmpty(lines []string, i int) int {
+	for i < len(lines) && lines[i] == "" {
+		i++
+	}
+	return i
+}
+
+// splitLocation splits "File.java:12" into file and line; locations without a line,
+// such as "Native Method", are returned as the file.
+func splitLocation(location, sep string) (string, int) {
+	idx := strings.LastIndex(location, sep)
+	if idx < 0 {
+		return location, 0
+	}
+	n, err := strconv.Atoi(location[idx+len(sep):])
+	if err != nil {
+		return location, 0
+	}
+	return location[:idx], n
+}
+
+func topFrame(t models.StackTrace) models.StackFrame {
+	if len(t.Frames) == 0 {
+		return models.StackFrame{}
+	}
+	return t.Frames[0]
+}
+
+// fingerprint replaces numbers so messages differing only in IDs or timings compare equal.
+func fingerprint(message string) string {
+	return digitsPattern.ReplaceAllString(message, "#")
+}
+
+func normalizeLevel(level string) string {
+	switch strings.ToUpper(level) {
+	case "FATAL", "CRITICAL", "SEVERE":
+		return "FATAL"
+	case "ERROR", "ERR":
+		return "ERROR"
+	case "WARN", "WARNING":
+		return "WARN"
+	}
+	return ""
+}
+
+func firstString(entry map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if s, ok := entry[key].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+func clip(s string) string {
+	s = strings.TrimSpace(s)
+	if runes := []rune(s); len(runes) > maxMessageLen {
+		return string(runes[:maxMessageLen]) + "..."
+	}
+	return s
+}
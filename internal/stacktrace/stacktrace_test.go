@@ -0,0 +1,168 @@
+package stacktrace
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tuannvm/jira-a2a/internal/models"
+)
+
+func TestParseTraces(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want models.StackTrace
+	}{
+		{
+			name: "java",
+			text: `{code:java}
+2025-01-12 08:31:02,123 ERROR [main] c.e.App - Request failed
+java.lang.IllegalStateException: boom
+	at com.example.Service.handle(Service.java:42)
+	at com.example.Controller.post(Controller.java:17)
+Caused by: java.sql.SQLException: timeout
+	at com.zaxxer.Pool.get(Pool.java:9)
+	... 12 more
+{code}`,
+			want: models.StackTrace{
+				Language:      "java",
+				ExceptionType: "java.lang.IllegalStateException",
+				Message:       "boom",
+				RootCause:     "java.sql.SQLException: timeout",
+				Frames: []models.StackFrame{
+					{Function: "com.example.Service.handle", File: "Service.java", Line: 42},
+					{Function: "com.example.Controller.post", File: "Controller.java", Line: 17},
+				},
+				Timestamp: "2025-01-12 08:31:02,123",
+			},
+		},
+		{
+			name: "python",
+			text: `Traceback (most recent call last):
+  File "app.py", line 10, in <module>
+    main()
+  File "app.py", line 5, in main
+    raise ValueError("bad input")
+ValueError: bad input`,
+			want: models.StackTrace{
+				Language:      "python",
+				ExceptionType: "ValueError",
+				Message:       "bad input",
+				Frames: []models.StackFrame{
+					{Function: "main", File: "app.py", Line: 5},
+					{Function: "<module>", File: "app.py", Line: 10},
+				},
+			},
+		},
+		{
+			name: "go",
+			text: `panic: runtime error: index out of range [3] with length 2
+
+goroutine 1 [running]:
+main.lookup(...)
+	/src/main.go:12
+main.main()
+	/src/main.go:20 +0x1d
+
+goroutine 7 [chan receive]:
+main.worker()
+	/src/worker.go:8 +0x2a
+exit status 2`,
+			want: models.StackTrace{
+				Language:      "go",
+				ExceptionType: "panic",
+				Message:       "runtime error: index out of range [3] with length 2",
+				Frames: []models.StackFrame{
+					{Function: "main.lookup", File: "/src/main.go", Line: 12},
+					{Function: "main.main", File: "/src/main.go", Line: 20},
+				},
+			},
+		},
+		{
+			name: "javascript",
+			text: `> TypeError: Cannot read properties of undefined (reading 'id')
+>     at getUser (/app/users.js:14:22)
+>     at /app/server.js:30:5`,
+			want: models.StackTrace{
+				Language:      "javascript",
+				ExceptionType: "TypeError",
+				Message:       "Cannot read properties of undefined (reading 'id')",
+				Frames: []models.StackFrame{
+					{Function: "getUser", File: "/app/users.js", Line: 14},
+					{File: "/app/server.js", Line: 30},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces, _ := Parse(tt.text, "description")
+			tt.want.Source = "description"
+			tt.want.Occurrences = 1
+			if len(traces) != 1 || !reflect.DeepEqual(traces[0], tt.want) {
+				t.Errorf("Parse = %+v\nwant %+v", traces, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLogLines(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want *models.LogLine
+	}{
+		{name: "plain", line: "2025-01-12 08:31:02 WARN [pool-1] com.example.Retry - retrying", want: &models.LogLine{Level: "WARN", Message: "retrying", Timestamp: "2025-01-12 08:31:02"}},
+		{name: "severe", line: "SEVERE: out of memory", want: &models.LogLine{Level: "FATAL", Message: "out of memory"}},
+		{name: "logfmt", line: `ts=2025-01-12T08:31:02Z level=error msg="db timeout after 30s"`, want: &models.LogLine{Level: "ERROR", Message: "db timeout after 30s", Timestamp: "2025-01-12T08:31:02Z"}},
+		{name: "json", line: `{"level":"warning","message":"slow query","time":"2025-01-12T08:31:02Z"}`, want: &models.LogLine{Level: "WARN", Message: "slow query", Timestamp: "2025-01-12T08:31:02Z"}},
+		{name: "info", line: "INFO started"},
+		{name: "json info", line: `{"level":"info","msg":"started"}`},
+		{name: "level without message", line: "ERROR"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, lines := Parse(tt.line, "description")
+			var want []models.LogLine
+			if tt.want != nil {
+				tt.want.Source, tt.want.Occurrences = "description", 1
+				want = []models.LogLine{*tt.want}
+			}
+			if !reflect.DeepEqual(lines, want) {
+				t.Errorf("Parse = %+v, want %+v", lines, want)
+			}
+		})
+	}
+}
+
+func TestExtract(t *testing.T) {
+	if got := Extract(&models.TicketAvailableTask{Description: "Login is broken"}); got != nil {
+		t.Errorf("Extract = %+v, want nil without traces", got)
+	}
+
+	jsTrace := "TypeError: x is undefined\nat render (/app/view.js:3:1)\n"
+	task := &models.TicketAvailableTask{
+		Description: "ERROR timeout after 30s\nERROR timeout after 45s\nERROR connection refused",
+		Comments:    []models.JiraComment{{Author: "Jane", Body: jsTrace + "\n" + jsTrace}},
+		Attachments: []models.Attachment{{Filename: "app.log", Content: jsTrace}},
+	}
+	got := Extract(task)
+	if got == nil {
+		t.Fatal("Extract found nothing")
+	}
+
+	wantLines := []models.LogLine{
+		{Level: "ERROR", Message: "timeout after 30s", Source: "description", Occurrences: 2},
+		{Level: "ERROR", Message: "connection refused", Source: "description", Occurrences: 1},
+	}
+	if !reflect.DeepEqual(got.LogLines, wantLines) {
+		t.Errorf("log lines = %+v\nwant %+v", got.LogLines, wantLines)
+	}
+	// Repeats are counted on the first trace found, wherever they appear
+	if len(got.StackTraces) != 1 {
+		t.Fatalf("stack traces = %+v, want 1", got.StackTraces)
+	}
+	if trace := got.StackTraces[0]; trace.Source != "comment 1 by Jane" || trace.Occurrences != 3 {
+		t.Errorf("trace source = %q, occurrences = %d", trace.Source, trace.Occurrences)
+	}
+}
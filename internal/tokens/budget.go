@@ -0,0 +1,51 @@
+package tokens
+
+// Section is a part of a prompt competing for the token budget.
+type Section struct {
+	Name   string
+	Tokens int // Tokens the section needs to be included in full
+	Weight int // Relative share of the budget when sections have to be cut
+}
+
+// Allocate divides budget tokens between sections. Sections needing less than their
+// weighted share are included in full and what they leave over is shared among the
+// rest, so the budget is cut only from the sections that are too big. The returned
+// allocations are in the order of sections.
+func Allocate(sections []Section, budget int) []int {
+	alloc := make([]int, len(sections))
+	remaining := max(budget, 0)
+	open := make([]int, 0, len(sections))
+	for i, s := range sections {
+		if s.Tokens > 0 {
+			open = append(open, i)
+		}
+	}
+
+	for len(open) > 0 {
+		totalWeight := 0
+		for _, i := range open {
+			totalWeight += max(sections[i].Weight, 1)
+		}
+		var rest []int
+		for _, i := range open {
+			share := remaining * max(sections[i].Weight, 1) / totalWeight
+			if sections[i].Tokens <= share {
+				alloc[i] = sections[i].Tokens
+				continue
+			}
+			rest = append(rest, i)
+		}
+		if len(rest) == len(open) {
+			// Every remaining section is too big; each gets its share
+			for _, i := range open {
+				alloc[i] = remaining * max(sections[i].Weight, 1) / totalWeight
+			}
+			break
+		}
+		for _, i := range open {
+			remaining -= alloc[i]
+		}
+		open = rest
+	}
+	return alloc
+}
@@ -0,0 +1,39 @@
+package tokens
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name     string
+		sections []Section
+		budget   int
+		want     []int
+	}{
+		{name: "everything fits", sections: []Section{{Tokens: 10}, {Tokens: 20}}, budget: 100, want: []int{10, 20}},
+		{name: "leftover goes to big sections", sections: []Section{{Tokens: 10, Weight: 1}, {Tokens: 200, Weight: 1}}, budget: 100, want: []int{10, 90}},
+		{name: "cut by weight", sections: []Section{{Tokens: 1000, Weight: 3}, {Tokens: 1000, Weight: 1}}, budget: 100, want: []int{75, 25}},
+		{name: "leftover shared by weight", sections: []Section{{Tokens: 10, Weight: 2}, {Tokens: 500, Weight: 3}, {Tokens: 500, Weight: 1}}, budget: 130, want: []int{10, 90, 30}},
+		{name: "zero weight counts as one", sections: []Section{{Tokens: 100}, {Tokens: 100, Weight: 1}}, budget: 50, want: []int{25, 25}},
+		{name: "empty section", sections: []Section{{Tokens: 0, Weight: 5}, {Tokens: 50, Weight: 1}}, budget: 40, want: []int{0, 40}},
+		{name: "negative budget", sections: []Section{{Tokens: 10}}, budget: -5, want: []int{0}},
+		{name: "no sections", budget: 100, want: []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Allocate(tt.sections, tt.budget)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Allocate = %v, want %v", got, tt.want)
+			}
+			total := 0
+			for _, n := range got {
+				total += n
+			}
+			if total > max(tt.budget, 0) {
+				t.Errorf("allocated %d tokens, budget is %d", total, tt.budget)
+			}
+		})
+	}
+}
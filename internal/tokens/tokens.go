@@ -0,0 +1,171 @@
+// Package tokens counts LLM tokens and divides a prompt's token budget between its sections.
+package tokens
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkoukk/tiktoken-go"
+	log "github.com/tuannvm/jira-a2a/internal/logging"
+)
+
+// charsPerToken approximates the token count when no tokenizer is available.
+const charsPerToken = 4
+
+// defaultContextWindow is assumed for models missing from contextWindows.
+const defaultContextWindow = 8192
+
+// contextWindows lists context sizes by model name prefix, most specific prefix first.
+var contextWindows = []struct {
+	prefix string
+	tokens int
+}{
+	{"gpt-4.1", 1047576},
+	{"gpt-4o", 128000},
+	{"gpt-4-turbo", 128000},
+	{"gpt-4-32k", 32768},
+	{"gpt-4", 8192},
+	{"gpt-3.5-turbo", 16385},
+	{"o1-mini", 128000},
+	{"o1", 200000},
+	{"o3", 200000},
+	{"o4", 200000},
+	{"claude", 200000},
+}
+
+// ContextWindow returns the number of tokens a model accepts for prompt and reply together.
+func ContextWindow(model string) int {
+	model = strings.ToLower(model)
+	for _, w := range contextWindows {
+		if strings.HasPrefix(model, w.prefix) {
+			return w.tokens
+		}
+	}
+	return defaultContextWindow
+}
+
+// Counter counts tokens the way a model's tokenizer does. It is safe for concurrent use.
+type Counter struct {
+	enc *tiktoken.Tiktoken // nil when the tokenizer could not be loaded
+}
+
+// NewCounter creates a counter for the model. Models without a known tiktoken encoding,
+// such as Anthropic's, are counted with cl100k_base, which is close enough for budgeting.
+// If no encoding can be loaded (tiktoken downloads them on first use), tokens are
+// approximated from the text length.
+func NewCounter(model string) *Counter {
+	enc, err := tiktoken.EncodingForModel(model)
+	if err != nil {
+		enc, err = tiktoken.GetEncoding(tiktoken.MODEL_CL100K_BASE)
+	}
+	if err != nil {
+		log.Warnf("Failed to load tokenizer for %s, approximating token counts: %v", model, err)
+		return &Counter{}
+	}
+	return &Counter{enc: enc}
+}
+
+// Count returns the number of tokens in text.
+func (c *Counter) Count(text string) int {
+	if c.enc == nil {
+		return (utf8.RuneCountInString(text) + charsPerToken - 1) / charsPerToken
+	}
+	return len(c.enc.Encode(text, nil, nil))
+}
+
+// Truncate shortens text to at most maxTokens tokens. It keeps the beginning and the end,
+// where descriptions and logs tend to carry the most information, and marks the cut.
+func (c *Counter) Truncate(text string, maxTokens int) string {
+	total := c.Count(text)
+	if total <= maxTokens {
+		return text
+	}
+	marker := fmt.Sprintf("\n[... %d tokens omitted ...]\n", total-maxTokens)
+	keep := maxTokens - c.Count(marker)
+	if keep <= 0 {
+		return c.head(text, maxTokens)
+	}
+	head := keep * 2 / 3
+	return c.head(text, head) + marker + c.tail(text, keep-head)
+}
+
+// Split breaks text into chunks of at most maxTokens tokens, preferring line boundaries.
+func (c *Counter) Split(text string, maxTokens int) []string {
+	var chunks []string
+	var current strings.Builder
+	size := 0
+	flush := func() {
+		if current.Len() > 0 {
+			chunks = append(chunks, current.String())
+			current.Reset()
+			size = 0
+		}
+	}
+	for _, line := range strings.SplitAfter(text, "\n") {
+		n := c.Count(line)
+		if size+n > maxTokens {
+			flush()
+		}
+		// A single line longer than a chunk is cut into pieces
+		for n > maxTokens {
+			piece := c.head(line, maxTokens)
+			if piece == "" {
+				break
+			}
+			chunks = append(chunks, piece)
+			line = line[len(piece):]
+			n = c.Count(line)
+		}
+		current.WriteString(line)
+		size += n
+	}
+	flush()
+	return chunks
+}
+
+// head returns the longest prefix of text with at most n tokens.
+func (c *Counter) head(text string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	if c.enc == nil {
+		return string([]rune(text)[:min(n*charsPerToken, utf8.RuneCountInString(text))])
+	}
+	ids := c.enc.Encode(text, nil, nil)
+	if len(ids) <= n {
+		return text
+	}
+	// A token boundary may fall inside a multi-byte character
+	return trimInvalid(c.enc.Decode(ids[:n]), false)
+}
+
+// tail returns the longest suffix of text with at most n tokens.
+func (c *Counter) tail(text string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	if c.enc == nil {
+		runes := []rune(text)
+		return string(runes[max(0, len(runes)-n*charsPerToken):])
+	}
+	ids := c.enc.Encode(text, nil, nil)
+	if len(ids) <= n {
+		return text
+	}
+	return trimInvalid(c.enc.Decode(ids[len(ids)-n:]), true)
+}
+
+// trimInvalid drops the partial character left at the end (or start) of decoded text.
+func trimInvalid(s string, fromStart bool) string {
+	if fromStart {
+		for len(s) > 0 && !utf8.RuneStart(s[0]) {
+			s = s[1:]
+		}
+		return s
+	}
+	for len(s) > 0 && !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,81 @@
+package tokens
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// The zero counter approximates 4 characters per token, so no tokenizer is downloaded
+
+func TestContextWindow(t *testing.T) {
+	tests := []struct {
+		model string
+		want  int
+	}{
+		{model: "gpt-4o-mini", want: 128000},
+		{model: "GPT-4-0613", want: 8192},
+		{model: "gpt-4.1-nano", want: 1047576},
+		{model: "claude-3-5-sonnet-latest", want: 200000},
+		{model: "llama3", want: defaultContextWindow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			if got := ContextWindow(tt.model); got != tt.want {
+				t.Errorf("ContextWindow = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		maxTokens int
+		want      []string
+	}{
+		{name: "single chunk", text: "aaaa\nbbbb\n", maxTokens: 10, want: []string{"aaaa\nbbbb\n"}},
+		{name: "at line boundaries", text: "aaaa\nbbbb\ncccc\n", maxTokens: 4, want: []string{"aaaa\nbbbb\n", "cccc\n"}},
+		{name: "long line is cut", text: "abcdefghijklmnopqrst", maxTokens: 2, want: []string{"abcdefgh", "ijklmnop", "qrst"}},
+		{name: "long line after short one", text: "ab\nabcdefghijkl", maxTokens: 2, want: []string{"ab\n", "abcdefgh", "ijkl"}},
+		{name: "multi-byte characters", text: "ééééééééé", maxTokens: 2, want: []string{"éééééééé", "é"}},
+		{name: "empty", text: "", maxTokens: 2},
+	}
+	counter := &Counter{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := counter.Split(tt.text, tt.maxTokens)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Split = %q, want %q", got, tt.want)
+			}
+			if strings.Join(got, "") != tt.text {
+				t.Errorf("chunks do not add up to the text: %q", got)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	counter := &Counter{}
+	text := strings.Repeat("a", 200) + strings.Repeat("z", 200) // 100 tokens
+
+	if got := counter.Truncate(text, 100); got != text {
+		t.Errorf("Truncate shortened text that fits: %q", got)
+	}
+
+	got := counter.Truncate(text, 40)
+	// 8 tokens of marker leave 32: 21 for the head and 11 for the tail
+	want := strings.Repeat("a", 84) + "\n[... 60 tokens omitted ...]\n" + strings.Repeat("z", 44)
+	if got != want {
+		t.Errorf("Truncate = %q, want %q", got, want)
+	}
+	if n := counter.Count(got); n > 40 {
+		t.Errorf("truncated text has %d tokens, want at most 40", n)
+	}
+
+	// Too small for the marker, only the beginning is kept
+	if got := counter.Truncate(text, 2); got != "aaaaaaaa" {
+		t.Errorf("Truncate = %q, want the first 2 tokens", got)
+	}
+}